@@ -25,6 +25,10 @@ Built packages are stored in the "build/" folder located at the root folder of t
 
 Built packages are served up by the Elastic Package Registry running locally (see "elastic-package stack"). If you want a local package to be served up by the local Elastic Package Registry, make sure to build that package first using "elastic-package build".
 
+Packages are built incrementally: if the contents of the package haven't changed since the last build, the package is not built again. The hashes of the last builds are stored in the "build/cache" folder. Use the --force flag to always build the package.
+
+To inspect the fields imported from external dependencies, like ECS, use the --dump-injected-fields flag. The fields files modified by the injection are written, before and after the injection, to the "original" and "injected" directories under "build/injected-fields/<package>/<version>".
+
 Built packages can also be published to the global package registry service.
 
 For details on how to enable dependency management, see the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/dependency_management.md).`
@@ -40,6 +44,8 @@ func setupBuildCommand() *cobraext.Command {
 	cmd.Flags().Bool(cobraext.BuildZipFlagName, true, cobraext.BuildZipFlagDescription)
 	cmd.Flags().Bool(cobraext.SignPackageFlagName, false, cobraext.SignPackageFlagDescription)
 	cmd.Flags().Bool(cobraext.BuildSkipValidationFlagName, false, cobraext.BuildSkipValidationFlagDescription)
+	cmd.Flags().Bool(cobraext.BuildForceFlagName, false, cobraext.BuildForceFlagDescription)
+	cmd.Flags().Bool(cobraext.BuildDumpInjectedFieldsFlagName, false, cobraext.BuildDumpInjectedFieldsFlagDescription)
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
 
@@ -49,6 +55,8 @@ func buildCommandAction(cmd *cobra.Command, args []string) error {
 	createZip, _ := cmd.Flags().GetBool(cobraext.BuildZipFlagName)
 	signPackage, _ := cmd.Flags().GetBool(cobraext.SignPackageFlagName)
 	skipValidation, _ := cmd.Flags().GetBool(cobraext.BuildSkipValidationFlagName)
+	force, _ := cmd.Flags().GetBool(cobraext.BuildForceFlagName)
+	dumpInjectedFields, _ := cmd.Flags().GetBool(cobraext.BuildDumpInjectedFieldsFlagName)
 
 	if signPackage && !createZip {
 		return errors.New("can't sign the unzipped package, please use also the --zip switch")
@@ -83,16 +91,26 @@ func buildCommandAction(cmd *cobra.Command, args []string) error {
 	}
 
 	target, err := builder.BuildPackage(builder.BuildOptions{
-		PackageRoot:    packageRoot,
-		CreateZip:      createZip,
-		SignPackage:    signPackage,
-		SkipValidation: skipValidation,
+		PackageRoot:        packageRoot,
+		CreateZip:          createZip,
+		SignPackage:        signPackage,
+		SkipValidation:     skipValidation,
+		Incremental:        !force,
+		DumpInjectedFields: dumpInjectedFields,
 	})
 	if err != nil {
 		return fmt.Errorf("building package failed: %w", err)
 	}
 	cmd.Printf("Package built: %s\n", target)
 
+	if dumpInjectedFields {
+		injectedFieldsDir, err := builder.InjectedFieldsDirectory(packageRoot)
+		if err != nil {
+			return fmt.Errorf("can't locate directory for injected fields: %w", err)
+		}
+		cmd.Printf("Injected fields dumped: %s\n", injectedFieldsDir)
+	}
+
 	cmd.Println("Done")
 	return nil
 }
@@ -6,6 +6,8 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -14,7 +16,36 @@ import (
 
 const checkLongDescription = `Use this command to verify if the package is correct in terms of formatting, validation and building.
 
-It will execute the lint and build commands all at once, in that order.`
+It will execute the lint and build commands all at once, in that order.
+
+Additional checks are available as subcommands.`
+
+// Values of the flags that select how the problems found by checks are reported.
+const (
+	reportIgnore = "ignore"
+	reportWarn   = "warn"
+	reportError  = "error"
+)
+
+var reportModes = []string{reportIgnore, reportWarn, reportError}
+
+// addReportModeFlag adds a flag to select how the problems found by a check are reported. The
+// description of the flag must include a placeholder for the list of possible values.
+func addReportModeFlag(cmd *cobra.Command, name, defaultValue, description string) {
+	cmd.Flags().String(name, defaultValue, fmt.Sprintf(description, strings.Join(reportModes, ", ")))
+}
+
+// getReportModeFlag returns the value of a flag added with addReportModeFlag.
+func getReportModeFlag(cmd *cobra.Command, name string) (string, error) {
+	mode, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return "", cobraext.FlagParsingError(err, name)
+	}
+	if !slices.Contains(reportModes, mode) {
+		return "", cobraext.FlagParsingError(fmt.Errorf("unsupported value %q", mode), name)
+	}
+	return mode, nil
+}
 
 func setupCheckCommand() *cobraext.Command {
 	cmd := &cobra.Command{
@@ -35,5 +66,15 @@ func setupCheckCommand() *cobraext.Command {
 	}
 	cmd.PersistentFlags().BoolP(cobraext.FailFastFlagName, "f", true, cobraext.FailFastFlagDescription)
 
+	cmd.AddCommand(setupCheckChangelogCommand())
+	cmd.AddCommand(setupCheckDashboardsCommand())
+	cmd.AddCommand(setupCheckDependenciesCommand())
+	cmd.AddCommand(setupCheckDocsCommand())
+	cmd.AddCommand(setupCheckFieldsCommand())
+	cmd.AddCommand(setupCheckDeployCommand())
+	cmd.AddCommand(setupCheckLifecycleCommand())
+	cmd.AddCommand(setupCheckSpecCommand())
+	cmd.AddCommand(setupCheckTestCoverageCommand())
+
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
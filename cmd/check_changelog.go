@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/packages/changelog"
+)
+
+const checkChangelogLongDescription = `Use this command to verify that the changelog of the package is in sync with its manifest.
+
+The latest entry of the changelog.yml file must match the version in the manifest.yml file. Versions must be valid semantic versions sorted from newest to oldest, and each of them must contain changes with a description, a link and a valid type (bugfix, enhancement or breaking-change).`
+
+func setupCheckChangelogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Check the changelog of the package",
+		Long:  checkChangelogLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkChangelogCommandAction,
+	}
+
+	return cmd
+}
+
+func checkChangelogCommandAction(cmd *cobra.Command, args []string) error {
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	revisions, err := changelog.ReadChangelogFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("reading package changelog failed: %w", err)
+	}
+
+	err = changelog.Validate(revisions, manifest.Version)
+	if err != nil {
+		return fmt.Errorf("invalid changelog (path: %s):\n%w", changelog.PackageChangelogFile, err)
+	}
+
+	cmd.Println("Done")
+	return nil
+}
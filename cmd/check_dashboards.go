@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const checkDashboardsLongDescription = `Use this command to verify that the fields referenced by Kibana saved objects are defined in the package.
+
+The dashboards, visualizations and other saved objects found under the kibana directory of the package are parsed, and the fields they reference are looked up in the fields defined by the package and its data streams, including the ones imported from ECS. Saved objects referencing undefined fields are reported.`
+
+func setupCheckDashboardsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboards",
+		Short: "Check the fields referenced by Kibana dashboards",
+		Long:  checkDashboardsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkDashboardsCommandAction,
+	}
+
+	return cmd
+}
+
+func checkDashboardsCommandAction(cmd *cobra.Command, args []string) error {
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	objects, err := fields.ReadSavedObjectsFieldReferences(packageRoot)
+	if err != nil {
+		return fmt.Errorf("reading saved objects failed: %w", err)
+	}
+	if len(objects) == 0 {
+		cmd.Println("No Kibana saved objects found.")
+		return nil
+	}
+
+	schema, err := loadPackageFieldsSchema(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	missing := fields.FindMissingFieldReferences(objects, schema)
+	if len(missing) > 0 {
+		for _, reference := range missing {
+			cmd.Println(reference.String())
+		}
+		return fmt.Errorf("found %d references to undefined fields in Kibana saved objects", len(missing))
+	}
+
+	cmd.Println("Done")
+	return nil
+}
+
+// loadPackageFieldsSchema loads the fields defined in the package and in all its data streams.
+func loadPackageFieldsSchema(packageRoot string) ([]fields.FieldDefinition, error) {
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	dataStreams, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data streams: %w", err)
+	}
+
+	var schema []fields.FieldDefinition
+	for _, fieldsParentDir := range append([]string{packageRoot}, dataStreams...) {
+		validator, err := fields.CreateValidatorForDirectory(fieldsParentDir,
+			fields.WithSpecVersion(manifest.SpecVersion),
+			fields.WithEnabledImportAllECSSChema(true),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("loading fields failed (path: %s): %w", fieldsParentDir, err)
+		}
+		schema = append(schema, validator.Schema...)
+	}
+	return schema, nil
+}
@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const checkDependenciesLongDescription = `Use this command to verify that the dependencies of the package can be resolved.
+
+Dependencies declared in the "_dev/build/build.yml" file are resolved, using local files or cached copies when available. Fields files are also checked, to ensure that all their external fields are defined in the declared dependencies. Each unresolved dependency is reported with the reason.`
+
+func setupCheckDependenciesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dependencies",
+		Short: "Check that the dependencies of the package can be resolved",
+		Long:  checkDependenciesLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkDependenciesCommandAction,
+	}
+
+	return cmd
+}
+
+func checkDependenciesCommandAction(cmd *cobra.Command, args []string) error {
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	problems, err := fields.FindDependencyProblems(packageRoot)
+	if err != nil {
+		return fmt.Errorf("checking dependencies failed: %w", err)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			cmd.Println(problem.String())
+		}
+		return fmt.Errorf("found %d unresolved dependencies", len(problems))
+	}
+
+	cmd.Println("Done")
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/system"
+)
+
+const checkDeployLongDescription = `Use this command to validate the service deployer definitions of the package before running tests.
+
+With the --terraform flag, the definitions of the Terraform service deployer, found in the package or in any of its data streams, are validated with "terraform validate". Definitions are initialized without backend, so no credentials are needed. The terraform binary needs to be available in the PATH.`
+
+func setupCheckDeployCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Check the service deployer definitions",
+		Long:  checkDeployLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkDeployCommandAction,
+	}
+	cmd.Flags().Bool(cobraext.TerraformFlagName, false, cobraext.TerraformFlagDescription)
+
+	return cmd
+}
+
+func checkDeployCommandAction(cmd *cobra.Command, args []string) error {
+	terraform, err := cmd.Flags().GetBool(cobraext.TerraformFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TerraformFlagName)
+	}
+	if !terraform {
+		return fmt.Errorf("no service deployer selected to check (available: --%s)", cobraext.TerraformFlagName)
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	definitions, err := servicedeployer.FindTerraformDefinitions(packageRoot, system.DevDeployDir)
+	if err != nil {
+		return fmt.Errorf("looking for terraform definitions failed: %w", err)
+	}
+	if len(definitions) == 0 {
+		cmd.Println("No Terraform service deployer definitions found.")
+		return nil
+	}
+
+	var errs []error
+	for _, definitionsDir := range definitions {
+		cmd.Printf("Validating Terraform definitions in %s\n", definitionsDir)
+		err := servicedeployer.ValidateTerraformDefinitions(cmd.Context(), definitionsDir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid terraform definitions (path: %s): %w", definitionsDir, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	cmd.Println("Done")
+	return nil
+}
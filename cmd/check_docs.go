@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/docs"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const checkDocsLongDescription = `Use this command to verify the documentation files of the package.
+
+It checks that the README files are up-to-date with their templates. With the --links flag, links in the documentation files are also verified: anchors must match headings of the linked files, and linked files must exist in the package. External links are only verified if the --external-links flag is also used, as it requires network access; they are reported as broken if they are not found.
+
+The --missing-sample-events flag controls how data streams without a sample event are reported. Sample events are included in the generated documentation, so packages are expected to provide one for each data stream. Possible values are "ignore" (default), "warn" and "error".`
+
+func setupCheckDocsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Check the documentation files of the package",
+		Long:  checkDocsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkDocsCommandAction,
+	}
+	cmd.Flags().Bool(cobraext.LinksFlagName, false, cobraext.LinksFlagDescription)
+	cmd.Flags().Bool(cobraext.ExternalLinksFlagName, false, cobraext.ExternalLinksFlagDescription)
+	addReportModeFlag(cmd, cobraext.MissingSampleEventsFlagName, reportIgnore, cobraext.MissingSampleEventsFlagDescription)
+
+	return cmd
+}
+
+func checkDocsCommandAction(cmd *cobra.Command, args []string) error {
+	checkLinks, err := cmd.Flags().GetBool(cobraext.LinksFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.LinksFlagName)
+	}
+	checkExternalLinks, err := cmd.Flags().GetBool(cobraext.ExternalLinksFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ExternalLinksFlagName)
+	}
+	if checkExternalLinks && !checkLinks {
+		return cobraext.FlagParsingError(fmt.Errorf("flag requires --%s", cobraext.LinksFlagName), cobraext.ExternalLinksFlagName)
+	}
+	missingSampleEvents, err := getReportModeFlag(cmd, cobraext.MissingSampleEventsFlagName)
+	if err != nil {
+		return err
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	readmeFiles, err := docs.AreReadmesUpToDate()
+	if err != nil {
+		for _, f := range readmeFiles {
+			if !f.UpToDate {
+				cmd.Printf("%s is outdated. Rebuild the package with 'elastic-package build'\n%s", f.FileName, f.Diff)
+			}
+			if f.Error != nil {
+				cmd.Printf("check if %s is up-to-date failed: %s\n", f.FileName, f.Error)
+			}
+		}
+		return fmt.Errorf("checking readme files are up-to-date failed: %w", err)
+	}
+
+	if checkLinks {
+		broken, err := docs.CheckLinks(cmd.Context(), packageRoot, docs.LinksCheckOptions{External: checkExternalLinks})
+		if err != nil {
+			return fmt.Errorf("checking links failed: %w", err)
+		}
+		if len(broken) > 0 {
+			for _, link := range broken {
+				cmd.Println(link.String())
+			}
+			return fmt.Errorf("found %d broken links", len(broken))
+		}
+	}
+
+	if missingSampleEvents != reportIgnore {
+		missing, err := docs.DataStreamsWithoutSampleEvent(packageRoot)
+		if err != nil {
+			return fmt.Errorf("checking sample events failed: %w", err)
+		}
+		for _, dataStream := range missing {
+			cmd.Printf("Data stream %q has no sample event\n", dataStream)
+		}
+		if missingSampleEvents == reportError && len(missing) > 0 {
+			return fmt.Errorf("found %d data streams without sample events", len(missing))
+		}
+	}
+
+	cmd.Println("Done")
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const checkFieldsLongDescription = `Use this command to verify the field definitions of the package.
+
+Without flags, it checks that all the fields files of the package and its data streams can be parsed.
+
+The --lint flag additionally enforces style rules in field definitions, reporting each violation with the file where it is found:
+- description: all fields have a description, except groups and external fields.
+- metric-unit: fields with a metric type declare a unit.
+- allowed-values: keyword fields with expected values declare allowed values.
+
+The rules to enforce can be selected with the --lint-rules flag, all of them are enforced by default.`
+
+func setupCheckFieldsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fields",
+		Short: "Check the field definitions of the package",
+		Long:  checkFieldsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkFieldsCommandAction,
+	}
+	cmd.Flags().Bool(cobraext.LintFlagName, false, cobraext.LintFlagDescription)
+	cmd.Flags().StringSlice(cobraext.LintRulesFlagName, fields.LintRules,
+		fmt.Sprintf(cobraext.LintRulesFlagDescription, strings.Join(fields.LintRules, ", ")))
+
+	return cmd
+}
+
+func checkFieldsCommandAction(cmd *cobra.Command, args []string) error {
+	lint, err := cmd.Flags().GetBool(cobraext.LintFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.LintFlagName)
+	}
+	var rules []string
+	if lint {
+		rules, err = cmd.Flags().GetStringSlice(cobraext.LintRulesFlagName)
+		if err != nil {
+			return cobraext.FlagParsingError(err, cobraext.LintRulesFlagName)
+		}
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	violations, err := fields.LintPackageFields(packageRoot, rules)
+	if err != nil {
+		return fmt.Errorf("checking fields failed: %w", err)
+	}
+	for _, violation := range violations {
+		cmd.Println(violation.String())
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d problems in field definitions", len(violations))
+	}
+
+	cmd.Println("Done")
+	return nil
+}
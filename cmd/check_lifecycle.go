@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const checkLifecycleLongDescription = `Use this command to verify the lifecycle policies referenced by the data streams of the package.
+
+ILM policies referenced with "ilm_policy" in data stream manifests must be defined in the "elasticsearch/ilm" directory of the data stream, as required by the package spec: their names must start with "<type>-<package>.<data stream>-", followed by the name of the JSON file with the definition. ILM policy definitions must contain at least one phase, and data stream lifecycle (DLM) definitions in "lifecycle.yml" files must have a valid data retention. Unresolved references and invalid definitions are reported.`
+
+func setupCheckLifecycleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lifecycle",
+		Short: "Check the lifecycle policies of the package",
+		Long:  checkLifecycleLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkLifecycleCommandAction,
+	}
+
+	return cmd
+}
+
+func checkLifecycleCommandAction(cmd *cobra.Command, args []string) error {
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	problems, err := packages.FindLifecycleProblems(packageRoot)
+	if err != nil {
+		return fmt.Errorf("checking lifecycle policies failed: %w", err)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			cmd.Println(problem.String())
+		}
+		return fmt.Errorf("found %d problems in lifecycle policies", len(problems))
+	}
+
+	cmd.Println("Done")
+	return nil
+}
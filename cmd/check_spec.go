@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/validation"
+)
+
+const checkSpecLongDescription = `Use this command to validate the source of the package using the package specification (see: https://github.com/elastic/package-spec).
+
+The package is validated directly from its source tree, without building it, so it gives faster feedback than the validation done by the build command. Errors are reported with the paths of the source files, relative to the package root. Errors filtered in the validation.yml file of the package are not reported.`
+
+func setupCheckSpecCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spec",
+		Short: "Validate the source of the package against the package spec",
+		Long:  checkSpecLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkSpecCommandAction,
+	}
+
+	return cmd
+}
+
+func checkSpecCommandAction(cmd *cobra.Command, args []string) error {
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	errs, skipped := validation.ValidateAndFilterFromPath(packageRoot)
+	if skipped != nil {
+		logger.Infof("Skipped errors: %v", skipped)
+	}
+	if errs != nil {
+		descriptions := validation.SourceErrors(packageRoot, errs)
+		for _, description := range descriptions {
+			cmd.Println(description)
+		}
+		return fmt.Errorf("found %d errors validating the package against the spec", len(descriptions))
+	}
+
+	cmd.Println("Done")
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+const checkTestCoverageLongDescription = `Use this command to verify that all the data streams of the package have tests.
+
+Data streams are considered tested if they have system test configurations or pipeline test cases. Static tests are not considered, as they don't ingest any data.
+
+The --missing-tests flag controls how data streams without tests are reported. Possible values are "ignore", "warn" (default) and "error".`
+
+func setupCheckTestCoverageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test-coverage",
+		Short: "Check that all data streams of the package have tests",
+		Long:  checkTestCoverageLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkTestCoverageCommandAction,
+	}
+	addReportModeFlag(cmd, cobraext.MissingTestsFlagName, reportWarn, cobraext.MissingTestsFlagDescription)
+
+	return cmd
+}
+
+func checkTestCoverageCommandAction(cmd *cobra.Command, args []string) error {
+	missingTests, err := getReportModeFlag(cmd, cobraext.MissingTestsFlagName)
+	if err != nil {
+		return err
+	}
+	if missingTests == reportIgnore {
+		cmd.Println("Done")
+		return nil
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	untested, err := testrunner.DataStreamsWithoutTests(packageRoot)
+	if err != nil {
+		return fmt.Errorf("checking tests failed: %w", err)
+	}
+	for _, dataStream := range untested {
+		cmd.Printf("Data stream %q has no system or pipeline tests\n", dataStream)
+	}
+	if missingTests == reportError && len(untested) > 0 {
+		return fmt.Errorf("found %d data streams without tests", len(untested))
+	}
+
+	cmd.Println("Done")
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+const diffLongDescription = `Use this command to compare two versions of a package.`
+
+const diffFieldsLongDescription = `Use this command to compare the field schemas of two built packages.
+
+The command receives the paths to the zip files of the old and the new versions of the package, and reports the fields that have been added, removed or whose type has changed, grouped by data stream. Fields defined at the package level are reported separately.`
+
+func setupDiffCommand() *cobraext.Command {
+	diffFieldsCmd := &cobra.Command{
+		Use:   "fields <old package zip> <new package zip>",
+		Short: "Compare the fields of two package versions",
+		Long:  diffFieldsLongDescription,
+		Args:  cobra.ExactArgs(2),
+		RunE:  diffFieldsCommandAction,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare package versions",
+		Long:  diffLongDescription,
+	}
+	cmd.AddCommand(diffFieldsCmd)
+
+	return cobraext.NewCommand(cmd, cobraext.ContextGlobal)
+}
+
+func diffFieldsCommandAction(cmd *cobra.Command, args []string) error {
+	oldFields, err := fields.LoadFieldsFromZipPackage(args[0])
+	if err != nil {
+		return fmt.Errorf("reading fields of old package failed: %w", err)
+	}
+	newFields, err := fields.LoadFieldsFromZipPackage(args[1])
+	if err != nil {
+		return fmt.Errorf("reading fields of new package failed: %w", err)
+	}
+
+	diffs := fields.DiffPackageFields(oldFields, newFields)
+	if len(diffs) == 0 {
+		cmd.Println("No differences found in fields.")
+		return nil
+	}
+
+	for _, diff := range diffs {
+		if diff.DataStream == "" {
+			cmd.Println("Package fields:")
+		} else {
+			cmd.Printf("Data stream %s:\n", diff.DataStream)
+		}
+		for _, field := range diff.Added {
+			cmd.Printf("  + %s (%s)\n", field.Name, field.NewType)
+		}
+		for _, field := range diff.Removed {
+			cmd.Printf("  - %s (%s)\n", field.Name, field.OldType)
+		}
+		for _, field := range diff.Retyped {
+			cmd.Printf("  ~ %s (%s -> %s)\n", field.Name, field.OldType, field.NewType)
+		}
+	}
+	return nil
+}
@@ -7,11 +7,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
 	"github.com/elastic/elastic-package/internal/docs"
+	"github.com/elastic/elastic-package/internal/fields"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/validation"
@@ -19,7 +21,11 @@ import (
 
 const lintLongDescription = `Use this command to validate the contents of a package using the package specification (see: https://github.com/elastic/package-spec).
 
-The command ensures that the package is aligned with the package spec and the README file is up-to-date with its template (if present).`
+The command ensures that the package is aligned with the package spec and the README file is up-to-date with its template (if present). It also verifies that the sample events referenced by the README templates exist, that secret variables don't have default values that would leak into policies, that the variables referenced in agent templates are declared, that the data streams listed in policy templates exist, that transforms define their fleet_transform_version, and that multifields of the package fields are consistent with the ones expected by ECS.
+
+Fields set by grok, dissect and set processors of ingest pipelines that are not defined in the package fields or in ECS are reported as warnings, as they may be ignored when indexed.
+
+In time series data streams, fields declared as dimensions must have the types allowed for dimensions by the package spec, and they can't be metrics.`
 
 func setupLintCommand() *cobraext.Command {
 	cmd := &cobra.Command{
@@ -28,9 +34,17 @@ func setupLintCommand() *cobraext.Command {
 		Long:  lintLongDescription,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var ecsSchema lintECSSchema
 			err := cobraext.ComposeCommandActions(cmd, args,
 				lintCommandAction,
+				checkSecretVariablesCommandAction,
+				checkTemplateVariablesCommandAction,
+				checkPolicyTemplateDataStreamsCommandAction,
+				checkTransformVersionsCommandAction,
 				validateSourceCommandAction,
+				checkMultiFieldsCommandAction(&ecsSchema),
+				checkPipelineFieldsCommandAction(&ecsSchema),
+				checkDimensionFieldsCommandAction,
 			)
 			if err != nil {
 				return err
@@ -46,6 +60,13 @@ func setupLintCommand() *cobraext.Command {
 func lintCommandAction(cmd *cobra.Command, args []string) error {
 	cmd.Println("Lint the package")
 
+	// Missing sample events are reported before checking the README files, as they can't be
+	// rendered without them.
+	err := checkSampleEventsCommandAction(cmd, args)
+	if err != nil {
+		return err
+	}
+
 	readmeFiles, err := docs.AreReadmesUpToDate()
 	if err != nil {
 		for _, f := range readmeFiles {
@@ -61,6 +82,115 @@ func lintCommandAction(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func checkSampleEventsCommandAction(cmd *cobra.Command, args []string) error {
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	missing, err := docs.CheckSampleEvents(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("checking sample events failed: %w", err)
+	}
+	if len(missing) > 0 {
+		for _, m := range missing {
+			cmd.Println(m.String())
+		}
+		return fmt.Errorf("found %d sample events referenced in docs that don't exist", len(missing))
+	}
+	return nil
+}
+
+func checkSecretVariablesCommandAction(cmd *cobra.Command, args []string) error {
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	offending, err := packages.FindSecretVariablesWithDefaults(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("checking secret variables failed: %w", err)
+	}
+	if len(offending) > 0 {
+		for _, v := range offending {
+			cmd.Println(v.String())
+		}
+		return fmt.Errorf("found %d secret variables with default values", len(offending))
+	}
+	return nil
+}
+
+func checkTemplateVariablesCommandAction(cmd *cobra.Command, args []string) error {
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	// Undeclared variables are only reported as warnings, as templates are not fully parsed and
+	// there can be false positives.
+	undeclared, err := packages.FindUndeclaredTemplateVariables(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("checking template variables failed: %w", err)
+	}
+	for _, v := range undeclared {
+		cmd.Printf("Warning: %s\n", v.String())
+	}
+	return nil
+}
+
+func checkPolicyTemplateDataStreamsCommandAction(cmd *cobra.Command, args []string) error {
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	missing, err := packages.FindMissingPolicyTemplateDataStreams(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("checking policy templates failed: %w", err)
+	}
+	if len(missing) > 0 {
+		for _, m := range missing {
+			cmd.Println(m.String())
+		}
+		return fmt.Errorf("found %d data streams referenced in policy templates that don't exist", len(missing))
+	}
+	return nil
+}
+
+func checkTransformVersionsCommandAction(cmd *cobra.Command, args []string) error {
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	missing, err := packages.FindTransformsWithoutVersion(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("checking transforms failed: %w", err)
+	}
+	if len(missing) > 0 {
+		for _, m := range missing {
+			cmd.Println(m.String())
+		}
+		return fmt.Errorf("found %d transforms without fleet_transform_version", len(missing))
+	}
+	return nil
+}
+
 func validateSourceCommandAction(cmd *cobra.Command, args []string) error {
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
@@ -78,3 +208,171 @@ func validateSourceCommandAction(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// lintECSSchema holds the ECS schema of the linted package, so it is loaded only once by the
+// checks that compare the fields of the package with ECS.
+type lintECSSchema struct {
+	schema *fields.ECSSchema
+}
+
+func (s *lintECSSchema) get(packageRootPath string, specVersion string) (*fields.ECSSchema, error) {
+	if s.schema != nil {
+		return s.schema, nil
+	}
+	schema, err := fields.NewECSSchema(packageRootPath, specVersion)
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+	return schema, nil
+}
+
+func checkMultiFieldsCommandAction(ecsSchema *lintECSSchema) cobraext.CommandAction {
+	return func(cmd *cobra.Command, args []string) error {
+		packageRootPath, found, err := packages.FindPackageRoot()
+		if !found {
+			return errors.New("package root not found")
+		}
+		if err != nil {
+			return fmt.Errorf("locating package root failed: %w", err)
+		}
+
+		manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+		if err != nil {
+			return fmt.Errorf("reading package manifest failed: %w", err)
+		}
+		schema, err := ecsSchema.get(packageRootPath, manifest.SpecVersion)
+		if err != nil {
+			return fmt.Errorf("creating ECS schema failed: %w", err)
+		}
+
+		dataStreams, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+		if err != nil {
+			return fmt.Errorf("can't look for data streams: %w", err)
+		}
+
+		var conflicts []packages.Problem
+		for _, fieldsParentDir := range append([]string{packageRootPath}, dataStreams...) {
+			// Multifields are checked with the definitions of the package, the ECS schema is
+			// only loaded if multifields are defined for external fields.
+			validator, err := fields.CreateValidatorForDirectory(fieldsParentDir,
+				fields.WithSpecVersion(manifest.SpecVersion),
+				fields.WithDisabledDependencyManagement(),
+			)
+			if err != nil {
+				return fmt.Errorf("loading fields failed (path: %s): %w", fieldsParentDir, err)
+			}
+			found, err := validator.MultiFieldConflicts(schema)
+			if err != nil {
+				return fmt.Errorf("checking multifields failed (path: %s): %w", fieldsParentDir, err)
+			}
+			conflicts = append(conflicts, found...)
+		}
+		if len(conflicts) > 0 {
+			for _, c := range conflicts {
+				cmd.Println(c.String())
+			}
+			return fmt.Errorf("found %d multifields inconsistent with ECS", len(conflicts))
+		}
+		return nil
+	}
+}
+
+func checkPipelineFieldsCommandAction(ecsSchema *lintECSSchema) cobraext.CommandAction {
+	return func(cmd *cobra.Command, args []string) error {
+		packageRootPath, found, err := packages.FindPackageRoot()
+		if !found {
+			return errors.New("package root not found")
+		}
+		if err != nil {
+			return fmt.Errorf("locating package root failed: %w", err)
+		}
+
+		manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+		if err != nil {
+			return fmt.Errorf("reading package manifest failed: %w", err)
+		}
+		// This check is advisory, so failures loading the schemas are only reported as warnings.
+		schema, err := ecsSchema.get(packageRootPath, manifest.SpecVersion)
+		if err != nil {
+			cmd.Printf("Warning: creating ECS schema failed: %s\n", err)
+			return nil
+		}
+
+		dataStreams, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+		if err != nil {
+			return fmt.Errorf("can't look for data streams: %w", err)
+		}
+
+		// Undefined fields are only reported as warnings, as they can be false positives for fields
+		// covered by dynamic mappings.
+		for _, dataStream := range dataStreams {
+			validator, err := fields.CreateValidatorForDirectory(dataStream,
+				fields.WithSpecVersion(manifest.SpecVersion),
+				fields.WithDisabledDependencyManagement(),
+			)
+			if err != nil {
+				cmd.Printf("Warning: loading fields failed (path: %s): %s\n", dataStream, err)
+				continue
+			}
+			undefined, err := validator.UndefinedPipelineTargetFields(dataStream, schema)
+			if err != nil {
+				cmd.Printf("Warning: checking ingest pipelines failed (path: %s): %s\n", dataStream, err)
+				continue
+			}
+			for _, field := range undefined {
+				cmd.Printf("Warning: %s\n", field.String())
+			}
+		}
+		return nil
+	}
+}
+
+func checkDimensionFieldsCommandAction(cmd *cobra.Command, args []string) error {
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	dataStreams, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+	if err != nil {
+		return fmt.Errorf("can't look for data streams: %w", err)
+	}
+
+	invalidCount := 0
+	for _, dataStream := range dataStreams {
+		dataStreamManifest, err := packages.ReadDataStreamManifest(filepath.Join(dataStream, packages.DataStreamManifestFile))
+		if err != nil {
+			return fmt.Errorf("reading data stream manifest failed (path: %s): %w", dataStream, err)
+		}
+		if dataStreamManifest.Elasticsearch == nil || dataStreamManifest.Elasticsearch.IndexMode != "time_series" {
+			continue
+		}
+
+		// Dimensions are checked with the package schema, there is no need to resolve external fields.
+		validator, err := fields.CreateValidatorForDirectory(dataStream,
+			fields.WithSpecVersion(manifest.SpecVersion),
+			fields.WithDisabledDependencyManagement(),
+		)
+		if err != nil {
+			return fmt.Errorf("loading fields failed (path: %s): %w", dataStream, err)
+		}
+		invalid := validator.InvalidDimensionFields()
+		for _, f := range invalid {
+			cmd.Println(f.String())
+		}
+		invalidCount += len(invalid)
+	}
+	if invalidCount > 0 {
+		return fmt.Errorf("found %d invalid dimension fields", invalidCount)
+	}
+	return nil
+}
@@ -13,9 +13,11 @@ import (
 
 	"github.com/elastic/elastic-package/internal/builder"
 	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/reportgenerator"
 	_ "github.com/elastic/elastic-package/internal/reportgenerator/generators" // register all report generators
 	"github.com/elastic/elastic-package/internal/reportgenerator/outputs"
+	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
 const (
@@ -28,6 +30,14 @@ These report will be generated by comparing local benchmark results against ones
 The report will show performance differences between both runs.
 
 It is formatted as a Markdown Github comment to use as part of the CI results.
+
+#### Field coverage report
+
+This report shows the percentage of fields defined in the data streams of the package that are exercised by
+test documents. Test documents are the sample events and the expected results of pipeline tests.
+
+Use the --full flag to list the fields not covered by any document, and the --badge flag to write an SVG badge
+with the field coverage of the package in the test coverage reports folder.
 `
 )
 
@@ -57,6 +67,9 @@ func setupReportsCommand() *cobraext.Command {
 	// add benchmark report creation subcommand
 	cmd.AddCommand(getBenchReportCommand())
 
+	// add field coverage report creation subcommand
+	cmd.AddCommand(getFieldCoverageReportCommand())
+
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
 
@@ -159,6 +172,66 @@ func getBenchReportCommand() *cobra.Command {
 	return cmd
 }
 
+func getFieldCoverageReportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "field-coverage",
+		Short: "Generate a field coverage report",
+		Long:  "Generate a report with the percentage of fields defined in the package that are exercised by test documents.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("Generate field coverage report")
+
+			isFull, err := cmd.Flags().GetBool(cobraext.ReportFullFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.ReportFullFlagName)
+			}
+
+			badge, err := cmd.Flags().GetBool(cobraext.FieldCoverageBadgeFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.FieldCoverageBadgeFlagName)
+			}
+
+			packageRootPath, err := packages.MustFindPackageRoot()
+			if err != nil {
+				return fmt.Errorf("locating package root failed: %w", err)
+			}
+
+			manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+			if err != nil {
+				return fmt.Errorf("reading package manifest failed (path: %s): %w", packageRootPath, err)
+			}
+
+			report, err := testrunner.GenerateFieldCoverageReport(manifest.Name, packageRootPath)
+			if err != nil {
+				return fmt.Errorf("generating field coverage report failed: %w", err)
+			}
+
+			for _, ds := range report.DataStreams {
+				cmd.Printf("%s: %d/%d fields covered (%.1f%%)\n", ds.Name, len(ds.Covered), len(ds.Defined), ds.Percentage())
+				if isFull {
+					for _, field := range ds.Uncovered() {
+						cmd.Printf("  - %s\n", field)
+					}
+				}
+			}
+			cmd.Printf("Total: %d/%d fields covered (%.1f%%)\n", report.Covered(), report.Defined(), report.Percentage())
+
+			if badge {
+				path, err := testrunner.WriteFieldCoverageBadge(report)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("Field coverage badge written to %s\n", path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolP(cobraext.FieldCoverageBadgeFlagName, "", false, cobraext.FieldCoverageBadgeFlagDescription)
+
+	return cmd
+}
+
 // resultsDir returns the location of the directory to store reports.
 func resultsDir() (string, error) {
 	buildDir, err := builder.BuildDirectory()
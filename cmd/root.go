@@ -23,6 +23,7 @@ var commands = []*cobraext.Command{
 	setupCheckCommand(),
 	setupCleanCommand(),
 	setupCreateCommand(),
+	setupDiffCommand(),
 	setupDumpCommand(),
 	setupEditCommand(),
 	setupExportCommand(),
@@ -36,6 +37,7 @@ var commands = []*cobraext.Command{
 	setupStatusCommand(),
 	setupTestCommand(),
 	setupUninstallCommand(),
+	setupVerifyCommand(),
 	setupVersionCommand(),
 }
 
@@ -5,8 +5,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/table"
 
@@ -14,6 +16,7 @@ import (
 
 	"github.com/elastic/elastic-package/internal/cobraext"
 	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/elasticsearch"
 	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/stack"
 )
@@ -54,6 +57,18 @@ There are different providers supported, that can be selected with the --provide
 - environment: Prepares an existing stack to be used to test packages. Missing components are started locally using Docker Compose. Environment variables are used to configure the access to the existing Elasticsearch and Kibana instances.
 - serverless: Uses Elastic Cloud to start a serverless project. Requires an Elastic Cloud API key.`
 
+const stackStatusLongDescription = `Use this command to show the status of the stack services.
+
+Besides the status of the services, the versions reported by the APIs of Elasticsearch, Kibana, Fleet Server and the Elastic Agent managed by elastic-package are shown, so version-skew issues between the components are visible. Components whose API is not available are reported as unavailable. In serverless projects, Elasticsearch and Kibana are reported as serverless, and Fleet Server as managed.`
+
+const stackCleanLongDescription = `Use this command to clean resources left in the stack by tests.
+
+Interrupted tests can leave data streams in the stack, this command looks for the data streams created by tests, whose namespace is the identifier of the test run, and that were created before the age given with --stale-data-streams. By default the data streams found are only listed, use --force to delete them.`
+
+const stackAgentsLongDescription = `Use this command to list the Elastic Agents enrolled in Fleet with the policies used by elastic-package.
+
+Agents enrolled with the default policy of the agents managed by elastic-package, or with any of the policies created by test runners, are listed with their policy, status and version. This can help to find out why tests are waiting for agents to be enrolled.`
+
 const stackShellinitLongDescription = `Use this command to export to the current shell the configuration of the stack managed by elastic-package.
 
 The output of this command is intended to be evaluated by the current shell. For example in bash: 'eval $(elastic-package stack shellinit)'.
@@ -277,9 +292,68 @@ func setupStackCommand() *cobraext.Command {
 	}
 	dumpCommand.Flags().StringP(cobraext.StackDumpOutputFlagName, "", "elastic-stack-dump", cobraext.StackDumpOutputFlagDescription)
 
+	cleanCommand := &cobra.Command{
+		Use:   "clean",
+		Short: "Clean resources left in the stack by tests",
+		Long:  stackCleanLongDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			staleDataStreamsAge, err := cmd.Flags().GetDuration(cobraext.StackCleanStaleDataStreamsFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.StackCleanStaleDataStreamsFlagName)
+			}
+			if staleDataStreamsAge <= 0 {
+				return cobraext.FlagParsingError(errors.New("expected a positive duration"), cobraext.StackCleanStaleDataStreamsFlagName)
+			}
+
+			force, err := cmd.Flags().GetBool(cobraext.StackCleanForceFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.StackCleanForceFlagName)
+			}
+
+			profile, err := cobraext.GetProfileFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			esClient, err := stack.NewElasticsearchClientFromProfile(profile)
+			if err != nil {
+				return fmt.Errorf("can't create Elasticsearch client: %w", err)
+			}
+
+			dataStreams, err := stack.StaleDataStreams(cmd.Context(), esClient, staleDataStreamsAge)
+			if err != nil {
+				return fmt.Errorf("failed looking for stale data streams: %w", err)
+			}
+
+			cmd.Printf("Data streams created by tests older than %s:\n", staleDataStreamsAge)
+			printDataStreams(cmd, dataStreams)
+			if len(dataStreams) == 0 {
+				return nil
+			}
+
+			if !force {
+				cmd.Printf("Dry run, use --%s to delete these data streams.\n", cobraext.StackCleanForceFlagName)
+				return nil
+			}
+
+			err = stack.DeleteDataStreams(cmd.Context(), esClient, dataStreams)
+			if err != nil {
+				return fmt.Errorf("failed deleting stale data streams: %w", err)
+			}
+
+			cmd.Println("Done")
+			return nil
+		},
+	}
+	cleanCommand.Flags().Duration(cobraext.StackCleanStaleDataStreamsFlagName, 0, cobraext.StackCleanStaleDataStreamsFlagDescription)
+	cleanCommand.MarkFlagRequired(cobraext.StackCleanStaleDataStreamsFlagName)
+	cleanCommand.Flags().Bool(cobraext.StackCleanForceFlagName, false, cobraext.StackCleanForceFlagDescription)
+
 	statusCommand := &cobra.Command{
 		Use:   "status",
 		Short: "Show status of the stack services",
+		Long:  stackStatusLongDescription,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			profile, err := cobraext.GetProfileFlag(cmd)
@@ -302,6 +376,39 @@ func setupStackCommand() *cobraext.Command {
 
 			cmd.Println("Status of Elastic stack services:")
 			printStatus(cmd, servicesStatus)
+			if len(servicesStatus) == 0 {
+				return nil
+			}
+
+			cmd.Println("Versions reported by the Elastic stack components:")
+			printComponentVersions(cmd, stack.ComponentVersions(cmd.Context(), profile))
+			return nil
+		},
+	}
+
+	agentsCommand := &cobra.Command{
+		Use:   "agents",
+		Short: "Show the agents enrolled with the policies used by elastic-package",
+		Long:  stackAgentsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, err := cobraext.GetProfileFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			kibanaClient, err := stack.NewKibanaClientFromProfile(profile)
+			if err != nil {
+				return fmt.Errorf("can't create Kibana client: %w", err)
+			}
+
+			agents, err := stack.ListEnrolledAgents(cmd.Context(), kibanaClient)
+			if err != nil {
+				return fmt.Errorf("failed listing enrolled agents: %w", err)
+			}
+
+			cmd.Println("Enrolled agents:")
+			printAgents(cmd, agents)
 			return nil
 		},
 	}
@@ -318,7 +425,9 @@ func setupStackCommand() *cobraext.Command {
 		updateCommand,
 		shellInitCommand,
 		dumpCommand,
-		statusCommand)
+		statusCommand,
+		agentsCommand,
+		cleanCommand)
 
 	return cobraext.NewCommand(cmd, cobraext.ContextGlobal)
 }
@@ -364,3 +473,44 @@ func printStatus(cmd *cobra.Command, servicesStatus []stack.ServiceStatus) {
 	t.SetStyle(table.StyleRounded)
 	cmd.Println(t.Render())
 }
+
+func printComponentVersions(cmd *cobra.Command, versions []stack.ComponentVersion) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Component", "Version"})
+
+	for _, version := range versions {
+		t.AppendRow(table.Row{version.Name, version.Version})
+	}
+	t.SetStyle(table.StyleRounded)
+	cmd.Println(t.Render())
+}
+
+func printDataStreams(cmd *cobra.Command, dataStreams []elasticsearch.DataStream) {
+	if len(dataStreams) == 0 {
+		cmd.Printf(" - No data stream found\n")
+		return
+	}
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Data stream", "Created"})
+
+	for _, dataStream := range dataStreams {
+		t.AppendRow(table.Row{dataStream.Name, dataStream.CreationDate.Format(time.RFC3339)})
+	}
+	t.SetStyle(table.StyleRounded)
+	cmd.Println(t.Render())
+}
+
+func printAgents(cmd *cobra.Command, agents []stack.EnrolledAgent) {
+	if len(agents) == 0 {
+		cmd.Printf(" - No agent enrolled\n")
+		return
+	}
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Host", "Policy", "Status", "Version"})
+
+	for _, agent := range agents {
+		t.AppendRow(table.Row{agent.LocalMetadata.Host.Name, agent.PolicyName, agent.Status, agent.LocalMetadata.Elastic.Agent.Version})
+	}
+	t.SetStyle(table.StyleRounded)
+	cmd.Println(t.Render())
+}
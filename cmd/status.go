@@ -21,10 +21,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/packages/changelog"
 	"github.com/elastic/elastic-package/internal/packages/status"
 	"github.com/elastic/elastic-package/internal/registry"
+	"github.com/elastic/elastic-package/internal/stack"
 )
 
 const statusLongDescription = `Use this command to display the current deployment status of a package.
@@ -73,6 +75,7 @@ func setupStatusCommand() *cobraext.Command {
 	cmd.Flags().String(cobraext.StatusKibanaVersionFlagName, "", cobraext.StatusKibanaVersionFlagDescription)
 	cmd.Flags().StringSlice(cobraext.StatusExtraInfoFlagName, nil, fmt.Sprintf(cobraext.StatusExtraInfoFlagDescription, strings.Join(availableExtraInfoParameters, ",")))
 	cmd.Flags().String(cobraext.StatusFormatFlagName, "table", fmt.Sprintf(cobraext.StatusFormatFlagDescription, strings.Join(availableFormatsParameters, ",")))
+	cmd.Flags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
 
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
@@ -109,6 +112,15 @@ func statusCommandAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validating info paramaters failed: %w", err)
 
 	}
+	profile, err := cobraext.GetProfileFlag(cmd)
+	if err != nil {
+		return err
+	}
+	registryClient, err := stack.NewRegistryClientFromProfile(profile)
+	if err != nil {
+		return fmt.Errorf("failed to create package registry client: %w", err)
+	}
+
 	options := registry.SearchOptions{
 		All:           showAll,
 		KibanaVersion: kibanaVersion,
@@ -117,7 +129,7 @@ func statusCommandAction(cmd *cobra.Command, args []string) error {
 		// Deprecated, keeping for compatibility with older versions of the registry.
 		Experimental: true,
 	}
-	packageStatus, err := getPackageStatus(packageName, options)
+	packageStatus, err := getPackageStatus(registryClient, packageName, options)
 	if err != nil {
 		return err
 	}
@@ -126,7 +138,7 @@ func statusCommandAction(cmd *cobra.Command, args []string) error {
 		if packageName == "" && packageStatus.Local != nil {
 			packageName = packageStatus.Local.Name
 		}
-		packageStatus.Serverless, err = getServerlessManifests(packageName, options)
+		packageStatus.Serverless, err = getServerlessManifests(registryClient, packageName, options)
 		if err != nil {
 			return err
 		}
@@ -158,9 +170,9 @@ func validateExtraInfoParameters(extraParameters []string) error {
 	return nil
 }
 
-func getPackageStatus(packageName string, options registry.SearchOptions) (*status.PackageStatus, error) {
+func getPackageStatus(registryClient *registry.Client, packageName string, options registry.SearchOptions) (*status.PackageStatus, error) {
 	if packageName != "" {
-		return status.RemotePackage(packageName, options)
+		return status.RemotePackage(registryClient, packageName, options)
 	}
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
@@ -169,10 +181,10 @@ func getPackageStatus(packageName string, options registry.SearchOptions) (*stat
 	if err != nil {
 		return nil, fmt.Errorf("locating package root failed: %w", err)
 	}
-	return status.LocalPackage(packageRootPath, options)
+	return status.LocalPackage(registryClient, packageRootPath, options)
 }
 
-func getServerlessManifests(packageName string, options registry.SearchOptions) ([]status.ServerlessManifests, error) {
+func getServerlessManifests(registryClient *registry.Client, packageName string, options registry.SearchOptions) ([]status.ServerlessManifests, error) {
 	if packageName == "" {
 		return nil, nil
 	}
@@ -186,7 +198,7 @@ func getServerlessManifests(packageName string, options registry.SearchOptions)
 		options.Capabilities = projectType.Capabilities
 		options.SpecMax = projectType.SpecMax
 		options.SpecMin = projectType.SpecMin
-		manifests, err := registry.Production.Revisions(packageName, options)
+		manifests, err := registryClient.Revisions(packageName, options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get packages available for serverless projects of type %s: %w", projectType.Name, err)
 		}
@@ -7,17 +7,22 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
 	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/elasticsearch"
 	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/kibana"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/signal"
@@ -57,7 +62,12 @@ For details on how to configure and run system tests, review the [HOWTO guide](h
 #### Policy Tests
 These tests allow you to test different configuration options and the policies they generate, without needing to run a full scenario.
 
-For details on how to configure and run policy tests, review the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/policy_testing.md).`
+For details on how to configure and run policy tests, review the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/policy_testing.md).
+
+#### Coverage reports
+Coverage reports are generated with the ` + "`--test-coverage`" + ` flag, in the format selected with ` + "`--coverage-format`" + `.
+Packages can override the default format by setting ` + "`coverage.type`" + ` in their global test configuration file (` + "`_dev/test/config.yml`" + `), the format set with the flag has precedence.
+Files of the package are included in the reports even if their tests are skipped, reported as not covered.`
 
 func setupTestCommand() *cobraext.Command {
 	cmd := &cobra.Command{
@@ -74,6 +84,8 @@ func setupTestCommand() *cobraext.Command {
 
 	cmd.PersistentFlags().StringP(cobraext.ReportFormatFlagName, "", string(formats.ReportFormatHuman), cobraext.ReportFormatFlagDescription)
 	cmd.PersistentFlags().StringP(cobraext.ReportOutputFlagName, "", string(outputs.ReportOutputSTDOUT), cobraext.ReportOutputFlagDescription)
+	cmd.PersistentFlags().String(cobraext.ReportSuiteNameFlagName, "", cobraext.ReportSuiteNameFlagDescription)
+	cmd.PersistentFlags().StringToString(cobraext.ReportPropertyFlagName, nil, cobraext.ReportPropertyFlagDescription)
 	cmd.PersistentFlags().BoolP(cobraext.TestCoverageFlagName, "", false, cobraext.TestCoverageFlagDescription)
 	cmd.PersistentFlags().StringP(cobraext.TestCoverageFormatFlagName, "", "cobertura", fmt.Sprintf(cobraext.TestCoverageFormatFlagDescription, strings.Join(testrunner.CoverageFormatsList(), ",")))
 	cmd.PersistentFlags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
@@ -109,6 +121,11 @@ func getTestRunnerAssetCommand() *cobra.Command {
 		RunE:  testRunnerAssetCommandAction,
 	}
 
+	cmd.Flags().Bool(cobraext.CheckIdempotencyFlagName, false, cobraext.CheckIdempotencyFlagDescription)
+	cmd.Flags().Bool(cobraext.CheckMigrationsFlagName, false, cobraext.CheckMigrationsFlagDescription)
+	cmd.Flags().String(cobraext.UpgradeFromFlagName, "", cobraext.UpgradeFromFlagDescription)
+	cmd.Flags().String(cobraext.SkipVersionCheckFlagName, "", cobraext.SkipVersionCheckFlagDescription)
+
 	return cmd
 }
 
@@ -121,6 +138,12 @@ func testRunnerAssetCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	tunnel, err := stack.StartSSHTunnel(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+
 	reportFormat, err := cmd.Flags().GetString(cobraext.ReportFormatFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.ReportFormatFlagName)
@@ -145,6 +168,21 @@ func testRunnerAssetCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(fmt.Errorf("coverage format not available: %s", testCoverageFormat), cobraext.TestCoverageFormatFlagName)
 	}
 
+	checkIdempotency, err := cmd.Flags().GetBool(cobraext.CheckIdempotencyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.CheckIdempotencyFlagName)
+	}
+
+	checkMigrations, err := cmd.Flags().GetBool(cobraext.CheckMigrationsFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.CheckMigrationsFlagName)
+	}
+
+	upgradeFrom, err := cmd.Flags().GetString(cobraext.UpgradeFromFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.UpgradeFromFlagName)
+	}
+
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
 		return errors.New("package root not found")
@@ -161,15 +199,36 @@ func testRunnerAssetCommandAction(cmd *cobra.Command, args []string) error {
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
-	kibanaClient, err := stack.NewKibanaClientFromProfile(profile)
+	kibanaOptions, err := kibanaClientOptions(cmd)
+	if err != nil {
+		return err
+	}
+	kibanaClient, err := stack.NewKibanaClientFromProfile(profile, kibanaOptions...)
 	if err != nil {
 		return fmt.Errorf("can't create Kibana client: %w", err)
 	}
+	kibanaVersion, err := kibanaClient.Version()
+	if err != nil {
+		return fmt.Errorf("can't get Kibana version: %w", err)
+	}
+	reportOptions, err := getReportOptions(cmd, kibanaVersion.Version())
+	if err != nil {
+		return err
+	}
+
+	var esClient *elasticsearch.Client
+	if upgradeFrom != "" {
+		esClient, err = stack.NewElasticsearchClientFromProfile(profile)
+		if err != nil {
+			return fmt.Errorf("can't create Elasticsearch client: %w", err)
+		}
+	}
 
 	globalTestConfig, err := testrunner.ReadGlobalTestConfig(packageRootPath)
 	if err != nil {
 		return fmt.Errorf("failed to read global config: %w", err)
 	}
+	testCoverageFormat = packageTestCoverageFormat(cmd, testCoverageFormat, globalTestConfig.CoverageType)
 
 	runner := asset.NewAssetTestRunner(asset.AssetTestRunnerOptions{
 		PackageRootPath:  packageRootPath,
@@ -177,6 +236,10 @@ func testRunnerAssetCommandAction(cmd *cobra.Command, args []string) error {
 		GlobalTestConfig: globalTestConfig.Asset,
 		WithCoverage:     testCoverage,
 		CoverageType:     testCoverageFormat,
+		CheckIdempotency: checkIdempotency,
+		CheckMigrations:  checkMigrations,
+		ESClient:         esClient,
+		UpgradeFrom:      upgradeFrom,
 	})
 
 	results, err := testrunner.RunSuite(ctx, runner)
@@ -184,7 +247,7 @@ func testRunnerAssetCommandAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error running package %s tests: %w", testType, err)
 	}
 
-	return processResults(results, testType, reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
+	return processResults(results, testType, reportFormat, reportOutput, reportOptions, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 }
 
 func getTestRunnerStaticCommand() *cobra.Command {
@@ -221,6 +284,12 @@ func testRunnerStaticCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.ReportOutputFlagName)
 	}
 
+	// Static tests don't use the stack, so its version is not known.
+	reportOptions, err := getReportOptions(cmd, "")
+	if err != nil {
+		return err
+	}
+
 	testCoverage, err := cmd.Flags().GetBool(cobraext.TestCoverageFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.TestCoverageFlagName)
@@ -260,6 +329,7 @@ func testRunnerStaticCommandAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read global config: %w", err)
 	}
+	testCoverageFormat = packageTestCoverageFormat(cmd, testCoverageFormat, globalTestConfig.CoverageType)
 
 	runner := static.NewStaticTestRunner(static.StaticTestRunnerOptions{
 		PackageRootPath:    packageRootPath,
@@ -275,7 +345,7 @@ func testRunnerStaticCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return processResults(results, testType, reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
+	return processResults(results, testType, reportFormat, reportOutput, reportOptions, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 }
 
 func getTestRunnerPipelineCommand() *cobra.Command {
@@ -289,6 +359,8 @@ func getTestRunnerPipelineCommand() *cobra.Command {
 
 	cmd.Flags().BoolP(cobraext.FailOnMissingFlagName, "m", false, cobraext.FailOnMissingFlagDescription)
 	cmd.Flags().BoolP(cobraext.GenerateTestResultFlagName, "g", false, cobraext.GenerateTestResultFlagDescription)
+	cmd.Flags().Bool(cobraext.ExplainFlagName, false, cobraext.ExplainFlagDescription)
+	cmd.Flags().String(cobraext.NextECSReferenceFlagName, "", cobraext.NextECSReferenceFlagDescription)
 	cmd.Flags().StringSliceP(cobraext.DataStreamsFlagName, "d", nil, cobraext.DataStreamsFlagDescription)
 
 	return cmd
@@ -303,6 +375,12 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	tunnel, err := stack.StartSSHTunnel(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+
 	failOnMissing, err := cmd.Flags().GetBool(cobraext.FailOnMissingFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.FailOnMissingFlagName)
@@ -313,6 +391,16 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.GenerateTestResultFlagName)
 	}
 
+	explain, err := cmd.Flags().GetBool(cobraext.ExplainFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ExplainFlagName)
+	}
+
+	nextECSReference, err := cmd.Flags().GetString(cobraext.NextECSReferenceFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.NextECSReferenceFlagName)
+	}
+
 	reportFormat, err := cmd.Flags().GetString(cobraext.ReportFormatFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.ReportFormatFlagName)
@@ -366,6 +454,14 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	esInfo, err := esClient.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("can't get Elasticsearch version: %w", err)
+	}
+	reportOptions, err := getReportOptions(cmd, esInfo.Version.Number)
+	if err != nil {
+		return err
+	}
 
 	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
 	if err != nil {
@@ -376,6 +472,7 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read global config: %w", err)
 	}
+	testCoverageFormat = packageTestCoverageFormat(cmd, testCoverageFormat, globalTestConfig.CoverageType)
 
 	runner := pipeline.NewPipelineTestRunner(pipeline.PipelineTestRunnerOptions{
 		Profile:            profile,
@@ -384,6 +481,8 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		DataStreams:        dataStreams,
 		FailOnMissingTests: failOnMissing,
 		GenerateTestResult: generateTestResult,
+		Explain:            explain,
+		NextECSReference:   nextECSReference,
 		WithCoverage:       testCoverage,
 		CoverageType:       testCoverageFormat,
 		DeferCleanup:       deferCleanup,
@@ -395,7 +494,7 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return processResults(results, testType, reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
+	return processResults(results, testType, reportFormat, reportOutput, reportOptions, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 }
 
 func getTestRunnerSystemCommand() *cobra.Command {
@@ -409,13 +508,28 @@ func getTestRunnerSystemCommand() *cobra.Command {
 
 	cmd.Flags().BoolP(cobraext.FailOnMissingFlagName, "m", false, cobraext.FailOnMissingFlagDescription)
 	cmd.Flags().BoolP(cobraext.GenerateTestResultFlagName, "g", false, cobraext.GenerateTestResultFlagDescription)
+	cmd.Flags().Bool(cobraext.ExplainFlagName, false, cobraext.ExplainFlagDescription)
 	cmd.Flags().StringSliceP(cobraext.DataStreamsFlagName, "d", nil, cobraext.DataStreamsFlagDescription)
 	cmd.Flags().String(cobraext.VariantFlagName, "", cobraext.VariantFlagDescription)
+	cmd.Flags().String(cobraext.AgentImageFlagName, "", cobraext.AgentImageFlagDescription)
+	cmd.Flags().Bool(cobraext.StrictIgnoredFieldsFlagName, false, cobraext.StrictIgnoredFieldsFlagDescription)
+	cmd.Flags().Bool(cobraext.FailOnIgnoreMalformedFlagName, false, cobraext.FailOnIgnoreMalformedFlagDescription)
+	cmd.Flags().Bool(cobraext.FailOnAgentWarningsFlagName, false, cobraext.FailOnAgentWarningsFlagDescription)
+	cmd.Flags().String(cobraext.MaxLogSizeFlagName, "", cobraext.MaxLogSizeFlagDescription)
+	cmd.Flags().Bool(cobraext.DiagnosticsOnFailureFlagName, false, cobraext.DiagnosticsOnFailureFlagDescription)
+	cmd.Flags().Bool(cobraext.PrintPolicyFlagName, false, cobraext.PrintPolicyFlagDescription)
+	cmd.Flags().String(cobraext.ValidateOnlyFlagName, "", cobraext.ValidateOnlyFlagDescription)
+	cmd.Flags().String(cobraext.ExportDocsFlagName, "", cobraext.ExportDocsFlagDescription)
+	cmd.Flags().Bool(cobraext.MappingsReportFlagName, false, cobraext.MappingsReportFlagDescription)
+	cmd.Flags().Bool(cobraext.KeepAgentFlagName, false, cobraext.KeepAgentFlagDescription)
+	cmd.Flags().BoolP(cobraext.InteractiveFlagName, "i", false, cobraext.InteractiveFlagDescription)
 
 	cmd.Flags().String(cobraext.ConfigFileFlagName, "", cobraext.ConfigFileFlagDescription)
+	cmd.Flags().String(cobraext.ConfigNameFlagName, "", cobraext.ConfigNameFlagDescription)
 	cmd.Flags().Bool(cobraext.SetupFlagName, false, cobraext.SetupFlagDescription)
 	cmd.Flags().Bool(cobraext.TearDownFlagName, false, cobraext.TearDownFlagDescription)
 	cmd.Flags().Bool(cobraext.NoProvisionFlagName, false, cobraext.NoProvisionFlagDescription)
+	cmd.Flags().String(cobraext.SkipVersionCheckFlagName, "", cobraext.SkipVersionCheckFlagDescription)
 
 	cmd.MarkFlagsMutuallyExclusive(cobraext.SetupFlagName, cobraext.TearDownFlagName, cobraext.NoProvisionFlagName)
 	cmd.MarkFlagsRequiredTogether(cobraext.ConfigFileFlagName, cobraext.SetupFlagName)
@@ -424,6 +538,11 @@ func getTestRunnerSystemCommand() *cobra.Command {
 	cmd.MarkFlagsMutuallyExclusive(cobraext.ConfigFileFlagName, cobraext.TearDownFlagName)
 	cmd.MarkFlagsMutuallyExclusive(cobraext.ConfigFileFlagName, cobraext.NoProvisionFlagName)
 
+	// config name flag selects the tests to run, as the config file flag does
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ConfigNameFlagName, cobraext.ConfigFileFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ConfigNameFlagName, cobraext.TearDownFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ConfigNameFlagName, cobraext.NoProvisionFlagName)
+
 	// variant flag should not be used with tear-down and no-provision flags
 	// cannot be defined here using MarkFlagsMutuallyExclusive as in --config-file
 	// this restriction has been managed later in the code when processing the flags
@@ -431,6 +550,20 @@ func getTestRunnerSystemCommand() *cobra.Command {
 	cmd.MarkFlagsMutuallyExclusive(cobraext.DataStreamsFlagName, cobraext.TearDownFlagName)
 	cmd.MarkFlagsMutuallyExclusive(cobraext.DataStreamsFlagName, cobraext.NoProvisionFlagName)
 
+	// validate only flag uses existing data, it doesn't run any part of the tests
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ValidateOnlyFlagName, cobraext.SetupFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ValidateOnlyFlagName, cobraext.TearDownFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ValidateOnlyFlagName, cobraext.NoProvisionFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ValidateOnlyFlagName, cobraext.PrintPolicyFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.ValidateOnlyFlagName, cobraext.ExportDocsFlagName)
+
+	// interactive flag replaces the flags used to select the tests to run
+	cmd.MarkFlagsMutuallyExclusive(cobraext.InteractiveFlagName, cobraext.DataStreamsFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.InteractiveFlagName, cobraext.ConfigFileFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.InteractiveFlagName, cobraext.ConfigNameFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.InteractiveFlagName, cobraext.TearDownFlagName)
+	cmd.MarkFlagsMutuallyExclusive(cobraext.InteractiveFlagName, cobraext.NoProvisionFlagName)
+
 	return cmd
 }
 
@@ -442,6 +575,12 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	tunnel, err := stack.StartSSHTunnel(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+
 	failOnMissing, err := cmd.Flags().GetBool(cobraext.FailOnMissingFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.FailOnMissingFlagName)
@@ -452,6 +591,11 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.GenerateTestResultFlagName)
 	}
 
+	explain, err := cmd.Flags().GetBool(cobraext.ExplainFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ExplainFlagName)
+	}
+
 	reportFormat, err := cmd.Flags().GetString(cobraext.ReportFormatFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.ReportFormatFlagName)
@@ -486,6 +630,68 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.VariantFlagName)
 	}
 
+	agentImage, err := cmd.Flags().GetString(cobraext.AgentImageFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.AgentImageFlagName)
+	}
+
+	strictIgnoredFields, err := cmd.Flags().GetBool(cobraext.StrictIgnoredFieldsFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.StrictIgnoredFieldsFlagName)
+	}
+
+	failOnMalformed, err := cmd.Flags().GetBool(cobraext.FailOnIgnoreMalformedFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.FailOnIgnoreMalformedFlagName)
+	}
+
+	failOnAgentWarnings, err := cmd.Flags().GetBool(cobraext.FailOnAgentWarningsFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.FailOnAgentWarningsFlagName)
+	}
+
+	var maxLogSize uint64
+	maxLogSizeFlag, err := cmd.Flags().GetString(cobraext.MaxLogSizeFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.MaxLogSizeFlagName)
+	}
+	if maxLogSizeFlag != "" {
+		maxLogSize, err = humanize.ParseBytes(maxLogSizeFlag)
+		if err != nil {
+			return cobraext.FlagParsingError(err, cobraext.MaxLogSizeFlagName)
+		}
+	}
+
+	diagnosticsOnFailure, err := cmd.Flags().GetBool(cobraext.DiagnosticsOnFailureFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.DiagnosticsOnFailureFlagName)
+	}
+
+	printPolicy, err := cmd.Flags().GetBool(cobraext.PrintPolicyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.PrintPolicyFlagName)
+	}
+
+	validateOnly, err := cmd.Flags().GetString(cobraext.ValidateOnlyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ValidateOnlyFlagName)
+	}
+
+	exportDocs, err := cmd.Flags().GetString(cobraext.ExportDocsFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ExportDocsFlagName)
+	}
+
+	mappingsReport, err := cmd.Flags().GetBool(cobraext.MappingsReportFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.MappingsReportFlagName)
+	}
+
+	keepAgent, err := cmd.Flags().GetBool(cobraext.KeepAgentFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.KeepAgentFlagName)
+	}
+
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
 		return errors.New("package root not found")
@@ -522,18 +728,51 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		configFileFlag = absPath
 	}
 
+	configName, err := cmd.Flags().GetString(cobraext.ConfigNameFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ConfigNameFlagName)
+	}
+
 	dataStreams, err := getDataStreamsFlag(cmd, packageRootPath)
 	if err != nil {
 		return err
 	}
 
+	interactive, err := cmd.Flags().GetBool(cobraext.InteractiveFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.InteractiveFlagName)
+	}
+	var configFiles []string
+	if interactive {
+		if isInteractiveTerminal() {
+			dataStreams, configFiles, err = promptSystemTests(packageRootPath)
+			if err != nil {
+				return fmt.Errorf("prompt for test selection failed: %w", err)
+			}
+		} else {
+			logger.Warn("Not running in an interactive terminal, tests are selected with flags")
+		}
+	}
+
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
-	kibanaClient, err := stack.NewKibanaClientFromProfile(profile)
+	kibanaOptions, err := kibanaClientOptions(cmd)
+	if err != nil {
+		return err
+	}
+	kibanaClient, err := stack.NewKibanaClientFromProfile(profile, kibanaOptions...)
 	if err != nil {
 		return fmt.Errorf("can't create Kibana client: %w", err)
 	}
+	kibanaVersion, err := kibanaClient.Version()
+	if err != nil {
+		return fmt.Errorf("can't get Kibana version: %w", err)
+	}
+	reportOptions, err := getReportOptions(cmd, kibanaVersion.Version())
+	if err != nil {
+		return err
+	}
 
 	esClient, err := stack.NewElasticsearchClientFromProfile(profile)
 	if err != nil {
@@ -548,6 +787,10 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("can't check if failure store is available: %w", err)
 	}
 
+	if printPolicy && (runSetup || runTearDown || runTestsOnly) {
+		return fmt.Errorf("print policy flag cannot be set with --setup, --tear-down or --no-provision")
+	}
+
 	if runTearDown || runTestsOnly {
 		if variantFlag != "" {
 			return fmt.Errorf("variant flag cannot be set with --tear-down or --no-provision")
@@ -563,26 +806,41 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read global config: %w", err)
 	}
+	testCoverageFormat = packageTestCoverageFormat(cmd, testCoverageFormat, globalTestConfig.CoverageType)
 
 	runner := system.NewSystemTestRunner(system.SystemTestRunnerOptions{
-		Profile:            profile,
-		PackageRootPath:    packageRootPath,
-		KibanaClient:       kibanaClient,
-		API:                esClient.API,
-		ESClient:           esClient,
-		ConfigFilePath:     configFileFlag,
-		RunSetup:           runSetup,
-		RunTearDown:        runTearDown,
-		RunTestsOnly:       runTestsOnly,
-		DataStreams:        dataStreams,
-		ServiceVariant:     variantFlag,
-		FailOnMissingTests: failOnMissing,
-		GenerateTestResult: generateTestResult,
-		DeferCleanup:       deferCleanup,
-		GlobalTestConfig:   globalTestConfig.System,
-		WithCoverage:       testCoverage,
-		CoverageType:       testCoverageFormat,
-		CheckFailureStore:  checkFailureStore,
+		Profile:              profile,
+		PackageRootPath:      packageRootPath,
+		KibanaClient:         kibanaClient,
+		API:                  esClient.API,
+		ESClient:             esClient,
+		ConfigFilePath:       configFileFlag,
+		ConfigFiles:          configFiles,
+		ConfigName:           configName,
+		RunSetup:             runSetup,
+		RunTearDown:          runTearDown,
+		RunTestsOnly:         runTestsOnly,
+		DataStreams:          dataStreams,
+		ServiceVariant:       variantFlag,
+		FailOnMissingTests:   failOnMissing,
+		GenerateTestResult:   generateTestResult,
+		DeferCleanup:         deferCleanup,
+		GlobalTestConfig:     globalTestConfig.System,
+		WithCoverage:         testCoverage,
+		CoverageType:         testCoverageFormat,
+		CheckFailureStore:    checkFailureStore,
+		AgentImage:           agentImage,
+		StrictIgnoredFields:  strictIgnoredFields,
+		FailOnMalformed:      failOnMalformed,
+		FailOnAgentWarnings:  failOnAgentWarnings,
+		MaxLogSize:           maxLogSize,
+		PrintPolicy:          printPolicy,
+		DiagnosticsOnFailure: diagnosticsOnFailure,
+		ValidateOnly:         validateOnly,
+		ExportDocs:           exportDocs,
+		Explain:              explain,
+		MappingsReport:       mappingsReport,
+		KeepAgent:            keepAgent,
 	})
 
 	logger.Debugf("Running suite...")
@@ -591,7 +849,7 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = processResults(results, runner.Type(), reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
+	err = processResults(results, runner.Type(), reportFormat, reportOutput, reportOptions, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 	if err != nil {
 		return fmt.Errorf("failed to process results: %w", err)
 	}
@@ -610,6 +868,7 @@ func getTestRunnerPolicyCommand() *cobra.Command {
 	cmd.Flags().BoolP(cobraext.FailOnMissingFlagName, "m", false, cobraext.FailOnMissingFlagDescription)
 	cmd.Flags().StringSliceP(cobraext.DataStreamsFlagName, "d", nil, cobraext.DataStreamsFlagDescription)
 	cmd.Flags().BoolP(cobraext.GenerateTestResultFlagName, "g", false, cobraext.GenerateTestResultFlagDescription)
+	cmd.Flags().String(cobraext.SkipVersionCheckFlagName, "", cobraext.SkipVersionCheckFlagDescription)
 	return cmd
 }
 
@@ -622,6 +881,12 @@ func testRunnerPolicyCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	tunnel, err := stack.StartSSHTunnel(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+
 	failOnMissing, err := cmd.Flags().GetBool(cobraext.FailOnMissingFlagName)
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.FailOnMissingFlagName)
@@ -672,10 +937,22 @@ func testRunnerPolicyCommandAction(cmd *cobra.Command, args []string) error {
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
-	kibanaClient, err := stack.NewKibanaClientFromProfile(profile)
+	kibanaOptions, err := kibanaClientOptions(cmd)
+	if err != nil {
+		return err
+	}
+	kibanaClient, err := stack.NewKibanaClientFromProfile(profile, kibanaOptions...)
 	if err != nil {
 		return fmt.Errorf("can't create Kibana client: %w", err)
 	}
+	kibanaVersion, err := kibanaClient.Version()
+	if err != nil {
+		return fmt.Errorf("can't get Kibana version: %w", err)
+	}
+	reportOptions, err := getReportOptions(cmd, kibanaVersion.Version())
+	if err != nil {
+		return err
+	}
 
 	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
 	if err != nil {
@@ -686,6 +963,7 @@ func testRunnerPolicyCommandAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read global config: %w", err)
 	}
+	testCoverageFormat = packageTestCoverageFormat(cmd, testCoverageFormat, globalTestConfig.CoverageType)
 
 	runner := policy.NewPolicyTestRunner(policy.PolicyTestRunnerOptions{
 		PackageRootPath:    packageRootPath,
@@ -703,10 +981,10 @@ func testRunnerPolicyCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return processResults(results, testType, reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
+	return processResults(results, testType, reportFormat, reportOutput, reportOptions, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 }
 
-func processResults(results []testrunner.TestResult, testType testrunner.TestType, reportFormat, reportOutput, packageRootPath, packageName, packageType, testCoverageFormat string, testCoverage bool) error {
+func processResults(results []testrunner.TestResult, testType testrunner.TestType, reportFormat, reportOutput string, reportOptions testrunner.ReportOptions, packageRootPath, packageName, packageType, testCoverageFormat string, testCoverage bool) error {
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].Package != results[j].Package {
 			return results[i].Package < results[j].Package
@@ -720,7 +998,7 @@ func processResults(results []testrunner.TestResult, testType testrunner.TestTyp
 		return results[i].Name < results[j].Name
 	})
 	format := testrunner.TestReportFormat(reportFormat)
-	report, err := testrunner.FormatReport(format, results)
+	report, err := testrunner.FormatReport(format, results, reportOptions)
 	if err != nil {
 		return fmt.Errorf("error formatting test report: %w", err)
 	}
@@ -745,6 +1023,73 @@ func processResults(results []testrunner.TestResult, testType testrunner.TestTyp
 	return nil
 }
 
+// getReportOptions builds the options for test reports from the command flags. Reports include
+// by default some properties describing the test run, the active profile and the stack. The
+// version of the stack is not included if it is empty.
+func getReportOptions(cmd *cobra.Command, stackVersion string) (testrunner.ReportOptions, error) {
+	suiteName, err := cmd.Flags().GetString(cobraext.ReportSuiteNameFlagName)
+	if err != nil {
+		return testrunner.ReportOptions{}, cobraext.FlagParsingError(err, cobraext.ReportSuiteNameFlagName)
+	}
+
+	customProperties, err := cmd.Flags().GetStringToString(cobraext.ReportPropertyFlagName)
+	if err != nil {
+		return testrunner.ReportOptions{}, cobraext.FlagParsingError(err, cobraext.ReportPropertyFlagName)
+	}
+
+	profile, err := cobraext.GetProfileFlag(cmd)
+	if err != nil {
+		return testrunner.ReportOptions{}, err
+	}
+
+	stackConfig, err := stack.LoadConfig(profile)
+	if err != nil {
+		return testrunner.ReportOptions{}, fmt.Errorf("failed to load stack config: %w", err)
+	}
+
+	properties := map[string]string{
+		"run.id":         common.CreateTestRunID(),
+		"profile":        profile.ProfileName,
+		"stack.provider": stackConfig.Provider,
+	}
+	if stackVersion != "" {
+		properties["stack.version"] = stackVersion
+	}
+	maps.Copy(properties, customProperties)
+
+	return testrunner.ReportOptions{
+		SuiteName:  suiteName,
+		Properties: properties,
+	}, nil
+}
+
+// packageTestCoverageFormat returns the format of the coverage reports, which is the one selected
+// with the flag if it is explicitly set, or the one configured in the package otherwise.
+func packageTestCoverageFormat(cmd *cobra.Command, testCoverageFormat string, packageCoverageType func(defaultType string) string) string {
+	if cmd.Flags().Changed(cobraext.TestCoverageFormatFlagName) {
+		return testCoverageFormat
+	}
+	return packageCoverageType(testCoverageFormat)
+}
+
+// kibanaClientOptions returns the options for the Kibana clients of the tests. If a version is
+// provided with the skip version check flag, it is used instead of requesting it to Kibana.
+func kibanaClientOptions(cmd *cobra.Command) ([]kibana.ClientOption, error) {
+	version, err := cmd.Flags().GetString(cobraext.SkipVersionCheckFlagName)
+	if err != nil {
+		return nil, cobraext.FlagParsingError(err, cobraext.SkipVersionCheckFlagName)
+	}
+	if version == "" {
+		return nil, nil
+	}
+	_, err = semver.NewVersion(strings.TrimSuffix(version, kibana.SNAPSHOT_SUFFIX))
+	if err != nil {
+		return nil, cobraext.FlagParsingError(fmt.Errorf("invalid version %q: %w", version, err), cobraext.SkipVersionCheckFlagName)
+	}
+	logger.Warnf("Skipping version check, assuming stack version %s. Validations that depend on the version of the stack may be inaccurate if it is not the running version.", version)
+	return []kibana.ClientOption{kibana.KnownVersion(version)}, nil
+}
+
 func validateDataStreamsFlag(packageRootPath string, dataStreams []string) error {
 	for _, dataStream := range dataStreams {
 		path := filepath.Join(packageRootPath, "data_stream", dataStream)
@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/term"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/system"
+)
+
+// isInteractiveTerminal returns true if the standard input and output are attached to a terminal,
+// so the user can answer prompts.
+func isInteractiveTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// systemTestOption is a configuration file of a system test that can be selected in prompts.
+type systemTestOption struct {
+	dataStream string
+	path       string
+}
+
+func (o systemTestOption) String() string {
+	if o.dataStream == "" {
+		return filepath.Base(o.path)
+	}
+	return o.dataStream + "/" + filepath.Base(o.path)
+}
+
+// promptSystemTests asks the user to select the data streams and the configuration files of the
+// system tests to run. It returns the selected data streams and the paths of the selected
+// configuration files.
+func promptSystemTests(packageRootPath string) ([]string, []string, error) {
+	folders, err := testrunner.FindTestFolders(packageRootPath, nil, system.TestType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to determine test folder paths: %w", err)
+	}
+	if len(folders) == 0 {
+		return nil, nil, errors.New("no system tests found")
+	}
+
+	var dataStreams []string
+	for _, folder := range folders {
+		if folder.DataStream != "" && !slices.Contains(dataStreams, folder.DataStream) {
+			dataStreams = append(dataStreams, folder.DataStream)
+		}
+	}
+	if len(dataStreams) > 1 {
+		dataStreamsPrompt := &survey.MultiSelect{
+			Message:  "Which data streams would you like to test?",
+			Options:  dataStreams,
+			PageSize: 20,
+		}
+		var selected []string
+		err = survey.AskOne(dataStreamsPrompt, &selected, survey.WithValidator(survey.Required))
+		if err != nil {
+			return nil, nil, err
+		}
+		dataStreams = selected
+	}
+
+	var options []systemTestOption
+	for _, folder := range folders {
+		if folder.DataStream != "" && !slices.Contains(dataStreams, folder.DataStream) {
+			continue
+		}
+		cfgFiles, err := system.ListConfigFiles(folder.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed listing test case config files: %w", err)
+		}
+		for _, cfgFile := range cfgFiles {
+			options = append(options, systemTestOption{
+				dataStream: folder.DataStream,
+				path:       filepath.Join(folder.Path, cfgFile),
+			})
+		}
+	}
+
+	var configFiles []string
+	if len(options) <= 1 {
+		for _, option := range options {
+			configFiles = append(configFiles, option.path)
+		}
+		return dataStreams, configFiles, nil
+	}
+
+	optionNames := make([]string, len(options))
+	for i, option := range options {
+		optionNames[i] = option.String()
+	}
+	configFilesPrompt := &survey.MultiSelect{
+		Message:  "Which test configurations would you like to run?",
+		Options:  optionNames,
+		Default:  optionNames,
+		PageSize: 20,
+	}
+	var selected []string
+	err = survey.AskOne(configFilesPrompt, &selected, survey.WithValidator(survey.Required))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, option := range options {
+		if slices.Contains(selected, option.String()) {
+			configFiles = append(configFiles, option.path)
+		}
+	}
+	return dataStreams, configFiles, nil
+}
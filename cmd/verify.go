@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/files"
+)
+
+const verifyLongDescription = `Use this command to verify the signature of a built package.
+
+The command verifies the detached GPG signature of the package zip file using the public key of the signer. If the signature is valid, it prints the fingerprint of the signing key; otherwise it fails.`
+
+func setupVerifyCommand() *cobraext.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <package zip>",
+		Short: "Verify the signature of a package",
+		Long:  verifyLongDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE:  verifyCommandAction,
+	}
+	cmd.Flags().String(cobraext.SignatureFlagName, "", cobraext.SignatureFlagDescription)
+	cmd.Flags().String(cobraext.SignerPublicKeyFlagName, "", cobraext.SignerPublicKeyFlagDescription)
+	cmd.MarkFlagRequired(cobraext.SignerPublicKeyFlagName)
+
+	return cobraext.NewCommand(cmd, cobraext.ContextGlobal)
+}
+
+func verifyCommandAction(cmd *cobra.Command, args []string) error {
+	packagePath := args[0]
+
+	signaturePath, err := cmd.Flags().GetString(cobraext.SignatureFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.SignatureFlagName)
+	}
+	if signaturePath == "" {
+		signaturePath = packagePath + ".sig"
+	}
+
+	publicKeyPath, err := cmd.Flags().GetString(cobraext.SignerPublicKeyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.SignerPublicKeyFlagName)
+	}
+
+	fingerprint, err := files.VerifySignature(packagePath, signaturePath, publicKeyPath)
+	if err != nil {
+		return fmt.Errorf("signature verification failed (package: %s, signature: %s): %w", packagePath, signaturePath, err)
+	}
+
+	cmd.Printf("Signature verified: OK (key fingerprint: %s)\n", fingerprint)
+	return nil
+}
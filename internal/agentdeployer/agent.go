@@ -117,6 +117,7 @@ func (d *DockerComposeAgentDeployer) SetUp(ctx context.Context, agentInfo AgentI
 		fmt.Sprintf("%s=%s", localCACertEnv, caCertPath),
 		fmt.Sprintf("%s=%s", fleetPolicyEnv, d.policyName),
 		fmt.Sprintf("%s=%s", agentHostnameEnv, d.agentHostname()),
+		fmt.Sprintf("%s=%s", elasticAgentTagsEnv, strings.Join(agentInfo.Agent.Tags, ",")),
 	)
 
 	configDir, err := d.installDockerCompose(ctx, agentInfo)
@@ -196,6 +197,7 @@ func (d *DockerComposeAgentDeployer) SetUp(ctx context.Context, agentInfo AgentI
 	// using the ContainerName of the agent (p.ContainerName(agentName)) as in servicedeployer does not work,
 	// probably because it is in another compose project in case of ti_anomali?.
 	agentInfo.Hostname = d.agentHostname()
+	agentInfo.ContainerName = p.ContainerName(agentName)
 
 	logger.Debugf("adding service container %s internal ports to context", p.ContainerName(agentName))
 	serviceComposeConfig, err := p.Config(ctx, compose.CommandOptions{Env: env})
@@ -285,6 +287,13 @@ func (d *DockerComposeAgentDeployer) installDockerCompose(ctx context.Context, a
 	if err != nil {
 		return "", nil
 	}
+	if agentInfo.Agent.Image != "" {
+		err := docker.Pull(agentInfo.Agent.Image)
+		if err != nil {
+			return "", fmt.Errorf("can't pull Elastic Agent image %q: %w", agentInfo.Agent.Image, err)
+		}
+		agentImage = agentInfo.Agent.Image
+	}
 
 	resourceManager := resource.NewManager()
 	resourceManager.AddFacter(resource.StaticFacter{
@@ -294,6 +303,7 @@ func (d *DockerComposeAgentDeployer) installDockerCompose(ctx context.Context, a
 		"runtime":                agentInfo.Agent.Runtime,
 		"pid_mode":               agentInfo.Agent.PidMode,
 		"ports":                  strings.Join(agentInfo.Agent.Ports, ","),
+		"tags":                   strings.Join(agentInfo.Agent.Tags, ","),
 		"dockerfile_hash":        hex.EncodeToString(hashDockerfile),
 		"stack_version":          stackVersion,
 		"fleet_url":              fleetURL,
@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agentdeployer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/compose"
+	"github.com/elastic/elastic-package/internal/docker"
+)
+
+// DiagnosticsCollector is implemented by deployed agents that can collect a diagnostics bundle.
+type DiagnosticsCollector interface {
+	// CollectDiagnostics collects a diagnostics bundle of the agent into the given directory,
+	// and returns the path to the bundle.
+	CollectDiagnostics(ctx context.Context, outputDir string) (string, error)
+}
+
+var _ DiagnosticsCollector = new(dockerComposeDeployedAgent)
+
+// CollectDiagnostics collects a diagnostics bundle from the agent container using "elastic-agent diagnostics".
+func (s *dockerComposeDeployedAgent) CollectDiagnostics(ctx context.Context, outputDir string) (string, error) {
+	p, err := compose.NewProject(s.project, s.ymlPaths...)
+	if err != nil {
+		return "", fmt.Errorf("could not create Docker Compose project for agent: %w", err)
+	}
+	containerName := p.ContainerName(s.agentInfo.Name)
+
+	bundleName := fmt.Sprintf("%s-diagnostics-%d.zip", s.agentInfo.Name, time.Now().UnixNano())
+	containerPath := "/tmp/" + bundleName
+	_, err = docker.Exec(ctx, containerName, "elastic-agent", "diagnostics", "--file", containerPath)
+	if err != nil {
+		return "", fmt.Errorf("could not collect diagnostics from agent: %w", err)
+	}
+
+	err = os.MkdirAll(outputDir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("can't create directory for agent diagnostics (path: %s): %w", outputDir, err)
+	}
+
+	bundlePath := filepath.Join(outputDir, bundleName)
+	err = docker.Copy(containerName, containerPath, bundlePath)
+	if err != nil {
+		return "", fmt.Errorf("could not copy diagnostics bundle from agent: %w", err)
+	}
+	return bundlePath, nil
+}
@@ -28,6 +28,8 @@ type AgentSettings struct {
 	User string `config:"user"`
 	// BaseImage elastic-agent base image to be used for testing
 	BaseImage string `config:"base_image"`
+	// Image overrides the elastic-agent Docker image to be used for testing
+	Image string `config:"image"`
 	// PidMode selects the host PID mode
 	// (From docker-compose docs) Turns on sharing between container and the host
 	// operating system the PID address space
@@ -44,6 +46,9 @@ type AgentSettings struct {
 	// PreStartScript allows to define a script to update/modify Elastic Agent process (container, vm, ...)
 	// Example update environment variables like PATH
 	PreStartScript AgentScript `config:"pre_start_script"`
+	// Tags are added to the Elastic Agent when it is enrolled in Fleet, they are used to select
+	// the agent for the test
+	Tags []string `config:"tags"`
 }
 
 // AgentInfo encapsulates context that is both available to a AgentDeployer and
@@ -61,6 +66,9 @@ type AgentInfo struct {
 	// required to connect the Service with the agent.
 	NetworkName string
 
+	// ContainerName is the name of the container running the agent, if any.
+	ContainerName string
+
 	// Agent Policy related properties
 	Policy struct {
 		// Name is the name of the test Agent Policy created for the given agent
@@ -230,6 +230,7 @@ func getElasticAgentYAML(ctx context.Context, profile *profile.Profile, agentInf
 		"elasticAgentImage":           appConfig.StackImageRefs().ElasticAgent,
 		"elasticAgentTokenPolicyName": getTokenPolicyName(stackVersion, agentInfo.Policy.Name),
 		"agentName":                   agentName,
+		"tags":                        strings.Join(agentInfo.Agent.Tags, ","),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't generate elastic agent manifest: %w", err)
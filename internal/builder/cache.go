@@ -0,0 +1,160 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/version"
+)
+
+const buildCacheFolder = "cache"
+
+// hashSkippedDirs are the directories, relative to the package root, that are not part of the
+// package sources, and are not considered in the hash of the package contents. The build directory
+// changes with each build.
+var hashSkippedDirs = []string{"build", ".git"}
+
+// packageContentHash calculates a hash of the package sources and of everything else that
+// affects the built package, so it can be compared with the one of the last build.
+func packageContentHash(options BuildOptions) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "elastic-package: %s %s\n", version.Tag, version.CommitHash)
+	fmt.Fprintf(h, "zip: %t, sign: %t, dump injected fields: %t, skip validation: %t\n", options.CreateZip, options.SignPackage, options.DumpInjectedFields, options.SkipValidation)
+
+	err := hashRepositoryLicense(h)
+	if err != nil {
+		return "", err
+	}
+
+	err = filepath.WalkDir(options.PackageRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(options.PackageRoot, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if d.IsDir() {
+			if slices.Contains(hashSkippedDirs, relPath) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		fmt.Fprintf(h, "file: %s\n", relPath)
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't calculate hash of package contents (path: %s): %w", options.PackageRoot, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashRepositoryLicense adds to the hash the license text of the repository, that is included in
+// packages that don't have their own license file.
+func hashRepositoryLicense(h io.Writer) error {
+	repositoryLicenseTextFileName, userDefined := os.LookupEnv(repositoryLicenseEnv)
+	if !userDefined {
+		repositoryLicenseTextFileName = licenseTextFileName
+	}
+	fmt.Fprintf(h, "license: %s\n", repositoryLicenseTextFileName)
+
+	licensePath, err := findRepositoryLicense(repositoryLicenseTextFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't look for license %q in repository: %w", repositoryLicenseTextFileName, err)
+	}
+	f, err := os.Open(licensePath)
+	if err != nil {
+		return fmt.Errorf("can't read repository license (path: %s): %w", licensePath, err)
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("can't read repository license (path: %s): %w", licensePath, err)
+	}
+	return nil
+}
+
+// buildHashPath returns the path of the file where the hash of the last build of the package is stored.
+func buildHashPath(packageRoot string) (string, error) {
+	buildDir, err := BuildDirectory()
+	if err != nil {
+		return "", fmt.Errorf("can't locate build directory: %w", err)
+	}
+	m, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return "", fmt.Errorf("reading package manifest failed (path: %s): %w", packageRoot, err)
+	}
+	return filepath.Join(buildDir, buildCacheFolder, fmt.Sprintf("%s-%s.sha256", m.Name, m.Version)), nil
+}
+
+// readBuildHash returns the hash of the last build, or an empty string if there is none.
+func readBuildHash(hashPath string) (string, error) {
+	d, err := os.ReadFile(hashPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("can't read build hash (path: %s): %w", hashPath, err)
+	}
+	return strings.TrimSpace(string(d)), nil
+}
+
+func writeBuildHash(hashPath, hash string) error {
+	err := os.MkdirAll(filepath.Dir(hashPath), 0755)
+	if err != nil {
+		return fmt.Errorf("can't create build cache directory: %w", err)
+	}
+	err = os.WriteFile(hashPath, []byte(hash+"\n"), 0644)
+	if err != nil {
+		return fmt.Errorf("can't write build hash (path: %s): %w", hashPath, err)
+	}
+	return nil
+}
+
+func removeBuildHash(hashPath string) error {
+	err := os.Remove(hashPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("can't remove build hash (path: %s): %w", hashPath, err)
+	}
+	return nil
+}
+
+// builtPackageTarget returns the path of the package built with the given options.
+func builtPackageTarget(options BuildOptions) (string, error) {
+	if options.CreateZip {
+		return buildPackagesZipPath(options.PackageRoot)
+	}
+	return BuildPackagesDirectory(options.PackageRoot)
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestPackageContentHash(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, "manifest.yml", "name: foo\n")
+	filestest.WriteFile(t, packageRoot, "data_stream/foo/manifest.yml", "title: Foo\n")
+
+	options := BuildOptions{PackageRoot: packageRoot}
+	hash, err := packageContentHash(options)
+	require.NoError(t, err)
+
+	sameHash, err := packageContentHash(options)
+	require.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	zipHash, err := packageContentHash(BuildOptions{PackageRoot: packageRoot, CreateZip: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, zipHash)
+
+	skipValidationHash, err := packageContentHash(BuildOptions{PackageRoot: packageRoot, SkipValidation: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, skipValidationHash)
+
+	for _, dir := range []string{"build", ".git"} {
+		filestest.WriteFile(t, packageRoot, filepath.Join(dir, "artifact"), "foo")
+	}
+	ignoredHash, err := packageContentHash(options)
+	require.NoError(t, err)
+	assert.Equal(t, hash, ignoredHash)
+
+	filestest.WriteFile(t, packageRoot, "_dev/build/build.yml", "dependencies:\n  ecs:\n    reference: git@v8.17.0\n")
+	buildManifestHash, err := packageContentHash(options)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, buildManifestHash)
+
+	filestest.WriteFile(t, packageRoot, "manifest.yml", "name: bar\n")
+	changedHash, err := packageContentHash(options)
+	require.NoError(t, err)
+	assert.NotEqual(t, buildManifestHash, changedHash)
+}
+
+func TestPackageContentHashRepositoryLicense(t *testing.T) {
+	repositoryRoot := t.TempDir()
+	packageRoot := filepath.Join(repositoryRoot, "packages", "foo")
+	require.NoError(t, os.MkdirAll(filepath.Join(repositoryRoot, ".git"), 0755))
+	filestest.WriteFile(t, packageRoot, "manifest.yml", "name: foo\n")
+	filestest.WriteFile(t, repositoryRoot, licenseTextFileName, "Some license\n")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(packageRoot))
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+
+	options := BuildOptions{PackageRoot: packageRoot}
+	hash, err := packageContentHash(options)
+	require.NoError(t, err)
+
+	filestest.WriteFile(t, repositoryRoot, licenseTextFileName, "Other license\n")
+	changedHash, err := packageContentHash(options)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestBuildHash(t *testing.T) {
+	hashPath := filepath.Join(t.TempDir(), buildCacheFolder, "foo-1.0.0.sha256")
+
+	hash, err := readBuildHash(hashPath)
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	require.NoError(t, writeBuildHash(hashPath, "abcd"))
+	hash, err = readBuildHash(hashPath)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", hash)
+
+	require.NoError(t, removeBuildHash(hashPath))
+	require.NoError(t, removeBuildHash(hashPath))
+	hash, err = readBuildHash(hashPath)
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+}
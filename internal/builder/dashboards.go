@@ -55,6 +55,12 @@ func encodeDashboards(destinationDir string) error {
 	return nil
 }
 
+// EncodeSavedObject encodes the fields of a saved object as they are encoded in built packages.
+func EncodeSavedObject(data []byte) ([]byte, error) {
+	output, _, err := encodeSavedObject(data)
+	return output, err
+}
+
 // encodeSavedObject encodes all the fields inside a saved object
 // which are stored in encoded JSON in Kibana.
 // The reason is that for versioning it is much nicer to have the full
@@ -22,7 +22,10 @@ import (
 
 var semver3_0_0 = semver.MustParse("3.0.0")
 
-func resolveExternalFields(packageRoot, destinationDir string) error {
+// resolveExternalFields injects the definitions of external fields in the fields files of the
+// built package. If dumpDir is not empty, the original and the injected versions of the modified
+// files are written there, under the "original" and "injected" directories.
+func resolveExternalFields(packageRoot, destinationDir, dumpDir string) error {
 	bm, ok, err := buildmanifest.ReadBuildManifest(packageRoot)
 	if err != nil {
 		return fmt.Errorf("can't read build manifest: %w", err)
@@ -77,6 +80,13 @@ func resolveExternalFields(packageRoot, destinationDir string) error {
 			if err != nil {
 				return err
 			}
+
+			if dumpDir != "" {
+				err = dumpInjectedFields(dumpDir, rel, data, output)
+				if err != nil {
+					return fmt.Errorf("can't dump injected fields of %s: %w", rel, err)
+				}
+			}
 		} else {
 			logger.Debugf("%s: source file hasn't been changed", rel)
 		}
@@ -85,6 +95,21 @@ func resolveExternalFields(packageRoot, destinationDir string) error {
 	return nil
 }
 
+func dumpInjectedFields(dumpDir, rel string, original, injected []byte) error {
+	for dir, content := range map[string][]byte{"original": original, "injected": injected} {
+		path := filepath.Join(dumpDir, dir, rel)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func listAllFieldsFiles(dir string) ([]string, error) {
 	patterns := []string{
 		// Package fields
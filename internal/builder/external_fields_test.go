@@ -0,0 +1,31 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpInjectedFields(t *testing.T) {
+	dumpDir := t.TempDir()
+	rel := filepath.Join("data_stream", "foo", "fields", "ecs.yml")
+	original := []byte("- name: host.name\n  external: ecs\n")
+	injected := []byte("- name: host.name\n  type: keyword\n")
+
+	require.NoError(t, dumpInjectedFields(dumpDir, rel, original, injected))
+
+	d, err := os.ReadFile(filepath.Join(dumpDir, "original", rel))
+	require.NoError(t, err)
+	assert.Equal(t, original, d)
+
+	d, err = os.ReadFile(filepath.Join(dumpDir, "injected", rel))
+	require.NoError(t, err)
+	assert.Equal(t, injected, d)
+}
@@ -20,6 +20,7 @@ import (
 )
 
 const builtPackagesFolder = "packages"
+const injectedFieldsFolder = "injected-fields"
 const licenseTextFileName = "LICENSE.txt"
 
 var repositoryLicenseEnv = environment.WithElasticPackagePrefix("REPOSITORY_LICENSE")
@@ -30,6 +31,13 @@ type BuildOptions struct {
 	CreateZip      bool
 	SignPackage    bool
 	SkipValidation bool
+
+	// Incremental skips the build if the package sources haven't changed since the last build.
+	Incremental bool
+
+	// DumpInjectedFields writes the fields files modified by the injection of external fields,
+	// before and after the injection, to the build directory.
+	DumpInjectedFields bool
 }
 
 // BuildDirectory function locates the target build directory. If the directory doesn't exist, it will create it.
@@ -84,6 +92,20 @@ func BuildPackagesDirectory(packageRoot string) (string, error) {
 	return filepath.Join(buildDir, m.Name, m.Version), nil
 }
 
+// InjectedFieldsDirectory function locates the directory where the fields files modified by the
+// injection of external fields are dumped.
+func InjectedFieldsDirectory(packageRoot string) (string, error) {
+	buildDir, err := BuildDirectory()
+	if err != nil {
+		return "", fmt.Errorf("can't locate build directory: %w", err)
+	}
+	m, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return "", fmt.Errorf("reading package manifest failed (path: %s): %w", packageRoot, err)
+	}
+	return filepath.Join(buildDir, injectedFieldsFolder, m.Name, m.Version), nil
+}
+
 // buildPackagesZipPath function locates the target zipped package path.
 func buildPackagesZipPath(packageRoot string) (string, error) {
 	buildDir, err := buildPackagesRootDirectory()
@@ -141,8 +163,72 @@ func FindBuildPackagesDirectory() (string, bool, error) {
 	return "", false, nil
 }
 
-// BuildPackage function builds the package.
+// BuildPackage function builds the package. In incremental builds, the package is not built
+// again if its contents haven't changed since the last build.
 func BuildPackage(options BuildOptions) (string, error) {
+	hash, err := packageContentHash(options)
+	if err != nil {
+		return "", err
+	}
+	hashPath, err := buildHashPath(options.PackageRoot)
+	if err != nil {
+		return "", err
+	}
+
+	if options.Incremental {
+		target, upToDate, err := upToDateBuiltPackage(options, hashPath, hash)
+		if err != nil {
+			return "", err
+		}
+		if upToDate {
+			logger.Infof("Package has not changed since the last build, skipping build (path: %s)", target)
+			return target, nil
+		}
+	}
+
+	// Remove the hash of the previous build, so a failed build is not considered up to date.
+	err = removeBuildHash(hashPath)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := buildPackage(options)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeBuildHash(hashPath, hash)
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// upToDateBuiltPackage checks if the package was already built from the same contents.
+func upToDateBuiltPackage(options BuildOptions, hashPath, hash string) (string, bool, error) {
+	lastHash, err := readBuildHash(hashPath)
+	if err != nil {
+		return "", false, err
+	}
+	if lastHash != hash {
+		return "", false, nil
+	}
+
+	target, err := builtPackageTarget(options)
+	if err != nil {
+		return "", false, fmt.Errorf("can't locate built package: %w", err)
+	}
+	_, err = os.Stat(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("can't check built package (path: %s): %w", target, err)
+	}
+	return target, true, nil
+}
+
+func buildPackage(options BuildOptions) (string, error) {
 	destinationDir, err := BuildPackagesDirectory(options.PackageRoot)
 	if err != nil {
 		return "", fmt.Errorf("can't locate build directory: %w", err)
@@ -173,8 +259,20 @@ func BuildPackage(options BuildOptions) (string, error) {
 		return "", fmt.Errorf("encoding dashboards failed: %w", err)
 	}
 
+	var injectedFieldsDir string
+	if options.DumpInjectedFields {
+		injectedFieldsDir, err = InjectedFieldsDirectory(options.PackageRoot)
+		if err != nil {
+			return "", fmt.Errorf("can't locate directory for injected fields: %w", err)
+		}
+		err = os.RemoveAll(injectedFieldsDir)
+		if err != nil {
+			return "", fmt.Errorf("clearing directory for injected fields failed: %w", err)
+		}
+	}
+
 	logger.Debug("Resolve external fields")
-	err = resolveExternalFields(options.PackageRoot, destinationDir)
+	err = resolveExternalFields(options.PackageRoot, destinationDir, injectedFieldsDir)
 	if err != nil {
 		return "", fmt.Errorf("resolving external fields failed: %w", err)
 	}
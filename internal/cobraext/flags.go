@@ -28,6 +28,9 @@ const (
 
 // Flag names and descriptions used by CLI commands
 const (
+	AgentImageFlagName        = "agent-image"
+	AgentImageFlagDescription = "Docker image to use for independent Elastic Agents, overriding the one configured in tests"
+
 	AgentPolicyFlagName    = "agent-policy"
 	AgentPolicyDescription = "name of the agent policy"
 
@@ -88,6 +91,12 @@ const (
 	BenchStreamTimestampFieldFlagName        = "timestamp-field"
 	BenchStreamTimestampFieldFlagDescription = "name of the field that's used in the generator config as `@timestamp`"
 
+	BuildForceFlagName        = "force"
+	BuildForceFlagDescription = "build the package even if its contents haven't changed since the last build"
+
+	BuildDumpInjectedFieldsFlagName        = "dump-injected-fields"
+	BuildDumpInjectedFieldsFlagDescription = "write the fields files modified by the injection of external fields, before and after the injection, to the build directory"
+
 	BuildSkipValidationFlagName        = "skip-validation"
 	BuildSkipValidationFlagDescription = "skip validation of the built package, use only if all validation issues have been acknowledged"
 
@@ -121,6 +130,15 @@ const (
 	DataStreamFlagName        = "data-stream"
 	DataStreamFlagDescription = "use service stack related to the data stream"
 
+	CheckMigrationsFlagName        = "check-migrations"
+	CheckMigrationsFlagDescription = "import the saved objects of the package to check that they can be migrated to the version of Kibana under test"
+
+	CheckIdempotencyFlagName        = "check-idempotency"
+	CheckIdempotencyFlagDescription = "install the package twice and check that the installed assets don't change"
+
+	DiagnosticsOnFailureFlagName        = "diagnostics-on-failure"
+	DiagnosticsOnFailureFlagDescription = "collect a diagnostics bundle from the Elastic Agent when a test fails"
+
 	DataStreamsFlagName        = "data-streams"
 	DataStreamsFlagDescription = "comma-separated data streams to test"
 
@@ -130,14 +148,62 @@ const (
 	DumpOutputFlagName        = "output"
 	DumpOutputFlagDescription = "path to directory where exported assets will be stored"
 
+	ExplainFlagName        = "explain"
+	ExplainFlagDescription = "explain how to define undefined fields found in documents"
+
+	ExternalLinksFlagName        = "external-links"
+	ExternalLinksFlagDescription = "verify also that external links can be reached, requires network access"
+
+	FailOnAgentWarningsFlagName        = "fail-on-agent-warnings"
+	FailOnAgentWarningsFlagDescription = "fail if the Elastic Agent logs warning messages, they can be filtered in the global test configuration"
+
+	FailOnIgnoreMalformedFlagName        = "fail-on-ignore-malformed"
+	FailOnIgnoreMalformedFlagDescription = "fail if fields with ignore_malformed enabled ignored values in documents, even if they are listed in skip_ignored_fields"
+
 	FailOnMissingFlagName        = "fail-on-missing"
 	FailOnMissingFlagDescription = "fail if tests are missing"
 
+	FieldCoverageBadgeFlagName        = "badge"
+	FieldCoverageBadgeFlagDescription = "write an SVG badge with the field coverage of the package in the test coverage reports folder"
+
 	FailFastFlagName                  = "fail-fast"
 	FailFastFlagDescription           = "fail immediately if any file requires updates (do not overwrite)"
 	GenerateTestResultFlagName        = "generate"
 	GenerateTestResultFlagDescription = "generate test result file"
 
+	InteractiveFlagName        = "interactive"
+	InteractiveFlagDescription = "select the tests to run with an interactive prompt, flags are used instead in non-interactive environments"
+
+	KeepAgentFlagName        = "keep-agent"
+	KeepAgentFlagDescription = "keep the independent Elastic Agents running and enrolled after the tests, to debug them"
+
+	LinksFlagName        = "links"
+	LinksFlagDescription = "verify that links in documentation files can be resolved"
+
+	LintFlagName        = "lint"
+	LintFlagDescription = "enforce style rules in field definitions, besides checking that they can be parsed"
+
+	LintRulesFlagName        = "lint-rules"
+	LintRulesFlagDescription = "comma-separated list of rules enforced with --lint (%s)"
+
+	MappingsReportFlagName        = "mappings-report"
+	MappingsReportFlagDescription = "write a JSON report with the expected and actual mappings of each field to the build directory, when mappings are validated"
+
+	MaxLogSizeFlagName        = "max-log-size"
+	MaxLogSizeFlagDescription = "maximum size of the Elastic Agent logs kept on disk while looking for errors, bigger logs are scanned in chunks (e.g. 100MB, defaults to unlimited)"
+
+	NextECSReferenceFlagName        = "next-ecs-reference"
+	NextECSReferenceFlagDescription = "also validate documents with this version of ECS (e.g. git@v8.17.0), reporting fields that would fail after upgrading the ECS dependency"
+
+	MissingSampleEventsFlagName        = "missing-sample-events"
+	MissingSampleEventsFlagDescription = "how to report data streams without sample events (%s)"
+
+	MissingTestsFlagName        = "missing-tests"
+	MissingTestsFlagDescription = "how to report data streams without system or pipeline tests (%s)"
+
+	PrintPolicyFlagName        = "print-policy"
+	PrintPolicyFlagDescription = "print the package policies that would be used by the tests, without deploying services or enrolling agents"
+
 	ProfileFlagName        = "profile"
 	ProfileFlagDescription = "select a profile to use for the stack configuration. Can also be set with %s"
 
@@ -159,6 +225,12 @@ const (
 	ReportOutputPathFlagName        = "report-output-path"
 	ReportOutputPathFlagDescription = "output path for test report (defaults to %q in build directory)"
 
+	ReportPropertyFlagName        = "report-property"
+	ReportPropertyFlagDescription = "additional property to include in test reports that support them, as key=value"
+
+	ReportSuiteNameFlagName        = "report-suite-name"
+	ReportSuiteNameFlagDescription = "name of the test suite in test reports that support it (defaults to the test type)"
+
 	ShowAllFlagName        = "all"
 	ShowAllFlagDescription = "show all deployed package revisions"
 
@@ -166,12 +238,30 @@ const (
 	ShellInitShellDescription = "change output shell code compatibility. Use 'detect' to use integrated shell detection; suggested to not change unless detection is not working"
 	ShellInitShellDetect      = "auto"
 
+	SkipVersionCheckFlagName        = "skip-version-check"
+	SkipVersionCheckFlagDescription = "use this version of the stack (e.g. 8.17.0) instead of requesting it to Kibana, validations that depend on the version of the stack may be inaccurate if it is not the running version"
+
+	StrictIgnoredFieldsFlagName        = "strict-ignored-fields"
+	StrictIgnoredFieldsFlagDescription = "fail on any ignored field found in documents, ignoring the skip_ignored_fields setting of tests"
+
 	SignPackageFlagName        = "sign"
 	SignPackageFlagDescription = "sign package"
 
+	SignatureFlagName        = "signature"
+	SignatureFlagDescription = "path to the detached signature file (defaults to the package path with the .sig extension)"
+
+	SignerPublicKeyFlagName        = "key"
+	SignerPublicKeyFlagDescription = "path to the armored public key of the signer"
+
+	TerraformFlagName        = "terraform"
+	TerraformFlagDescription = "validate the definitions of the Terraform service deployer"
+
 	TLSSkipVerifyFlagName        = "tls-skip-verify"
 	TLSSkipVerifyFlagDescription = "skip TLS verify"
 
+	UpgradeFromFlagName        = "upgrade-from"
+	UpgradeFromFlagDescription = "install this version of the package from the registry and ingest documents before installing the package under test, to check that data streams can be upgraded"
+
 	StackProviderFlagName        = "provider"
 	StackProviderFlagDescription = "service provider to start a stack (%s)"
 
@@ -181,6 +271,12 @@ const (
 	StackVersionFlagName        = "version"
 	StackVersionFlagDescription = "stack version"
 
+	StackCleanForceFlagName        = "force"
+	StackCleanForceFlagDescription = "delete the resources found, otherwise they are only listed"
+
+	StackCleanStaleDataStreamsFlagName        = "stale-data-streams"
+	StackCleanStaleDataStreamsFlagDescription = "clean data streams created by tests that are older than the given age (e.g. 24h)"
+
 	StackDumpOutputFlagName        = "output"
 	StackDumpOutputFlagDescription = "output location for the stack dump"
 
@@ -203,12 +299,21 @@ const (
 	TestCoverageFormatFlagName        = "coverage-format"
 	TestCoverageFormatFlagDescription = "set format for coverage reports: %s"
 
+	ExportDocsFlagName        = "export-docs"
+	ExportDocsFlagDescription = "path of an NDJSON file where the documents ingested during the system test are exported, to reuse them as test fixtures"
+
+	ValidateOnlyFlagName        = "validate-only"
+	ValidateOnlyFlagDescription = "name of an existing data stream whose documents are validated against the fields of the package, without running the tests (e.g. logs-nginx.access-ep)"
+
 	VariantFlagName        = "variant"
 	VariantFlagDescription = "service variant"
 
 	ConfigFileFlagName        = "config-file"
 	ConfigFileFlagDescription = "configuration file to setup service and test"
 
+	ConfigNameFlagName        = "config-name"
+	ConfigNameFlagDescription = "name of the test configuration to run (e.g. \"default\" for test-default-config.yml)"
+
 	SetupFlagName        = "setup"
 	SetupFlagDescription = "trigger just the setup phase of testing"
 
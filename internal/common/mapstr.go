@@ -127,6 +127,27 @@ func (m MapStr) Delete(key string) error {
 	return nil
 }
 
+// Flatten returns a copy of the MapStr with the values of nested maps stored in
+// dotted keys. Slices are not flattened.
+func (m MapStr) Flatten() MapStr {
+	flat := make(MapStr)
+	m.flatten("", flat)
+	return flat
+}
+
+func (m MapStr) flatten(prefix string, flat MapStr) {
+	for key, value := range m {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := tryToMapStr(value); ok {
+			nested.flatten(key, flat)
+			continue
+		}
+		flat[key] = value
+	}
+}
+
 // StringToPrint returns the MapStr as pretty JSON.
 func (m MapStr) StringToPrint() string {
 	j, err := json.MarshalIndent(m, "", "  ")
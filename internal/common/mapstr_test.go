@@ -62,3 +62,66 @@ func TestMapStrGetValue(t *testing.T) {
 		})
 	}
 }
+
+func TestMapStrFlatten(t *testing.T) {
+	cases := []struct {
+		title    string
+		doc      MapStr
+		expected MapStr
+	}{
+		{
+			title: "document with nested maps and dotted keys",
+			doc: MapStr{
+				"@timestamp": "2020-04-28T11:07:58.223Z",
+				"event": map[string]any{
+					"category": []any{"web"},
+					"original": "GET /",
+				},
+				"url.original": "/",
+			},
+			expected: MapStr{
+				"@timestamp":     "2020-04-28T11:07:58.223Z",
+				"event.category": []any{"web"},
+				"event.original": "GET /",
+				"url.original":   "/",
+			},
+		},
+		{
+			title: "nested MapStr",
+			doc: MapStr{
+				"host": MapStr{
+					"os": MapStr{
+						"name": "linux",
+					},
+					"ip": []any{"127.0.0.1", "::1"},
+				},
+			},
+			expected: MapStr{
+				"host.os.name": "linux",
+				"host.ip":      []any{"127.0.0.1", "::1"},
+			},
+		},
+		{
+			title: "slices of objects are not flattened",
+			doc: MapStr{
+				"process": map[string]any{
+					"args": []any{map[string]any{"name": "foo"}},
+				},
+			},
+			expected: MapStr{
+				"process.args": []any{map[string]any{"name": "foo"}},
+			},
+		},
+		{
+			title:    "empty document",
+			doc:      MapStr{},
+			expected: MapStr{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.doc.Flatten())
+		})
+	}
+}
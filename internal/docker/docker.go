@@ -6,6 +6,7 @@ package docker
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -178,6 +179,41 @@ func InspectContainers(containerIDs ...string) ([]ContainerDescription, error) {
 	return containerDescriptions, nil
 }
 
+// ImageRepoDigests function returns the repository digests of a local image.
+func ImageRepoDigests(image string) ([]string, error) {
+	cmd := exec.Command("docker", "image", "inspect", "--format", "{{json .RepoDigests}}", image)
+	errOutput := new(bytes.Buffer)
+	cmd.Stderr = errOutput
+
+	logger.Debugf("output command: %s", cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not inspect image %s (stderr=%q): %w", image, errOutput.String(), err)
+	}
+
+	var repoDigests []string
+	err = json.Unmarshal(output, &repoDigests)
+	if err != nil {
+		return nil, fmt.Errorf("can't unmarshal repository digests of image %s: %w", image, err)
+	}
+	return repoDigests, nil
+}
+
+// Exec function runs a command in the given container and returns its output.
+func Exec(ctx context.Context, containerName string, command ...string) ([]byte, error) {
+	args := append([]string{"exec", containerName}, command...)
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	errOutput := new(bytes.Buffer)
+	cmd.Stderr = errOutput
+
+	logger.Debugf("output command: %s", cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not run command in the container (stderr=%q): %w", errOutput.String(), err)
+	}
+	return output, nil
+}
+
 // Copy function copies resources from the container to the local destination.
 func Copy(containerName, containerPath, localPath string) error {
 	cmd := exec.Command("docker", "cp", containerName+":"+containerPath, localPath)
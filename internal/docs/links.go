@@ -0,0 +1,228 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package docs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const externalLinkCheckTimeout = 10 * time.Second
+
+var (
+	inlineLinkPattern    = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	referenceLinkPattern = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*<?(\S+?)>?(?:\s+"[^"]*")?\s*$`)
+	headingPattern       = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+	htmlAnchorPattern    = regexp.MustCompile(`<a\s+(?:name|id)="([^"]+)"`)
+	inlineCodePattern    = regexp.MustCompile("`[^`]*`")
+)
+
+// LinksCheckOptions are the options to check the links of the documentation files.
+type LinksCheckOptions struct {
+	// External enables the verification of external links, what requires network access.
+	External bool
+
+	// HTTPClient is the client used to check external links, a default client is used if nil.
+	HTTPClient *http.Client
+}
+
+type docLink struct {
+	line   int
+	target string
+}
+
+type docFile struct {
+	links   []docLink
+	anchors map[string]bool
+}
+
+// CheckLinks looks for broken links in the documentation files of the package. Internal anchors and
+// links to other files of the package are always verified, external links are only verified if enabled
+// in the options, and they are considered broken if they are not found. Broken links are reported
+// with the line of the file where they are found.
+func CheckLinks(ctx context.Context, packageRoot string, options LinksCheckOptions) ([]packages.Problem, error) {
+	docFiles, err := filepath.Glob(filepath.Join(docsPath(packageRoot), "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("reading directory entries failed: %w", err)
+	}
+
+	client := options.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: externalLinkCheckTimeout}
+	}
+
+	parsed := make(map[string]*docFile)
+	var broken []packages.Problem
+	for _, path := range docFiles {
+		doc, err := parseDocFile(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed[path] = doc
+
+		for _, link := range doc.links {
+			reason, err := checkLink(ctx, client, path, link.target, parsed, options.External)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				broken = append(broken, packages.Problem{
+					Path:    fmt.Sprintf("%s:%d", path, link.line),
+					Message: fmt.Sprintf("broken link %q: %s", link.target, reason),
+				})
+			}
+		}
+	}
+	return broken, nil
+}
+
+func checkLink(ctx context.Context, client *http.Client, path, target string, parsed map[string]*docFile, external bool) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Sprintf("invalid URL: %s", err), nil
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if !external {
+			return "", nil
+		}
+		return checkExternalLink(ctx, client, target), nil
+	case "":
+	default:
+		// Other schemes, like mailto, are not verified.
+		return "", nil
+	}
+
+	targetPath := path
+	if u.Path != "" {
+		targetPath = filepath.Join(filepath.Dir(path), filepath.FromSlash(u.Path))
+		_, err := os.Stat(targetPath)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Sprintf("file %s not found", targetPath), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("can't stat linked file (path: %s): %w", targetPath, err)
+		}
+	}
+	if u.Fragment == "" || filepath.Ext(targetPath) != ".md" {
+		return "", nil
+	}
+
+	doc, found := parsed[targetPath]
+	if !found {
+		doc, err = parseDocFile(targetPath)
+		if err != nil {
+			return "", err
+		}
+		parsed[targetPath] = doc
+	}
+	if !doc.anchors[strings.ToLower(u.Fragment)] {
+		return fmt.Sprintf("anchor #%s not found", u.Fragment), nil
+	}
+	return "", nil
+}
+
+func checkExternalLink(ctx context.Context, client *http.Client, target string) string {
+	status, err := requestStatus(ctx, client, http.MethodHead, target)
+	if err == nil && status == http.StatusMethodNotAllowed {
+		status, err = requestStatus(ctx, client, http.MethodGet, target)
+	}
+	if err != nil {
+		return fmt.Sprintf("request failed: %s", err)
+	}
+	if status == http.StatusNotFound || status == http.StatusGone {
+		return fmt.Sprintf("status code %d", status)
+	}
+	return ""
+}
+
+func requestStatus(ctx context.Context, client *http.Client, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// parseDocFile extracts the links and the anchors of a markdown file. Code blocks are ignored.
+func parseDocFile(path string) (*docFile, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read documentation file (path: %s): %w", path, err)
+	}
+
+	doc := docFile{anchors: make(map[string]bool)}
+	headings := make(map[string]int)
+	inCodeBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(d))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			anchor := headingAnchor(match[1])
+			if n := headings[anchor]; n > 0 {
+				doc.anchors[fmt.Sprintf("%s-%d", anchor, n)] = true
+			} else {
+				doc.anchors[anchor] = true
+			}
+			headings[anchor]++
+		}
+		for _, match := range htmlAnchorPattern.FindAllStringSubmatch(line, -1) {
+			doc.anchors[strings.ToLower(match[1])] = true
+		}
+
+		line = inlineCodePattern.ReplaceAllString(line, "")
+		for _, match := range inlineLinkPattern.FindAllStringSubmatch(line, -1) {
+			doc.links = append(doc.links, docLink{line: lineNumber, target: match[1]})
+		}
+		if match := referenceLinkPattern.FindStringSubmatch(line); match != nil {
+			doc.links = append(doc.links, docLink{line: lineNumber, target: match[1]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read documentation file (path: %s): %w", path, err)
+	}
+	return &doc, nil
+}
+
+// headingAnchor generates the anchor of a heading, as done by GitHub and other markdown renderers.
+func headingAnchor(heading string) string {
+	var anchor strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			anchor.WriteRune(r)
+		case r == ' ':
+			anchor.WriteRune('-')
+		}
+	}
+	return anchor.String()
+}
@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package docs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestCheckLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, "img/screenshot.png", "")
+
+	readmePath := filestest.WriteFile(t, packageRoot, "docs/README.md", fmt.Sprintf(`# Test Integration
+
+See [setup](#setup), [compatibility](#compatibility) and [the other setup](#setup-1).
+
+## Setup
+
+![Screenshot](../img/screenshot.png) ![Missing](../img/missing.png)
+
+## Setup
+
+More details in [other docs](other.md#details) and [wrong section](other.md#nothing).
+
+`+"```"+`
+[not a link](#ignored)
+`+"```"+`
+
+`+"`[not a link](#ignored)`"+`
+
+Vendor docs: [found](%[1]s/found), [missing](%[1]s/missing).
+
+[reference]: %[1]s/missing
+`, server.URL))
+	filestest.WriteFile(t, packageRoot, "docs/other.md", "# Other\n\n## Details\n")
+
+	t.Run("internal links", func(t *testing.T) {
+		broken, err := CheckLinks(context.Background(), packageRoot, LinksCheckOptions{})
+		require.NoError(t, err)
+
+		expected := []packages.Problem{
+			{Path: readmePath + ":3", Message: `broken link "#compatibility": anchor #compatibility not found`},
+			{Path: readmePath + ":7", Message: fmt.Sprintf(`broken link "../img/missing.png": file %s not found`, filepath.Join(packageRoot, "img", "missing.png"))},
+			{Path: readmePath + ":11", Message: `broken link "other.md#nothing": anchor #nothing not found`},
+		}
+		assert.Equal(t, expected, broken)
+	})
+
+	t.Run("external links", func(t *testing.T) {
+		broken, err := CheckLinks(context.Background(), packageRoot, LinksCheckOptions{External: true})
+		require.NoError(t, err)
+
+		require.Len(t, broken, 5)
+		message := fmt.Sprintf("broken link %q: status code 404", server.URL+"/missing")
+		assert.Equal(t, packages.Problem{Path: readmePath + ":19", Message: message}, broken[3])
+		assert.Equal(t, packages.Problem{Path: readmePath + ":21", Message: message}, broken[4])
+	})
+}
@@ -182,6 +182,21 @@ func findReadmeTemplatePath(fileName, packageRoot string) (string, bool, error)
 func renderReadme(fileName, packageRoot, templatePath string, linksMap linkMap) ([]byte, error) {
 	logger.Debugf("Render %s file (package: %s, templatePath: %s)", fileName, packageRoot, templatePath)
 
+	t, err := parseReadmeTemplate(fileName, packageRoot, templatePath, linksMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered bytes.Buffer
+	err = t.Execute(&rendered, nil)
+	if err != nil {
+		return nil, fmt.Errorf("executing template failed: %w", err)
+	}
+	return rendered.Bytes(), nil
+}
+
+// parseReadmeTemplate parses a README template with the functions available to render it.
+func parseReadmeTemplate(fileName, packageRoot, templatePath string, linksMap linkMap) (*template.Template, error) {
 	t := template.New(fileName)
 	t, err := t.Funcs(template.FuncMap{
 		"event": func(args ...string) (string, error) {
@@ -208,13 +223,7 @@ func renderReadme(fileName, packageRoot, templatePath string, linksMap linkMap)
 	if err != nil {
 		return nil, fmt.Errorf("parsing README template failed (path: %s): %w", templatePath, err)
 	}
-
-	var rendered bytes.Buffer
-	err = t.Execute(&rendered, nil)
-	if err != nil {
-		return nil, fmt.Errorf("executing template failed: %w", err)
-	}
-	return rendered.Bytes(), nil
+	return t, nil
 }
 
 func readReadme(fileName, packageRoot string) ([]byte, bool, error) {
@@ -6,10 +6,13 @@ package docs
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/template/parse"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -20,12 +23,7 @@ import (
 const sampleEventFile = "sample_event.json"
 
 func renderSampleEvent(packageRoot, dataStreamName string) (string, error) {
-	var eventPath string
-	if dataStreamName == "" {
-		eventPath = filepath.Join(packageRoot, sampleEventFile)
-	} else {
-		eventPath = filepath.Join(packageRoot, "data_stream", dataStreamName, sampleEventFile)
-	}
+	eventPath := sampleEventPath(packageRoot, dataStreamName)
 
 	body, err := os.ReadFile(eventPath)
 	if err != nil {
@@ -65,3 +63,142 @@ func stripDataStreamFolderSuffix(dataStreamName string) string {
 	dataStreamName = strings.ReplaceAll(dataStreamName, "_logs", "")
 	return dataStreamName
 }
+
+// CheckSampleEvents looks for sample events referenced with the "event" function in the
+// README templates of the package, and reports the ones whose sample_event.json file doesn't exist.
+func CheckSampleEvents(packageRoot string) ([]packages.Problem, error) {
+	templatePaths, err := filepath.Glob(filepath.Join(packageRoot, "_dev", "build", "docs", "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("reading directory entries failed: %w", err)
+	}
+
+	var missing []packages.Problem
+	for _, templatePath := range templatePaths {
+		dataStreams, err := referencedSampleEvents(packageRoot, templatePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, dataStream := range dataStreams {
+			eventPath := sampleEventPath(packageRoot, dataStream)
+			_, err := os.Stat(eventPath)
+			if errors.Is(err, os.ErrNotExist) {
+				missing = append(missing, packages.Problem{
+					Path:    templatePath,
+					Message: missingSampleEventMessage(dataStream, eventPath),
+				})
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("can't stat sample event file (path: %s): %w", eventPath, err)
+			}
+		}
+	}
+	return missing, nil
+}
+
+func missingSampleEventMessage(dataStream, eventPath string) string {
+	if dataStream == "" {
+		return fmt.Sprintf("references the package sample event, but %s is missing", eventPath)
+	}
+	return fmt.Sprintf("references the sample event of data stream %q, but %s is missing", dataStream, eventPath)
+}
+
+// DataStreamsWithoutSampleEvent returns the names of the data streams of the package that don't
+// have a sample event.
+func DataStreamsWithoutSampleEvent(packageRoot string) ([]string, error) {
+	dataStreamPaths, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data streams: %w", err)
+	}
+
+	var missing []string
+	for _, dataStreamPath := range dataStreamPaths {
+		dataStream := filepath.Base(dataStreamPath)
+		eventPath := sampleEventPath(packageRoot, dataStream)
+		_, err := os.Stat(eventPath)
+		if errors.Is(err, os.ErrNotExist) {
+			missing = append(missing, dataStream)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't stat sample event file (path: %s): %w", eventPath, err)
+		}
+	}
+	return missing, nil
+}
+
+// referencedSampleEvents returns the names of the data streams whose sample events are rendered
+// by the given README template. An empty name refers to the package-level sample event.
+func referencedSampleEvents(packageRoot, templatePath string) ([]string, error) {
+	// Functions are not executed, so links don't need to be resolved.
+	t, err := parseReadmeTemplate(filepath.Base(templatePath), packageRoot, templatePath, linkMap{})
+	if err != nil {
+		return nil, err
+	}
+
+	var dataStreams []string
+	walkTemplateCommands(t.Tree.Root, func(cmd *parse.CommandNode) {
+		if len(cmd.Args) == 0 {
+			return
+		}
+		if ident, ok := cmd.Args[0].(*parse.IdentifierNode); !ok || ident.Ident != "event" {
+			return
+		}
+		dataStream := ""
+		if len(cmd.Args) > 1 {
+			arg, ok := cmd.Args[1].(*parse.StringNode)
+			if !ok {
+				// Non-literal arguments can't be resolved statically.
+				return
+			}
+			dataStream = arg.Text
+		}
+		if !slices.Contains(dataStreams, dataStream) {
+			dataStreams = append(dataStreams, dataStream)
+		}
+	})
+	return dataStreams, nil
+}
+
+func walkTemplateCommands(node parse.Node, fn func(*parse.CommandNode)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplateCommands(child, fn)
+		}
+	case *parse.ActionNode:
+		walkTemplateCommands(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			fn(cmd)
+			for _, arg := range cmd.Args {
+				walkTemplateCommands(arg, fn)
+			}
+		}
+	case *parse.IfNode:
+		walkTemplateCommands(n.Pipe, fn)
+		walkTemplateCommands(n.List, fn)
+		walkTemplateCommands(n.ElseList, fn)
+	case *parse.RangeNode:
+		walkTemplateCommands(n.Pipe, fn)
+		walkTemplateCommands(n.List, fn)
+		walkTemplateCommands(n.ElseList, fn)
+	case *parse.WithNode:
+		walkTemplateCommands(n.Pipe, fn)
+		walkTemplateCommands(n.List, fn)
+		walkTemplateCommands(n.ElseList, fn)
+	}
+}
+
+func sampleEventPath(packageRoot, dataStreamName string) string {
+	if dataStreamName == "" {
+		return filepath.Join(packageRoot, sampleEventFile)
+	}
+	return filepath.Join(packageRoot, "data_stream", dataStreamName, sampleEventFile)
+}
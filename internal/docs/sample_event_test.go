@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package docs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestCheckSampleEvents(t *testing.T) {
+	cases := []struct {
+		title                  string
+		readmeTemplateContents string
+		sampleEvents           []string
+		expected               []string
+	}{
+		{
+			title:                  "No events referenced",
+			readmeTemplateContents: "# README\n{{fields \"example\"}}",
+		},
+		{
+			title:                  "Referenced sample event exists",
+			readmeTemplateContents: "# README\n{{event \"example\"}}",
+			sampleEvents:           []string{"example"},
+		},
+		{
+			title:                  "Referenced sample event is missing",
+			readmeTemplateContents: "# README\n{{event \"example\"}}\n{{event \"other\"}}",
+			sampleEvents:           []string{"other"},
+			expected:               []string{"example"},
+		},
+		{
+			title:                  "Package sample event is missing",
+			readmeTemplateContents: "# README\n{{ if true }}{{event}}{{ end }}",
+			expected:               []string{""},
+		},
+		{
+			title:                  "Duplicated references are reported once",
+			readmeTemplateContents: "# README\n{{event \"example\"}}\n{{event \"example\"}}",
+			expected:               []string{"example"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			packageRoot := t.TempDir()
+			templatePath := filestest.WriteFile(t, packageRoot, "_dev/build/docs/README.md", c.readmeTemplateContents)
+			for _, dataStream := range c.sampleEvents {
+				filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", dataStream, sampleEventFile), "{}")
+			}
+
+			missing, err := CheckSampleEvents(packageRoot)
+			require.NoError(t, err)
+
+			require.Len(t, missing, len(c.expected))
+			for i, dataStream := range c.expected {
+				assert.Equal(t, templatePath, missing[i].Path)
+				assert.Contains(t, missing[i].Message, sampleEventPath(packageRoot, dataStream))
+			}
+		})
+	}
+}
+
+func TestDataStreamsWithoutSampleEvent(t *testing.T) {
+	packageRoot := t.TempDir()
+	for _, dataStream := range []string{"access", "error", "status"} {
+		filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", dataStream, "manifest.yml"), "title: "+dataStream+"\n")
+	}
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "error", sampleEventFile), "{}")
+
+	missing, err := DataStreamsWithoutSampleEvent(packageRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"access", "status"}, missing)
+}
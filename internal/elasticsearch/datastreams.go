@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DataStream contains information about a data stream.
+type DataStream struct {
+	Name string
+
+	// CreationDate is the creation date of the oldest backing index of the data stream.
+	CreationDate time.Time
+}
+
+// DataStreams returns the data streams matching the given patterns, with the creation date
+// of their oldest backing index.
+func (c *Client) DataStreams(ctx context.Context, patterns ...string) ([]DataStream, error) {
+	resp, err := c.Indices.GetDataStream(
+		c.Indices.GetDataStream.WithContext(ctx),
+		c.Indices.GetDataStream.WithName(patterns...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data streams: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to get data streams: %s", resp.String())
+	}
+
+	var dataStreamsResponse struct {
+		DataStreams []struct {
+			Name    string `json:"name"`
+			Indices []struct {
+				IndexName string `json:"index_name"`
+			} `json:"indices"`
+		} `json:"data_streams"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&dataStreamsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data streams: %w", err)
+	}
+	if len(dataStreamsResponse.DataStreams) == 0 {
+		return nil, nil
+	}
+
+	creationDates, err := c.indicesCreationDate(ctx, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	dataStreams := make([]DataStream, 0, len(dataStreamsResponse.DataStreams))
+	for _, ds := range dataStreamsResponse.DataStreams {
+		dataStream := DataStream{Name: ds.Name}
+		for _, index := range ds.Indices {
+			creationDate, found := creationDates[index.IndexName]
+			if !found {
+				continue
+			}
+			if dataStream.CreationDate.IsZero() || creationDate.Before(dataStream.CreationDate) {
+				dataStream.CreationDate = creationDate
+			}
+		}
+		dataStreams = append(dataStreams, dataStream)
+	}
+	return dataStreams, nil
+}
+
+func (c *Client) indicesCreationDate(ctx context.Context, indices ...string) (map[string]time.Time, error) {
+	resp, err := c.Indices.GetSettings(
+		c.Indices.GetSettings.WithContext(ctx),
+		c.Indices.GetSettings.WithIndex(indices...),
+		c.Indices.GetSettings.WithName("index.creation_date"),
+		c.Indices.GetSettings.WithFlatSettings(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings of indices: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to get settings of indices: %s", resp.String())
+	}
+
+	var settings map[string]struct {
+		Settings struct {
+			CreationDate string `json:"index.creation_date"`
+		} `json:"settings"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode settings of indices: %w", err)
+	}
+
+	creationDates := make(map[string]time.Time, len(settings))
+	for index, s := range settings {
+		millis, err := strconv.ParseInt(s.Settings.CreationDate, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creation date of index %s (%q): %w", index, s.Settings.CreationDate, err)
+		}
+		creationDates[index] = time.UnixMilli(millis)
+	}
+	return creationDates, nil
+}
+
+// DeleteDataStream deletes a data stream, it doesn't fail if the data stream doesn't exist.
+func (c *Client) DeleteDataStream(ctx context.Context, dataStream string) error {
+	resp, err := c.Indices.DeleteDataStream([]string{dataStream},
+		c.Indices.DeleteDataStream.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("delete request failed for data stream %s: %w", dataStream, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		// Data stream doesn't exist, there was nothing to do.
+		return nil
+	}
+	if resp.IsError() {
+		return fmt.Errorf("delete request failed for data stream %s: %s", dataStream, resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIndexTemplateNotFound is returned when no index template matches an index.
+var ErrIndexTemplateNotFound = errors.New("index template not found")
+
+// MatchingIndexTemplate returns the name of the index template that applies to the given index.
+// As Elasticsearch does, if multiple templates match the index, the one with the highest
+// priority is selected.
+func (c *Client) MatchingIndexTemplate(ctx context.Context, index string) (string, error) {
+	resp, err := c.Indices.GetIndexTemplate(
+		c.Indices.GetIndexTemplate.WithContext(ctx),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get index templates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to get index templates: %s", resp.String())
+	}
+
+	var templatesResponse struct {
+		IndexTemplates []indexTemplateEntry `json:"index_templates"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&templatesResponse)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode index templates: %w", err)
+	}
+
+	name, found := selectIndexTemplate(templatesResponse.IndexTemplates, index)
+	if !found {
+		return "", fmt.Errorf("%w for index %q", ErrIndexTemplateNotFound, index)
+	}
+	return name, nil
+}
+
+type indexTemplateEntry struct {
+	Name          string `json:"name"`
+	IndexTemplate struct {
+		IndexPatterns []string `json:"index_patterns"`
+		Priority      int      `json:"priority"`
+	} `json:"index_template"`
+}
+
+// selectIndexTemplate selects the template with the highest priority of the ones whose patterns
+// match the index.
+func selectIndexTemplate(templates []indexTemplateEntry, index string) (string, bool) {
+	var selected *indexTemplateEntry
+	for i, template := range templates {
+		matches := false
+		for _, pattern := range template.IndexTemplate.IndexPatterns {
+			if matchIndexPattern(pattern, index) {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if selected == nil || template.IndexTemplate.Priority > selected.IndexTemplate.Priority {
+			selected = &templates[i]
+		}
+	}
+	if selected == nil {
+		return "", false
+	}
+	return selected.Name, true
+}
+
+// matchIndexPattern checks if the index matches a pattern of an index template. These patterns
+// only support the '*' wildcard.
+func matchIndexPattern(pattern, index string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == index
+	}
+	if !strings.HasPrefix(index, parts[0]) {
+		return false
+	}
+	index = index[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(index, part)
+		if i < 0 {
+			return false
+		}
+		index = index[i+len(part):]
+	}
+	return len(index) >= len(last) && strings.HasSuffix(index, last)
+}
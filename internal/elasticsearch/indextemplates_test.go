@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchIndexPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		index   string
+		matches bool
+	}{
+		{pattern: "logs-foo", index: "logs-foo", matches: true},
+		{pattern: "logs-foo", index: "logs-foobar", matches: false},
+		{pattern: "logs-foo*", index: "logs-foobar", matches: true},
+		{pattern: "logs-foo*", index: "logs-bar", matches: false},
+		{pattern: "*", index: "logs-bar", matches: true},
+		{pattern: "logs-*-default", index: "logs-foo-default", matches: true},
+		{pattern: "logs-*-default", index: "logs-foo-other", matches: false},
+		{pattern: "logs-*.latest*", index: "logs-ti.latest-2", matches: true},
+		{pattern: "logs-*bar", index: "logs-bar", matches: true},
+		{pattern: "logs-*-*", index: "logs-", matches: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.pattern+" "+c.index, func(t *testing.T) {
+			assert.Equal(t, c.matches, matchIndexPattern(c.pattern, c.index))
+		})
+	}
+}
+
+func TestSelectIndexTemplate(t *testing.T) {
+	template := func(name string, priority int, patterns ...string) indexTemplateEntry {
+		var entry indexTemplateEntry
+		entry.Name = name
+		entry.IndexTemplate.IndexPatterns = patterns
+		entry.IndexTemplate.Priority = priority
+		return entry
+	}
+	templates := []indexTemplateEntry{
+		template("logs", 100, "logs-*-*"),
+		template("logs-foo.latest", 250, "logs-foo.latest-*"),
+		template("metrics", 100, "metrics-*-*"),
+	}
+
+	name, found := selectIndexTemplate(templates, "logs-foo.latest-1")
+	if assert.True(t, found) {
+		assert.Equal(t, "logs-foo.latest", name)
+	}
+
+	name, found = selectIndexTemplate(templates, "logs-bar-default")
+	if assert.True(t, found) {
+		assert.Equal(t, "logs", name)
+	}
+
+	_, found = selectIndexTemplate(templates, "other")
+	assert.False(t, found)
+}
@@ -28,6 +28,7 @@ type IndexTemplate struct {
 			} `json:"package"`
 		} `json:"_meta"`
 		ComposedOf []string `json:"composed_of"`
+		Priority   int      `json:"priority"`
 		Template   struct {
 			Settings TemplateSettings `json:"settings"`
 		} `json:"template"`
@@ -57,6 +58,11 @@ func (t IndexTemplate) JSON() []byte {
 	return []byte(t.raw)
 }
 
+// Priority returns the priority of the index template. Templates without priority have priority 0.
+func (t IndexTemplate) Priority() int {
+	return t.IndexTemplate.Priority
+}
+
 // TemplateSettings returns the template settings of this template.
 func (t IndexTemplate) TemplateSettings() TemplateSettings {
 	return t.IndexTemplate.Template.Settings
@@ -92,8 +98,58 @@ func GetIndexTemplatesForPackage(ctx context.Context, api *elasticsearch.API, pa
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	templates, err := parseIndexTemplates(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexTemplates []IndexTemplate
+	for _, indexTemplate := range templates {
+		meta := indexTemplate.IndexTemplate.Meta
+		if meta.Package.Name != packageName || !managedByFleet(meta.ManagedBy) {
+			// This is not the droid you are looking for.
+			continue
+		}
+
+		indexTemplates = append(indexTemplates, indexTemplate)
+	}
+
+	return indexTemplates, nil
+}
+
+// GetIndexTemplate gets an index template by its name.
+func GetIndexTemplate(ctx context.Context, api *elasticsearch.API, name string) (*IndexTemplate, error) {
+	resp, err := api.Indices.GetIndexTemplate(
+		api.Indices.GetIndexTemplate.WithContext(ctx),
+		api.Indices.GetIndexTemplate.WithName(name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index template %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to get index template %s: %s", name, resp.String())
+	}
+
+	d, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	templates, err := parseIndexTemplates(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) != 1 {
+		return nil, fmt.Errorf("expected one index template with name %s, found %d", name, len(templates))
+	}
+	return &templates[0], nil
+}
+
+func parseIndexTemplates(d []byte) ([]IndexTemplate, error) {
 	var templateResponse getIndexTemplateResponse
-	err = json.Unmarshal(d, &templateResponse)
+	err := json.Unmarshal(d, &templateResponse)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -106,16 +162,8 @@ func GetIndexTemplatesForPackage(ctx context.Context, api *elasticsearch.API, pa
 			return nil, fmt.Errorf("failed to parse index template: %w", err)
 		}
 		indexTemplate.raw = indexTemplateRaw
-
-		meta := indexTemplate.IndexTemplate.Meta
-		if meta.Package.Name != packageName || !managedByFleet(meta.ManagedBy) {
-			// This is not the droid you are looking for.
-			continue
-		}
-
 		indexTemplates = append(indexTemplates, indexTemplate)
 	}
-
 	return indexTemplates, nil
 }
 
@@ -0,0 +1,140 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+// FieldsCoverage contains the fields defined in a package and the ones of them found in a
+// set of documents.
+type FieldsCoverage struct {
+	Defined []string
+	Covered []string
+}
+
+// Percentage returns the percentage of defined fields that are covered. Coverage is complete
+// if there are no defined fields.
+func (c FieldsCoverage) Percentage() float64 {
+	if len(c.Defined) == 0 {
+		return 100
+	}
+	return float64(len(c.Covered)) * 100 / float64(len(c.Defined))
+}
+
+// Uncovered returns the defined fields that are not covered.
+func (c FieldsCoverage) Uncovered() []string {
+	var uncovered []string
+	for _, name := range c.Defined {
+		if !slices.Contains(c.Covered, name) {
+			uncovered = append(uncovered, name)
+		}
+	}
+	return uncovered
+}
+
+// FieldsCoverage checks which of the fields defined in the package are present in the given
+// documents. Imported schemas are not considered, only the fields defined in the package. Groups
+// and multi-fields are not counted, as they don't appear in documents.
+func (v *Validator) FieldsCoverage(docs []common.MapStr) FieldsCoverage {
+	var keys []string
+	for _, doc := range docs {
+		keys = documentKeys("", doc, keys)
+	}
+
+	var coverage FieldsCoverage
+	for _, def := range flattenLeafFields("", v.packageSchema) {
+		coverage.Defined = append(coverage.Defined, def.Name)
+		for _, key := range keys {
+			if fieldCoversKey(def, key) {
+				coverage.Covered = append(coverage.Covered, def.Name)
+				break
+			}
+		}
+	}
+	slices.Sort(coverage.Defined)
+	slices.Sort(coverage.Covered)
+	return coverage
+}
+
+// fieldCoversKey checks if a document key is covered by a field definition. Keys of objects and
+// flattened fields are covered by the definition of the field containing them.
+func fieldCoversKey(def FieldDefinition, key string) bool {
+	if compareKeys(def.Name, def, key) {
+		return true
+	}
+	switch def.Type {
+	case "object", "flattened", "nested":
+		for i := strings.IndexByte(key, '.'); i >= 0; i = nextDot(key, i) {
+			if compareKeys(def.Name, def, key[:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextDot returns the position of the next dot in key after position i, or -1 if there are no more dots.
+func nextDot(key string, i int) int {
+	next := strings.IndexByte(key[i+1:], '.')
+	if next < 0 {
+		return -1
+	}
+	return i + 1 + next
+}
+
+// flattenLeafFields returns the definitions of the fields that are not groups, with their full names.
+func flattenLeafFields(prefix string, fields []FieldDefinition) []FieldDefinition {
+	var leaves []FieldDefinition
+	for _, field := range fields {
+		name := field.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if field.Type == "group" || (field.Type == "" && len(field.Fields) > 0) {
+			leaves = append(leaves, flattenLeafFields(name, field.Fields)...)
+			continue
+		}
+		field.Name = name
+		leaves = append(leaves, field)
+	}
+	return leaves
+}
+
+// documentKeys appends to keys the dotted names of the values in the document. Unlike
+// common.MapStr.Flatten, objects in arrays are also flattened.
+func documentKeys(prefix string, value any, keys []string) []string {
+	switch value := value.(type) {
+	case common.MapStr:
+		return documentKeys(prefix, map[string]any(value), keys)
+	case map[string]any:
+		for key, nested := range value {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			keys = documentKeys(key, nested, keys)
+		}
+		return keys
+	case []any:
+		hasObjects := false
+		for _, elem := range value {
+			switch elem.(type) {
+			case map[string]any, common.MapStr:
+				hasObjects = true
+				keys = documentKeys(prefix, elem, keys)
+			}
+		}
+		if hasObjects {
+			return keys
+		}
+	}
+	if prefix == "" || slices.Contains(keys, prefix) {
+		return keys
+	}
+	return append(keys, prefix)
+}
@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+func TestFieldsCoverage(t *testing.T) {
+	v := Validator{
+		packageSchema: []FieldDefinition{
+			{
+				Name: "source",
+				Type: "group",
+				Fields: []FieldDefinition{
+					{Name: "ip", Type: "ip"},
+					{Name: "port", Type: "long"},
+					{
+						Name: "domain", Type: "keyword",
+						MultiFields: []FieldDefinition{{Name: "text", Type: "match_only_text"}},
+					},
+				},
+			},
+			{Name: "labels.*", Type: "keyword"},
+			{Name: "attributes", Type: "flattened"},
+			{Name: "related.hosts", Type: "keyword"},
+			{Name: "threat.enrichments.indicator.ip", Type: "ip"},
+		},
+	}
+
+	docs := []common.MapStr{
+		{
+			"source": common.MapStr{
+				"ip": "10.0.0.1",
+			},
+			"labels": common.MapStr{
+				"env": "test",
+			},
+		},
+		{
+			"source.domain": "example.com",
+			"attributes": common.MapStr{
+				"some": common.MapStr{"key": "value"},
+			},
+			"threat": map[string]any{
+				"enrichments": []any{
+					map[string]any{"indicator": map[string]any{"ip": "10.0.0.2"}},
+				},
+			},
+		},
+	}
+
+	coverage := v.FieldsCoverage(docs)
+	assert.Equal(t, []string{
+		"attributes",
+		"labels.*",
+		"related.hosts",
+		"source.domain",
+		"source.ip",
+		"source.port",
+		"threat.enrichments.indicator.ip",
+	}, coverage.Defined)
+	assert.Equal(t, []string{
+		"attributes",
+		"labels.*",
+		"source.domain",
+		"source.ip",
+		"threat.enrichments.indicator.ip",
+	}, coverage.Covered)
+	assert.Equal(t, []string{"related.hosts", "source.port"}, coverage.Uncovered())
+	assert.InDelta(t, 71.4, coverage.Percentage(), 0.1)
+}
+
+func TestFieldsCoverageNoFields(t *testing.T) {
+	var v Validator
+	coverage := v.FieldsCoverage([]common.MapStr{{"message": "test"}})
+	assert.Empty(t, coverage.Defined)
+	assert.Equal(t, float64(100), coverage.Percentage())
+}
@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// fieldReferenceKeys are the keys used by saved objects to reference fields.
+var fieldReferenceKeys = []string{
+	"field",
+	"sourceField",
+}
+
+// lensRecordsField is the pseudo-field used by Lens to count documents.
+const lensRecordsField = "___records___"
+
+// SavedObjectFieldReferences contains the fields referenced by a Kibana saved object.
+type SavedObjectFieldReferences struct {
+	Path   string
+	Type   string
+	Title  string
+	Fields []string
+}
+
+// ReadSavedObjectsFieldReferences reads the saved objects found in the kibana directory of the package
+// and returns the fields they reference. Runtime fields defined in the saved objects are not included.
+func ReadSavedObjectsFieldReferences(packageRoot string) ([]SavedObjectFieldReferences, error) {
+	paths, err := filepath.Glob(filepath.Join(packageRoot, "kibana", "*", "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for saved objects: %w", err)
+	}
+
+	var result []SavedObjectFieldReferences
+	for _, path := range paths {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read saved object (path: %s): %w", path, err)
+		}
+
+		var object map[string]any
+		err = json.Unmarshal(body, &object)
+		if err != nil {
+			return nil, fmt.Errorf("can't unmarshal saved object (path: %s): %w", path, err)
+		}
+
+		references := SavedObjectFieldReferences{Path: path}
+		references.Type, _ = object["type"].(string)
+		if attributes, ok := object["attributes"].(map[string]any); ok {
+			references.Title, _ = attributes["title"].(string)
+		}
+
+		referenced := make(map[string]struct{})
+		runtimeFields := make(map[string]struct{})
+		collectFieldReferences(object, referenced, runtimeFields)
+		for field := range referenced {
+			if _, isRuntime := runtimeFields[field]; isRuntime {
+				continue
+			}
+			references.Fields = append(references.Fields, field)
+		}
+		sort.Strings(references.Fields)
+
+		result = append(result, references)
+	}
+	return result, nil
+}
+
+// collectFieldReferences walks the saved object looking for references to fields. Attributes
+// encoded as JSON strings (e.g. panelsJSON or visState) are decoded and walked too.
+func collectFieldReferences(value any, referenced, runtimeFields map[string]struct{}) {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, v := range value {
+			if key == "runtimeFieldMap" {
+				if runtimeFieldMap, ok := v.(map[string]any); ok {
+					for name := range runtimeFieldMap {
+						runtimeFields[name] = struct{}{}
+					}
+				}
+			}
+			if name, ok := v.(string); ok && slices.Contains(fieldReferenceKeys, key) && isFieldReference(name) {
+				referenced[name] = struct{}{}
+				continue
+			}
+			collectFieldReferences(v, referenced, runtimeFields)
+		}
+	case []any:
+		for _, v := range value {
+			collectFieldReferences(v, referenced, runtimeFields)
+		}
+	case string:
+		trimmed := strings.TrimSpace(value)
+		if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+			return
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(trimmed), &decoded); err != nil {
+			return
+		}
+		collectFieldReferences(decoded, referenced, runtimeFields)
+	}
+}
+
+func isFieldReference(name string) bool {
+	// Metadata fields (e.g. _id or _index) are not defined in packages.
+	return name != "" && name != lensRecordsField && !strings.HasPrefix(name, "_")
+}
+
+// FindMissingFieldReferences reports the fields referenced by the saved objects that are not defined in the schema.
+func FindMissingFieldReferences(objects []SavedObjectFieldReferences, schema []FieldDefinition) []packages.Problem {
+	var missing []packages.Problem
+	for _, object := range objects {
+		for _, field := range object.Fields {
+			if FindElementDefinition(field, schema) != nil {
+				continue
+			}
+			missing = append(missing, packages.Problem{
+				Path:    object.Path,
+				Message: fmt.Sprintf("%s %q references undefined field %q", object.Type, object.Title, field),
+			})
+		}
+	}
+	return missing
+}
@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestFindMissingFieldReferences(t *testing.T) {
+	packageRoot := t.TempDir()
+	// Decoded visualization, as exported by elastic-package.
+	filestest.WriteFile(t, packageRoot, filepath.Join("kibana", "visualization", "browsers.json"), `{
+  "type": "visualization",
+  "attributes": {
+    "title": "Browsers",
+    "visState": {
+      "aggs": [
+        {"params": {"field": "source.address"}},
+        {"params": {"field": "user_agent.name"}}
+      ]
+    }
+  }
+}`)
+	// Dashboard with encoded panels, including a Lens panel with a runtime field.
+	filestest.WriteFile(t, packageRoot, filepath.Join("kibana", "dashboard", "overview.json"), `{
+  "type": "dashboard",
+  "attributes": {
+    "title": "Overview",
+    "panelsJSON": "[{\"embeddableConfig\":{\"attributes\":{\"state\":{\"adHocDataViews\":{\"x\":{\"runtimeFieldMap\":{\"runtime.field\":{}}}},\"datasourceStates\":{\"formBased\":{\"layers\":{\"l\":{\"columns\":{\"a\":{\"sourceField\":\"___records___\"},\"b\":{\"sourceField\":\"http.response.status_code\"},\"c\":{\"sourceField\":\"runtime.field\"},\"d\":{\"sourceField\":\"_id\"}}}}}}}}}}]"
+  }
+}`)
+
+	objects, err := ReadSavedObjectsFieldReferences(packageRoot)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "Overview", objects[0].Title)
+	assert.Equal(t, []string{"http.response.status_code"}, objects[0].Fields)
+	assert.Equal(t, "Browsers", objects[1].Title)
+	assert.Equal(t, []string{"source.address", "user_agent.name"}, objects[1].Fields)
+
+	schema := []FieldDefinition{
+		{Name: "source.address", Type: "keyword"},
+		{Name: "user_agent", Type: "group", Fields: []FieldDefinition{
+			{Name: "name", Type: "keyword"},
+		}},
+	}
+	missing := FindMissingFieldReferences(objects, schema)
+	assert.Equal(t, []packages.Problem{
+		{
+			Path:    objects[0].Path,
+			Message: `dashboard "Overview" references undefined field "http.response.status_code"`,
+		},
+	}, missing)
+}
@@ -0,0 +1,198 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinDateFormats contains Go layouts equivalent to the built-in date formats of Elasticsearch
+// that can be matched exactly. Other built-in formats, like strict_date_optional_time, accept many
+// variations that Go layouts cannot express, so they are not validated to avoid false positives.
+// Epoch formats are handled separately.
+var builtinDateFormats = map[string][]string{
+	"strict_date":                    {"2006-01-02"},
+	"basic_date":                     {"20060102"},
+	"strict_date_hour_minute_second": {"2006-01-02T15:04:05"},
+}
+
+// javaDateTokens maps tokens of Java date patterns, as used in Elasticsearch custom date formats,
+// to their equivalent in Go layouts. Longer tokens must go first.
+var javaDateTokens = []struct {
+	java   string
+	layout string
+}{
+	{"yyyy", "2006"},
+	{"uuuu", "2006"},
+	{"yy", "06"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"dd", "02"},
+	{"d", "2"},
+	{"EEEE", "Monday"},
+	{"EEE", "Mon"},
+	{"HH", "15"},
+	{"hh", "03"},
+	{"h", "3"},
+	{"mm", "04"},
+	{"ss", "05"},
+	{"SSSSSSSSS", "000000000"},
+	{"SSSSSS", "000000"},
+	{"SSS", "000"},
+	{"a", "PM"},
+	{"XXX", "Z07:00"},
+	{"XX", "Z0700"},
+	{"xxx", "-07:00"},
+	{"Z", "-0700"},
+}
+
+// ensureDateFormatMatches validates that a date value can be parsed with any of the formats
+// declared in the field definition. Multiple formats can be separated by "||", as in Elasticsearch.
+// If any of the formats is not supported by this validation, the value is not validated, to avoid
+// false positives.
+func ensureDateFormatMatches(key string, value any, dateFormat string) error {
+	if dateFormat == "" {
+		return nil
+	}
+
+	for _, format := range strings.Split(dateFormat, "||") {
+		format = strings.TrimSpace(format)
+		matches, supported := dateMatchesFormat(value, format)
+		if !supported || matches {
+			return nil
+		}
+	}
+	return fmt.Errorf("field %q's value %v does not match the expected date format %q", key, value, dateFormat)
+}
+
+// dateMatchesFormat checks if a value can be parsed with the given format. It also returns false
+// as second value if the format is not supported.
+func dateMatchesFormat(value any, format string) (matches bool, supported bool) {
+	switch format {
+	case "epoch_millis", "epoch_second":
+		switch value := value.(type) {
+		case float64:
+			return true, true
+		case string:
+			_, err := strconv.ParseFloat(value, 64)
+			return err == nil, true
+		}
+		return false, true
+	}
+
+	str, isString := value.(string)
+
+	layouts, found := builtinDateFormats[format]
+	if !found {
+		layout, ok := javaDateLayout(format)
+		if !ok {
+			return false, false
+		}
+		layouts = []string{layout}
+	}
+	if !isString {
+		return false, true
+	}
+
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, str); err == nil {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// javaDateLayout converts a Java date pattern to a Go layout. It returns false if the pattern
+// contains tokens or constructs that cannot be matched exactly with a Go layout, like optional
+// sections, time zone names, or fractions of seconds not preceded by a separator.
+func javaDateLayout(pattern string) (string, bool) {
+	var layout strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			// Quoted literal, two single quotes are a literal single quote, inside or outside
+			// quoted literals.
+			literal, n, ok := javaQuotedLiteral(pattern[i:])
+			if !ok {
+				return "", false
+			}
+			if hasLayoutTokens(literal) {
+				// Could be interpreted as parts of the layout.
+				return "", false
+			}
+			layout.WriteString(literal)
+			i += n
+			continue
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			found := false
+			for _, token := range javaDateTokens {
+				if strings.HasPrefix(pattern[i:], token.java) {
+					if token.java[0] == 'S' && (i == 0 || (pattern[i-1] != '.' && pattern[i-1] != ',')) {
+						// Go layouts only support fractions of seconds after a separator.
+						return "", false
+					}
+					layout.WriteString(token.layout)
+					i += len(token.java)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return "", false
+			}
+			continue
+		case c >= '0' && c <= '9':
+			// Digits would be interpreted as parts of the layout.
+			return "", false
+		case strings.ContainsRune("[]{}#", rune(c)):
+			// Optional sections and reserved characters.
+			return "", false
+		}
+		layout.WriteByte(c)
+		i++
+	}
+	return layout.String(), true
+}
+
+// javaQuotedLiteral returns the literal text of the quoted literal at the beginning of the pattern,
+// and the length of the quoted literal in the pattern. It returns false if the literal is not closed.
+func javaQuotedLiteral(pattern string) (string, int, bool) {
+	if strings.HasPrefix(pattern, "''") {
+		return "'", 2, true
+	}
+	var literal strings.Builder
+	for i := 1; i < len(pattern); i++ {
+		if pattern[i] != '\'' {
+			literal.WriteByte(pattern[i])
+			continue
+		}
+		if i+1 < len(pattern) && pattern[i+1] == '\'' {
+			literal.WriteByte('\'')
+			i++
+			continue
+		}
+		return literal.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+// goLayoutWords are the tokens of Go layouts that don't contain digits.
+var goLayoutWords = []string{"Jan", "Mon", "MST", "PM", "pm"}
+
+// hasLayoutTokens returns true if a literal contains digits or words that would be interpreted as
+// tokens of Go layouts.
+func hasLayoutTokens(literal string) bool {
+	if strings.ContainsFunc(literal, func(r rune) bool { return r >= '0' && r <= '9' }) {
+		return true
+	}
+	return slices.ContainsFunc(goLayoutWords, func(word string) bool { return strings.Contains(literal, word) })
+}
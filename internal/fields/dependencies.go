@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/packages/buildmanifest"
+)
+
+// FindDependencyProblems resolves the dependencies declared in the build manifest of the package,
+// and the external fields that use them. Dependencies are read from local files or from the cache
+// when available, so only dependencies never used before require network access.
+func FindDependencyProblems(packageRoot string) ([]packages.Problem, error) {
+	var problems []packages.Problem
+
+	manifestPath := filepath.Join(packageRoot, "_dev", "build", "build.yml")
+	buildManifest, found, err := buildmanifest.ReadBuildManifest(packageRoot)
+	if err != nil {
+		return []packages.Problem{{Path: manifestPath, Message: err.Error()}}, nil
+	}
+
+	var dm *DependencyManager
+	if found {
+		dm, err = CreateFieldDependencyManager(buildManifest.Dependencies)
+		if err != nil {
+			problems = append(problems, packages.Problem{
+				Path:    manifestPath,
+				Message: fmt.Sprintf("can't resolve ECS dependency (reference: %s): %v", buildManifest.Dependencies.ECS.Reference, err),
+			})
+			// External fields can't be checked without the schemas.
+			return problems, nil
+		}
+	}
+
+	var fieldsFiles []string
+	for _, pattern := range []string{
+		filepath.Join(packageRoot, "fields", "*.yml"),
+		filepath.Join(packageRoot, "data_stream", "*", "fields", "*.yml"),
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("can't find fields files: %w", err)
+		}
+		fieldsFiles = append(fieldsFiles, matches...)
+	}
+
+	for _, path := range fieldsFiles {
+		d, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading fields file failed: %w", err)
+		}
+		var defs []common.MapStr
+		err = yaml.Unmarshal(d, &defs)
+		if err != nil {
+			problems = append(problems, packages.Problem{Path: path, Message: fmt.Sprintf("can't parse fields file: %v", err)})
+			continue
+		}
+
+		externalFields, err := findExternalFields("", defs)
+		if err != nil {
+			problems = append(problems, packages.Problem{Path: path, Message: err.Error()})
+			continue
+		}
+		for _, field := range externalFields {
+			_, err := dm.importField(field.external, field.name)
+			if err != nil {
+				problems = append(problems, packages.Problem{
+					Path:    path,
+					Message: fmt.Sprintf("external field %q (external: %s) can't be resolved: %v", field.name, field.external, err),
+				})
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+type externalField struct {
+	name     string
+	external string
+}
+
+func findExternalFields(root string, defs []common.MapStr) ([]externalField, error) {
+	var result []externalField
+	for _, def := range defs {
+		if _, ok := def["name"].(string); !ok {
+			return nil, fmt.Errorf("field definition without name found under %q", root)
+		}
+		fieldPath := buildFieldPath(root, def)
+
+		if external, ok := def["external"].(string); ok {
+			result = append(result, externalField{name: fieldPath, external: external})
+			continue
+		}
+
+		fields, _ := def.GetValue("fields")
+		if fields == nil {
+			continue
+		}
+		fieldsMs, err := common.ToMapStrSlice(fields)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert fields of %q: %w", fieldPath, err)
+		}
+		children, err := findExternalFields(fieldPath, fieldsMs)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, children...)
+	}
+	return result, nil
+}
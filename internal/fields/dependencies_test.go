@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestFindDependencyProblems(t *testing.T) {
+	ecsNestedPath, err := filepath.Abs("./testdata/ecs_nested_v8.10.0.yml")
+	require.NoError(t, err)
+
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, filepath.Join("fields", "ecs.yml"), `
+- name: host.name
+  external: ecs
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "logs", "fields", "fields.yml"), `
+- name: source
+  type: group
+  fields:
+    - name: ip
+      external: ecs
+    - name: unknown
+      external: ecs
+- name: custom
+  external: other
+`)
+
+	t.Run("no build manifest", func(t *testing.T) {
+		problems, err := FindDependencyProblems(packageRoot)
+		require.NoError(t, err)
+		assert.Len(t, problems, 4)
+	})
+
+	t.Run("unresolved ECS reference", func(t *testing.T) {
+		filestest.WriteFile(t, packageRoot, filepath.Join("_dev", "build", "build.yml"), `
+dependencies:
+  ecs:
+    reference: "file://`+filepath.Join(packageRoot, "missing.yml")+`"
+`)
+		problems, err := FindDependencyProblems(packageRoot)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Equal(t, filepath.Join(packageRoot, "_dev", "build", "build.yml"), problems[0].Path)
+	})
+
+	t.Run("unresolved external fields", func(t *testing.T) {
+		filestest.WriteFile(t, packageRoot, filepath.Join("_dev", "build", "build.yml"), `
+dependencies:
+  ecs:
+    reference: "file://`+ecsNestedPath+`"
+`)
+		problems, err := FindDependencyProblems(packageRoot)
+		require.NoError(t, err)
+
+		fieldsPath := filepath.Join(packageRoot, "data_stream", "logs", "fields", "fields.yml")
+		expected := []packages.Problem{
+			{
+				Path:    fieldsPath,
+				Message: `external field "source.unknown" (external: ecs) can't be resolved: field definition not found in schema (name: source.unknown)`,
+			},
+			{
+				Path:    fieldsPath,
+				Message: `external field "custom" (external: other) can't be resolved: schema "other" is not defined as package depedency`,
+			},
+		}
+		assert.Equal(t, expected, problems)
+	})
+}
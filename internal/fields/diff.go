@@ -0,0 +1,160 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldChange describes a field that is different between two versions of a package.
+type FieldChange struct {
+	Name    string
+	OldType string
+	NewType string
+}
+
+// FieldsDiff contains the differences in the fields of a data stream between two versions
+// of a package. Fields defined at the package level are reported with an empty data stream.
+type FieldsDiff struct {
+	DataStream string
+	Added      []FieldChange
+	Removed    []FieldChange
+	Retyped    []FieldChange
+}
+
+// Empty returns true if there are no differences.
+func (d FieldsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retyped) == 0
+}
+
+// LoadFieldsFromZipPackage reads the field definitions of a built package, grouped by data stream.
+// Fields defined at the package level are grouped with an empty data stream name.
+func LoadFieldsFromZipPackage(zipPath string) (map[string][]FieldDefinition, error) {
+	zipReader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("can't open zip package (path: %s): %w", zipPath, err)
+	}
+	defer zipReader.Close()
+
+	// elastic-package build command creates a zip that contains all the package files
+	// under a folder named "package-version".
+	patterns := map[string]string{
+		"":            "*/fields/*.yml",
+		"data_stream": "*/data_stream/*/fields/*.yml",
+	}
+
+	result := make(map[string][]FieldDefinition)
+	for kind, pattern := range patterns {
+		matched, err := fs.Glob(zipReader, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("can't look for fields files in zip package (path: %s): %w", zipPath, err)
+		}
+		for _, file := range matched {
+			dataStream := ""
+			if kind == "data_stream" {
+				// Path is <package-version>/data_stream/<data stream>/fields/<file>.yml
+				dataStream = strings.Split(file, "/")[2]
+			}
+
+			body, err := fs.ReadFile(zipReader, file)
+			if err != nil {
+				return nil, fmt.Errorf("can't read fields file %s from zip package (path: %s): %w", file, zipPath, err)
+			}
+
+			var fields FieldDefinitions
+			err = yaml.Unmarshal(body, &fields)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshalling fields file %s failed: %w", path.Base(file), err)
+			}
+			result[dataStream] = append(result[dataStream], fields...)
+		}
+	}
+	return result, nil
+}
+
+// DiffPackageFields compares the fields of two versions of a package, grouped by data stream,
+// and reports added, removed and retyped fields. Only data streams with differences are returned.
+func DiffPackageFields(oldFields, newFields map[string][]FieldDefinition) []FieldsDiff {
+	var dataStreams []string
+	for dataStream := range oldFields {
+		dataStreams = append(dataStreams, dataStream)
+	}
+	for dataStream := range newFields {
+		if !slices.Contains(dataStreams, dataStream) {
+			dataStreams = append(dataStreams, dataStream)
+		}
+	}
+	sort.Strings(dataStreams)
+
+	var diffs []FieldsDiff
+	for _, dataStream := range dataStreams {
+		diff := diffFields(flattenFieldTypes(oldFields[dataStream]), flattenFieldTypes(newFields[dataStream]))
+		if diff.Empty() {
+			continue
+		}
+		diff.DataStream = dataStream
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func diffFields(oldTypes, newTypes map[string]string) FieldsDiff {
+	var diff FieldsDiff
+	for _, name := range sortedKeys(oldTypes) {
+		oldType := oldTypes[name]
+		newType, found := newTypes[name]
+		switch {
+		case !found:
+			diff.Removed = append(diff.Removed, FieldChange{Name: name, OldType: oldType})
+		case oldType != newType:
+			diff.Retyped = append(diff.Retyped, FieldChange{Name: name, OldType: oldType, NewType: newType})
+		}
+	}
+	for _, name := range sortedKeys(newTypes) {
+		if _, found := oldTypes[name]; !found {
+			diff.Added = append(diff.Added, FieldChange{Name: name, NewType: newTypes[name]})
+		}
+	}
+	return diff
+}
+
+// flattenFieldTypes returns the types of the fields indexed by their full names.
+// Groups are not included, only their children.
+func flattenFieldTypes(fields []FieldDefinition) map[string]string {
+	types := make(map[string]string)
+	var flatten func(prefix string, fields []FieldDefinition)
+	flatten = func(prefix string, fields []FieldDefinition) {
+		for _, field := range fields {
+			name := field.Name
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			if field.Type != "group" && !(field.Type == "" && len(field.Fields) > 0) {
+				types[name] = field.Type
+			}
+			flatten(name, field.Fields)
+			flatten(name, field.MultiFields)
+		}
+	}
+	flatten("", fields)
+	return types
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
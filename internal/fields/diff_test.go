@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPackageFields(t *testing.T) {
+	oldPackage := createZipPackage(t, "old.zip", map[string]string{
+		"test-1.0.0/fields/base-fields.yml": `
+- name: data_stream.type
+  type: constant_keyword
+`,
+		"test-1.0.0/data_stream/logs/fields/fields.yml": `
+- name: test
+  type: group
+  fields:
+    - name: message
+      type: text
+    - name: bytes
+      type: long
+    - name: removed
+      type: keyword
+`,
+		"test-1.0.0/data_stream/metrics/fields/fields.yml": `
+- name: test.value
+  type: double
+`,
+	})
+	newPackage := createZipPackage(t, "new.zip", map[string]string{
+		"test-1.1.0/fields/base-fields.yml": `
+- name: data_stream.type
+  type: constant_keyword
+`,
+		"test-1.1.0/data_stream/logs/fields/fields.yml": `
+- name: test
+  type: group
+  fields:
+    - name: message
+      type: match_only_text
+      multi_fields:
+        - name: keyword
+          type: keyword
+    - name: bytes
+      type: long
+`,
+		"test-1.1.0/data_stream/metrics/fields/fields.yml": `
+- name: test.value
+  type: double
+`,
+	})
+
+	oldFields, err := LoadFieldsFromZipPackage(oldPackage)
+	require.NoError(t, err)
+	newFields, err := LoadFieldsFromZipPackage(newPackage)
+	require.NoError(t, err)
+
+	diffs := DiffPackageFields(oldFields, newFields)
+	expected := []FieldsDiff{
+		{
+			DataStream: "logs",
+			Added:      []FieldChange{{Name: "test.message.keyword", NewType: "keyword"}},
+			Removed:    []FieldChange{{Name: "test.removed", OldType: "keyword"}},
+			Retyped:    []FieldChange{{Name: "test.message", OldType: "text", NewType: "match_only_text"}},
+		},
+	}
+	assert.Equal(t, expected, diffs)
+}
+
+func TestDiffPackageFields_DataStreams(t *testing.T) {
+	oldFields := map[string][]FieldDefinition{
+		"":    {{Name: "@timestamp", Type: "date"}},
+		"old": {{Name: "foo", Type: "keyword"}},
+	}
+	newFields := map[string][]FieldDefinition{
+		"":    {{Name: "@timestamp", Type: "date"}},
+		"new": {{Name: "foo", Type: "keyword"}},
+	}
+
+	diffs := DiffPackageFields(oldFields, newFields)
+	expected := []FieldsDiff{
+		{DataStream: "new", Added: []FieldChange{{Name: "foo", NewType: "keyword"}}},
+		{DataStream: "old", Removed: []FieldChange{{Name: "foo", OldType: "keyword"}}},
+	}
+	assert.Equal(t, expected, diffs)
+}
+
+func createZipPackage(t *testing.T, name string, files map[string]string) string {
+	zipPath := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(zipPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for path, content := range files {
+		fw, err := w.Create(path)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return zipPath
+}
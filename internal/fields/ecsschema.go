@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ECSSchema is the complete ECS schema used as reference for the fields of a package. It is loaded
+// lazily and only once, so it is only read, or downloaded if it is not cached, when a check
+// compares the fields of the package with ECS.
+type ECSSchema struct {
+	load func() ([]FieldDefinition, error)
+}
+
+// NewECSSchema creates the ECS schema for the package in the given root path, with the ECS
+// version defined in its build manifest.
+func NewECSSchema(packageRoot string, specVersion string) (*ECSSchema, error) {
+	sv, err := semver.NewVersion(specVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", specVersion, err)
+	}
+	return &ECSSchema{
+		load: sync.OnceValues(func() ([]FieldDefinition, error) {
+			_, schema, err := initDependencyManagement(packageRoot, *sv, true, "")
+			return schema, err
+		}),
+	}, nil
+}
+
+// Fields returns the definitions of the fields of the schema, including the multifields added
+// by ecs@mappings.
+func (s *ECSSchema) Fields() ([]FieldDefinition, error) {
+	return s.load()
+}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// explainUndefinedField describes how an undefined field could be defined, using the nearest
+// definition found in the schema and the type inferred from its value.
+func explainUndefinedField(key string, val any, schema []FieldDefinition) string {
+	var explanation strings.Builder
+
+	parentKey, parent := findAncestorElementDefinition(key, schema, func(string, *FieldDefinition) bool { return true })
+	if parent != nil {
+		fmt.Fprintf(&explanation, "nearest defined parent: %q (type: %s)", parentKey, definitionType(*parent))
+	} else {
+		explanation.WriteString("nearest defined parent: none")
+	}
+
+	inferredType := inferFieldType(val)
+	fmt.Fprintf(&explanation, ", inferred type: %s", inferredType)
+
+	var suggestion string
+	switch {
+	case isArrayOfObjects(val):
+		suggestion = fmt.Sprintf("add a definition for %q with type group or nested", key)
+	case couldBeMultifield(key, schema):
+		name := key[strings.LastIndex(key, ".")+1:]
+		suggestion = fmt.Sprintf("add %q to the multi_fields of %q, with type %s", name, parentKey, inferredType)
+	case parent != nil && !isObjectType(parent.Type):
+		suggestion = fmt.Sprintf("%q can't have subfields, define it as an object or rename %q", parentKey, key)
+	default:
+		suggestion = fmt.Sprintf("add a definition for %q with type %s to the fields files", key, inferredType)
+	}
+	fmt.Fprintf(&explanation, ", suggestion: %s", suggestion)
+
+	return explanation.String()
+}
+
+func definitionType(definition FieldDefinition) string {
+	if definition.Type == "" {
+		return "group"
+	}
+	return definition.Type
+}
+
+func isObjectType(fieldType string) bool {
+	switch fieldType {
+	case "", "group", "nested", "object":
+		return true
+	}
+	return false
+}
+
+// inferFieldType returns the field type that would most likely fit the value.
+func inferFieldType(val any) string {
+	switch val := val.(type) {
+	case []any:
+		if len(val) == 0 {
+			return "keyword"
+		}
+		return inferFieldType(val[0])
+	case map[string]any:
+		return "group"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "long"
+		}
+		return "double"
+	case float64:
+		if val == float64(int64(val)) {
+			return "long"
+		}
+		return "double"
+	case int, int64:
+		return "long"
+	default:
+		return "keyword"
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainUndefinedField(t *testing.T) {
+	schema := []FieldDefinition{
+		{
+			Name: "process",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{Name: "name", Type: "keyword"},
+			},
+		},
+		{Name: "message", Type: "match_only_text"},
+	}
+
+	cases := []struct {
+		key      string
+		val      any
+		expected string
+	}{
+		{
+			key:      "process.pid",
+			val:      json.Number("42"),
+			expected: `nearest defined parent: "process" (type: group), inferred type: long, suggestion: add a definition for "process.pid" with type long to the fields files`,
+		},
+		{
+			key:      "process.name.text",
+			val:      "nginx",
+			expected: `nearest defined parent: "process.name" (type: keyword), inferred type: keyword, suggestion: add "text" to the multi_fields of "process.name", with type keyword`,
+		},
+		{
+			key:      "process.args",
+			val:      []any{map[string]any{"value": "-c"}},
+			expected: `nearest defined parent: "process" (type: group), inferred type: group, suggestion: add a definition for "process.args" with type group or nested`,
+		},
+		{
+			key:      "user.enabled",
+			val:      true,
+			expected: `nearest defined parent: none, inferred type: boolean, suggestion: add a definition for "user.enabled" with type boolean to the fields files`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.key, func(t *testing.T) {
+			assert.Equal(t, c.expected, explainUndefinedField(c.key, c.val, schema))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const timeSeriesIndexMode = "time_series"
+
+// dimensionFieldTypes are the field types that can be used as dimensions in time series data streams,
+// as allowed by the package spec.
+var dimensionFieldTypes = []string{
+	"constant_keyword",
+	"keyword",
+	"long",
+	"integer",
+	"short",
+	"byte",
+	"double",
+	"float",
+	"half_float",
+	"scaled_float",
+	"unsigned_long",
+	"ip",
+}
+
+// metricFieldTypes are the field types that can be used as metrics in time series data streams.
+var metricFieldTypes = []string{
+	"byte",
+	"short",
+	"integer",
+	"long",
+	"unsigned_long",
+	"float",
+	"half_float",
+	"double",
+	"scaled_float",
+	"histogram",
+	"aggregate_metric_double",
+}
+
+// validateIndexModeDefinition checks that the field definition can be used with the given index mode.
+func validateIndexModeDefinition(indexMode string, definition FieldDefinition) error {
+	return errors.Join(indexModeDefinitionErrors(indexMode, definition)...)
+}
+
+func indexModeDefinitionErrors(indexMode string, definition FieldDefinition) []error {
+	if indexMode != timeSeriesIndexMode {
+		return nil
+	}
+
+	var errs []error
+	if definition.Dimension && !slices.Contains(dimensionFieldTypes, definition.Type) {
+		errs = append(errs, fmt.Errorf("dimension fields can't be of type %q, supported types are %s", definition.Type, strings.Join(dimensionFieldTypes, ", ")))
+	}
+	if definition.MetricType != "" && !slices.Contains(metricFieldTypes, metricFieldType(definition)) {
+		errs = append(errs, fmt.Errorf("metric fields must be numeric, found type %q", metricFieldType(definition)))
+	}
+	if definition.Dimension && definition.MetricType != "" {
+		errs = append(errs, errors.New("fields can't be dimensions and metrics at the same time"))
+	}
+	return errs
+}
+
+// metricFieldType returns the type of the values of a metric field. Objects with an object type,
+// usually defined with wildcards, map their values with this type.
+func metricFieldType(definition FieldDefinition) string {
+	if definition.Type == "object" && definition.ObjectType != "" {
+		return definition.ObjectType
+	}
+	return definition.Type
+}
+
+// InvalidDimensionFields returns problems for the fields of the package declared as dimensions whose
+// definitions can't be used as dimensions in time series data streams. Problems are reported in the
+// fields directory of the validator.
+func (v *Validator) InvalidDimensionFields() []packages.Problem {
+	return findInvalidDimensionFields(v.fieldsDir, v.packageSchema, "")
+}
+
+func findInvalidDimensionFields(path string, schema []FieldDefinition, prefix string) []packages.Problem {
+	var invalid []packages.Problem
+	for _, def := range schema {
+		fullName := def.Name
+		if prefix != "" {
+			fullName = prefix + "." + fullName
+		}
+		invalid = append(invalid, findInvalidDimensionFields(path, def.Fields, fullName)...)
+
+		if !def.Dimension {
+			continue
+		}
+		if def.External != "" && def.Type == "" {
+			// The type of external fields is defined in their external schema.
+			continue
+		}
+		for _, err := range indexModeDefinitionErrors(timeSeriesIndexMode, def) {
+			invalid = append(invalid, packages.Problem{
+				Path:    path,
+				Message: fmt.Sprintf("field %q (type %q) can't be used as dimension: %s", fullName, def.Type, err),
+			})
+		}
+	}
+	return invalid
+}
@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestValidateIndexModeDefinition(t *testing.T) {
+	cases := []struct {
+		title      string
+		indexMode  string
+		definition FieldDefinition
+		valid      bool
+	}{
+		{
+			title:      "keyword dimension",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "host.name", Type: "keyword", Dimension: true},
+			valid:      true,
+		},
+		{
+			title:      "text dimension",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "message", Type: "text", Dimension: true},
+			valid:      false,
+		},
+		{
+			title:      "numeric metric",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "system.cpu.total.pct", Type: "scaled_float", MetricType: "gauge"},
+			valid:      true,
+		},
+		{
+			title:      "keyword metric",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "system.cpu.name", Type: "keyword", MetricType: "gauge"},
+			valid:      false,
+		},
+		{
+			title:      "object metric with numeric object type",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "prometheus.*.value", Type: "object", ObjectType: "double", MetricType: "gauge"},
+			valid:      true,
+		},
+		{
+			title:      "object metric with keyword object type",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "prometheus.*.value", Type: "object", ObjectType: "keyword", MetricType: "gauge"},
+			valid:      false,
+		},
+		{
+			title:      "dimension and metric",
+			indexMode:  "time_series",
+			definition: FieldDefinition{Name: "system.cpu.cores", Type: "long", Dimension: true, MetricType: "gauge"},
+			valid:      false,
+		},
+		{
+			title:      "keyword metric in standard mode",
+			indexMode:  "standard",
+			definition: FieldDefinition{Name: "system.cpu.name", Type: "keyword", MetricType: "gauge"},
+			valid:      true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := validateIndexModeDefinition(c.indexMode, c.definition)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestInvalidDimensionFields(t *testing.T) {
+	v := &Validator{
+		fieldsDir: "fields",
+		packageSchema: []FieldDefinition{
+			{
+				Name: "host",
+				Type: "group",
+				Fields: []FieldDefinition{
+					{Name: "name", Type: "keyword", Dimension: true},
+					{Name: "ip", Type: "ip", Dimension: true},
+					{Name: "description", Type: "text", Dimension: true},
+					{Name: "up", Type: "boolean", Dimension: true},
+					{Name: "load", Type: "double", Dimension: true},
+				},
+			},
+			{Name: "system.cpu.cores", Type: "long", Dimension: true, MetricType: "gauge"},
+			{Name: "system.cpu.total.pct", Type: "scaled_float", MetricType: "gauge"},
+			{Name: "service.name", External: "ecs", Dimension: true},
+		},
+	}
+
+	invalid := v.InvalidDimensionFields()
+	assert.Equal(t, []packages.Problem{
+		{Path: "fields", Message: `field "host.description" (type "text") can't be used as dimension: dimension fields can't be of type "text", supported types are constant_keyword, keyword, long, integer, short, byte, double, float, half_float, scaled_float, unsigned_long, ip`},
+		{Path: "fields", Message: `field "host.up" (type "boolean") can't be used as dimension: dimension fields can't be of type "boolean", supported types are constant_keyword, keyword, long, integer, short, byte, double, float, half_float, scaled_float, unsigned_long, ip`},
+		{Path: "fields", Message: `field "system.cpu.cores" (type "long") can't be used as dimension: fields can't be dimensions and metrics at the same time`},
+	}, invalid)
+}
@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const (
+	// LintRuleDescription requires a description in all fields, except groups and external fields.
+	LintRuleDescription = "description"
+
+	// LintRuleMetricUnit requires a unit in fields with a metric type.
+	LintRuleMetricUnit = "metric-unit"
+
+	// LintRuleAllowedValues requires allowed values in keyword fields with expected values, so
+	// these values are documented and validated.
+	LintRuleAllowedValues = "allowed-values"
+)
+
+// LintRules are all the rules that can be enforced in field definitions.
+var LintRules = []string{
+	LintRuleDescription,
+	LintRuleMetricUnit,
+	LintRuleAllowedValues,
+}
+
+// LintPackageFields checks the fields files of the package and its data streams with the given
+// rules. Field definitions that don't follow a rule are reported as problems, and files that can't
+// be parsed are always reported.
+func LintPackageFields(packageRoot string, rules []string) ([]packages.Problem, error) {
+	for _, rule := range rules {
+		if !slices.Contains(LintRules, rule) {
+			return nil, fmt.Errorf("unknown lint rule %q", rule)
+		}
+	}
+
+	var fieldsFiles []string
+	for _, pattern := range []string{
+		filepath.Join(packageRoot, "fields", "*.yml"),
+		filepath.Join(packageRoot, "data_stream", "*", "fields", "*.yml"),
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("can't find fields files: %w", err)
+		}
+		fieldsFiles = append(fieldsFiles, matches...)
+	}
+
+	var violations []packages.Problem
+	for _, path := range fieldsFiles {
+		d, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading fields file failed: %w", err)
+		}
+		relPath, err := filepath.Rel(packageRoot, path)
+		if err != nil {
+			relPath = path
+		}
+		var defs FieldDefinitions
+		err = yaml.Unmarshal(d, &defs)
+		if err != nil {
+			violations = append(violations, packages.Problem{Path: relPath, Message: fmt.Sprintf("can't parse fields file: %v", err)})
+			continue
+		}
+		violations = append(violations, lintFieldDefinitions(relPath, "", defs, rules)...)
+	}
+	return violations, nil
+}
+
+func lintFieldDefinitions(path, prefix string, defs []FieldDefinition, rules []string) []packages.Problem {
+	var violations []packages.Problem
+	for _, def := range defs {
+		fullName := def.Name
+		if prefix != "" {
+			fullName = prefix + "." + fullName
+		}
+		if def.Type == "group" || len(def.Fields) > 0 {
+			violations = append(violations, lintFieldDefinitions(path, fullName, def.Fields, rules)...)
+			continue
+		}
+		if def.External != "" {
+			// Properties of external fields are defined in their schemas.
+			continue
+		}
+
+		violation := func(rule, message string) {
+			violations = append(violations, packages.Problem{
+				Path:    path,
+				Message: fmt.Sprintf("field %q %s (%s)", fullName, message, rule),
+			})
+		}
+		if slices.Contains(rules, LintRuleDescription) && def.Description == "" {
+			violation(LintRuleDescription, "has no description")
+		}
+		if slices.Contains(rules, LintRuleMetricUnit) && def.MetricType != "" && def.Unit == "" {
+			violation(LintRuleMetricUnit, fmt.Sprintf("has metric type %q but no unit", def.MetricType))
+		}
+		if slices.Contains(rules, LintRuleAllowedValues) && def.Type == "keyword" && len(def.ExpectedValues) > 0 && len(def.AllowedValues) == 0 {
+			violation(LintRuleAllowedValues, "has expected values but no allowed values")
+		}
+	}
+	return violations
+}
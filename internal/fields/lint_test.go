@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestLintPackageFields(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, filepath.Join("fields", "ecs.yml"), `
+- name: host.name
+  external: ecs
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "status", "fields", "fields.yml"), `
+- name: nginx.status
+  type: group
+  description: Status metrics.
+  fields:
+    - name: requests
+      type: long
+      description: Number of requests.
+      metric_type: counter
+    - name: memory
+      type: long
+      description: Memory used.
+      metric_type: gauge
+      unit: byte
+    - name: state
+      type: keyword
+      expected_values: [running, stopped]
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "broken", "fields", "fields.yml"), `
+- name: [
+`)
+
+	statusPath := filepath.Join("data_stream", "status", "fields", "fields.yml")
+	brokenPath := filepath.Join("data_stream", "broken", "fields", "fields.yml")
+
+	t.Run("without rules", func(t *testing.T) {
+		violations, err := LintPackageFields(packageRoot, nil)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Equal(t, brokenPath, violations[0].Path)
+	})
+
+	t.Run("all rules", func(t *testing.T) {
+		violations, err := LintPackageFields(packageRoot, LintRules)
+		require.NoError(t, err)
+		assert.Equal(t, []packages.Problem{
+			violations[0],
+			{Path: statusPath, Message: `field "nginx.status.requests" has metric type "counter" but no unit (metric-unit)`},
+			{Path: statusPath, Message: `field "nginx.status.state" has no description (description)`},
+			{Path: statusPath, Message: `field "nginx.status.state" has expected values but no allowed values (allowed-values)`},
+		}, violations)
+		assert.Equal(t, brokenPath, violations[0].Path)
+	})
+
+	t.Run("selected rules", func(t *testing.T) {
+		violations, err := LintPackageFields(packageRoot, []string{LintRuleDescription})
+		require.NoError(t, err)
+		require.Len(t, violations, 2)
+		assert.Contains(t, violations[1].Message, `"nginx.status.state"`)
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		_, err := LintPackageFields(packageRoot, []string{"emoji"})
+		assert.Error(t, err)
+	})
+}
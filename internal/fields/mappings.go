@@ -7,6 +7,7 @@ package fields
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -92,6 +93,35 @@ func createValidatorForMappingsAndPackageRoot(opts ...MappingValidatorOption) (v
 	return v, nil
 }
 
+// MappingError is a mappings validation error that refers to a field.
+type MappingError struct {
+	// Field is the path of the field, or of the field parameter, the error refers to.
+	Field string
+	Err   error
+}
+
+func (e *MappingError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MappingError) Unwrap() error {
+	return e.Err
+}
+
+// withMappingField makes the errors that don't refer to any field yet refer to the given one.
+func withMappingField(path string, errs multierror.Error) multierror.Error {
+	if path == "" {
+		return errs
+	}
+	for i, err := range errs {
+		var mappingErr *MappingError
+		if !errors.As(err, &mappingErr) {
+			errs[i] = &MappingError{Field: path, Err: err}
+		}
+	}
+	return errs
+}
+
 func (v *MappingValidator) ValidateIndexMappings(ctx context.Context) multierror.Error {
 	var errs multierror.Error
 	logger.Debugf("Get Mappings from data stream (%s)", v.dataStreamName)
@@ -138,6 +168,16 @@ func (v *MappingValidator) ValidateIndexMappings(ctx context.Context) multierror
 	//     - if it does not match, there should be some issue and it should be reported
 	//     - If the mapping is a constant_keyword type (e.g. data_stream.dataset), how to check the value?
 	//         - if the constant_keyword is defined in the preview, it should be the same
+	var rawActual map[string]any
+	err = json.Unmarshal(actualMappings, &rawActual)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to unmarshal actual mappings (data stream %s): %w", v.dataStreamName, err))
+		return errs.Unique()
+	}
+
+	// Group fields must be mapped as objects, even if the mappings are the same as in the preview.
+	errs = append(errs, v.validateGroupMappings("", v.Schema, rawActual)...)
+
 	if diff := cmp.Diff(actualMappings, previewMappings, transformJSON); diff == "" {
 		logger.Debug("No changes found in mappings")
 		return errs.Unique()
@@ -149,12 +189,6 @@ func (v *MappingValidator) ValidateIndexMappings(ctx context.Context) multierror
 		errs = append(errs, fmt.Errorf("failed to unmarshal preview mappings (index template %s): %w", v.indexTemplateName, err))
 		return errs.Unique()
 	}
-	var rawActual map[string]any
-	err = json.Unmarshal(actualMappings, &rawActual)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("failed to unmarshal actual mappings (data stream %s): %w", v.dataStreamName, err))
-		return errs.Unique()
-	}
 
 	var rawDynamicTemplates []map[string]any
 	err = json.Unmarshal(actualDynamicTemplates, &rawDynamicTemplates)
@@ -173,6 +207,55 @@ func (v *MappingValidator) ValidateIndexMappings(ctx context.Context) multierror
 	return nil
 }
 
+// validateGroupMappings checks that the fields defined as groups in the schema are mapped as objects.
+// Groups mapped with other types, like flattened, break queries on their subfields.
+func (v *MappingValidator) validateGroupMappings(path string, schema []FieldDefinition, properties map[string]any) multierror.Error {
+	var errs multierror.Error
+	for _, def := range schema {
+		currentPath := currentMappingPath(path, def.Name)
+		if def.Type != "group" {
+			continue
+		}
+		if slices.Contains(v.exceptionFields, currentPath) {
+			continue
+		}
+		errs = append(errs, v.validateGroupMappings(currentPath, def.Fields, properties)...)
+
+		mapping := findMappingDefinition(currentPath, properties)
+		if mapping == nil {
+			// Groups without ingested subfields may not be mapped.
+			continue
+		}
+		if mappingType := mappingParameter("type", mapping); mappingType != "" && mappingType != "object" {
+			errs = append(errs, &MappingError{
+				Field: currentPath,
+				Err:   fmt.Errorf("field %q is defined as group but it is mapped as %q, expected object", currentPath, mappingType),
+			})
+		}
+	}
+	return errs
+}
+
+// findMappingDefinition returns the mapping definition of the given field path in the properties of
+// the mappings, or nil if it is not found.
+func findMappingDefinition(path string, properties map[string]any) map[string]any {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		definition, ok := properties[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		if i == len(keys)-1 {
+			return definition
+		}
+		properties, ok = definition["properties"].(map[string]any)
+		if !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
 func currentMappingPath(path, key string) string {
 	if path == "" {
 		return key
@@ -527,16 +610,16 @@ func (v *MappingValidator) validateObjectProperties(path string, couldBeParamete
 					continue
 				}
 				ecsErrors := v.validateMappingsNotInPreview(currentPath, childField, dynamicTemplates)
-				errs = append(errs, ecsErrors...)
+				errs = append(errs, withMappingField(currentPath, ecsErrors)...)
 				continue
 			}
 			// Field or Parameter not defined
-			errs = append(errs, fmt.Errorf("field %q is undefined", currentPath))
+			errs = append(errs, &MappingError{Field: currentPath, Err: fmt.Errorf("field %q is undefined", currentPath)})
 			continue
 		}
 
 		fieldErrs := v.validateObjectMappingAndParameters(preview[key], value, currentPath, dynamicTemplates, true)
-		errs = append(errs, fieldErrs...)
+		errs = append(errs, withMappingField(currentPath, fieldErrs)...)
 	}
 	if len(errs) == 0 {
 		return nil
@@ -567,7 +650,7 @@ func (v *MappingValidator) validateMappingsNotInPreview(currentPath string, chil
 
 		def, ok := object.(map[string]any)
 		if !ok {
-			errs = append(errs, fmt.Errorf("invalid field definition/mapping for path: %q", fieldPath))
+			errs = append(errs, &MappingError{Field: fieldPath, Err: fmt.Errorf("invalid field definition/mapping for path: %q", fieldPath)})
 			continue
 		}
 
@@ -588,7 +671,7 @@ func (v *MappingValidator) validateMappingsNotInPreview(currentPath string, chil
 		ecsErrs := v.validateMappingInECSSchema(fieldPath, def)
 		if len(ecsErrs) > 0 {
 			for _, e := range ecsErrs {
-				errs = append(errs, fmt.Errorf("field %q is undefined: %w", fieldPath, e))
+				errs = append(errs, &MappingError{Field: fieldPath, Err: fmt.Errorf("field %q is undefined: %w", fieldPath, e)})
 			}
 		}
 	}
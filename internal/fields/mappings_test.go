@@ -869,3 +869,110 @@ func TestComparingMappings(t *testing.T) {
 		})
 	}
 }
+
+func TestMappingErrorsReferToFields(t *testing.T) {
+	preview := map[string]any{
+		"foo": map[string]any{
+			"type": "keyword",
+		},
+		"bar": map[string]any{
+			"properties": map[string]any{
+				"baz": map[string]any{
+					"type": "long",
+				},
+			},
+		},
+	}
+	actual := map[string]any{
+		"foo": map[string]any{
+			"type": "text",
+		},
+		"bar": map[string]any{
+			"properties": map[string]any{
+				"baz": map[string]any{
+					"type": "long",
+				},
+				"qux": map[string]any{
+					"type": "keyword",
+				},
+			},
+		},
+	}
+
+	v, err := CreateValidatorForMappings(nil)
+	require.NoError(t, err)
+
+	var fields []string
+	for _, err := range v.compareMappings("", false, preview, actual, nil) {
+		var mappingErr *MappingError
+		if assert.ErrorAs(t, err, &mappingErr) {
+			fields = append(fields, mappingErr.Field)
+		}
+	}
+	assert.ElementsMatch(t, []string{"foo.type", "bar.qux"}, fields)
+}
+
+func TestValidateGroupMappings(t *testing.T) {
+	schema := []FieldDefinition{
+		{
+			Name: "host",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{Name: "name", Type: "keyword"},
+				{
+					Name: "os",
+					Type: "group",
+					Fields: []FieldDefinition{
+						{Name: "name", Type: "keyword"},
+					},
+				},
+			},
+		},
+		{
+			Name: "process",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{Name: "name", Type: "keyword"},
+			},
+		},
+		{
+			Name: "labels",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{Name: "custom", Type: "keyword"},
+			},
+		},
+		{
+			Name: "user",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{Name: "name", Type: "keyword"},
+			},
+		},
+		{Name: "message", Type: "match_only_text"},
+	}
+	actual := map[string]any{
+		"host": map[string]any{
+			"properties": map[string]any{
+				"name": map[string]any{"type": "keyword"},
+				"os":   map[string]any{"type": "flattened"},
+			},
+		},
+		"process": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"name": map[string]any{"type": "keyword"}},
+		},
+		"labels":  map[string]any{"type": "keyword"},
+		"message": map[string]any{"type": "match_only_text"},
+	}
+
+	v, err := CreateValidatorForMappings(nil,
+		WithMappingValidatorFallbackSchema(schema),
+		WithMappingValidatorExceptionFields([]string{"labels"}),
+	)
+	require.NoError(t, err)
+
+	errs := v.validateGroupMappings("", v.Schema, actual)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `field "host.os" is defined as group but it is mapped as "flattened", expected object`)
+}
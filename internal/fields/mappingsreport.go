@@ -0,0 +1,123 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+// MappingsReport is the comparison of the mappings of a data stream with the mappings expected from
+// its index template.
+type MappingsReport struct {
+	DataStream    string               `json:"data_stream"`
+	IndexTemplate string               `json:"index_template"`
+	Fields        []MappingFieldReport `json:"fields"`
+
+	// Errors contains the validation errors that are not related to any specific field.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// MappingFieldReport is the comparison of the expected and actual mappings of a field.
+type MappingFieldReport struct {
+	Name     string         `json:"name"`
+	Expected map[string]any `json:"expected,omitempty"`
+	Actual   map[string]any `json:"actual,omitempty"`
+	Valid    bool           `json:"valid"`
+	Errors   []string       `json:"errors,omitempty"`
+}
+
+// MappingsReport builds a report with the expected and actual mappings of each field of the data stream,
+// including the fields that were validated successfully. Errors found during validation are included in
+// the fields they refer to.
+func (v *MappingValidator) MappingsReport(ctx context.Context, errs multierror.Error) (*MappingsReport, error) {
+	_, actualMappings, err := v.esClient.DataStreamMappings(ctx, v.dataStreamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mappings from ES (data stream %s): %w", v.dataStreamName, err)
+	}
+	_, previewMappings, err := v.esClient.SimulateIndexTemplate(ctx, v.indexTemplateName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mappings from index template preview (%s): %w", v.indexTemplateName, err)
+	}
+
+	var rawPreview, rawActual map[string]any
+	err = json.Unmarshal(previewMappings, &rawPreview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal preview mappings (index template %s): %w", v.indexTemplateName, err)
+	}
+	err = json.Unmarshal(actualMappings, &rawActual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal actual mappings (data stream %s): %w", v.dataStreamName, err)
+	}
+
+	report, err := buildMappingsReport(rawPreview, rawActual, errs)
+	if err != nil {
+		return nil, err
+	}
+	report.DataStream = v.dataStreamName
+	report.IndexTemplate = v.indexTemplateName
+	return report, nil
+}
+
+func buildMappingsReport(preview, actual map[string]any, errs multierror.Error) (*MappingsReport, error) {
+	flatPreview, err := flattenMappings("", map[string]any{"properties": preview})
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten preview mappings: %w", err)
+	}
+	flatActual, err := flattenMappings("", map[string]any{"properties": actual})
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten actual mappings: %w", err)
+	}
+
+	var names []string
+	for name := range flatPreview {
+		names = append(names, name)
+	}
+	for name := range flatActual {
+		if _, found := flatPreview[name]; !found {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var report MappingsReport
+	reported := make([]bool, len(errs))
+	for _, name := range names {
+		field := MappingFieldReport{Name: name}
+		field.Expected, _ = flatPreview[name].(map[string]any)
+		field.Actual, _ = flatActual[name].(map[string]any)
+		for i, e := range errs {
+			if errorRefersToField(e, name) {
+				field.Errors = append(field.Errors, e.Error())
+				reported[i] = true
+			}
+		}
+		field.Valid = len(field.Errors) == 0
+		report.Fields = append(report.Fields, field)
+	}
+	for i, e := range errs {
+		if !reported[i] {
+			report.Errors = append(report.Errors, e.Error())
+		}
+	}
+	return &report, nil
+}
+
+// errorRefersToField checks if a validation error refers to the given field, to any of its parents,
+// or to any of its parameters or multi-fields.
+func errorRefersToField(err error, name string) bool {
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		return false
+	}
+	path := mappingErr.Field
+	return path == name || strings.HasPrefix(name, path+".") || strings.HasPrefix(path, name+".")
+}
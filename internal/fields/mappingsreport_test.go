@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+func TestBuildMappingsReport(t *testing.T) {
+	preview := map[string]any{
+		"host": map[string]any{
+			"properties": map[string]any{
+				"name": map[string]any{"type": "keyword"},
+			},
+		},
+		"message": map[string]any{"type": "match_only_text"},
+	}
+	actual := map[string]any{
+		"host": map[string]any{
+			"properties": map[string]any{
+				"name": map[string]any{"type": "keyword"},
+				"ip":   map[string]any{"type": "keyword"},
+			},
+		},
+		"size": map[string]any{"type": "float"},
+	}
+	errs := multierror.Error{
+		&MappingError{
+			Field: "host.ip",
+			Err:   errors.New(`field "host.ip" is undefined: field definition not found`),
+		},
+		&MappingError{
+			Field: "size.type",
+			Err:   errors.New(`unexpected value found in mapping for field "size.type": preview mappings value ("long") different from the actual mappings value ("float")`),
+		},
+		// Errors are assigned to fields by their paths, not by their messages.
+		errors.New(`failed to compare "message" mappings`),
+		errors.New("dynamic templates are different (data stream logs-test-default)"),
+	}
+
+	report, err := buildMappingsReport(preview, actual, errs)
+	require.NoError(t, err)
+
+	expected := &MappingsReport{
+		Fields: []MappingFieldReport{
+			{
+				Name:   "host.ip",
+				Actual: map[string]any{"type": "keyword"},
+				Valid:  false,
+				Errors: []string{`field "host.ip" is undefined: field definition not found`},
+			},
+			{
+				Name:     "host.name",
+				Expected: map[string]any{"type": "keyword"},
+				Actual:   map[string]any{"type": "keyword"},
+				Valid:    true,
+			},
+			{
+				Name:     "message",
+				Expected: map[string]any{"type": "match_only_text"},
+				Valid:    true,
+			},
+			{
+				Name:   "size",
+				Actual: map[string]any{"type": "float"},
+				Valid:  false,
+				Errors: []string{`unexpected value found in mapping for field "size.type": preview mappings value ("long") different from the actual mappings value ("float")`},
+			},
+		},
+		Errors: []string{
+			`failed to compare "message" mappings`,
+			"dynamic templates are different (data stream logs-test-default)",
+		},
+	}
+	assert.Equal(t, expected, report)
+}
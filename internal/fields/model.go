@@ -24,8 +24,10 @@ type FieldDefinition struct {
 	AllowedValues  AllowedValues     `yaml:"allowed_values"`
 	ExpectedValues []string          `yaml:"expected_values"`
 	Pattern        string            `yaml:"pattern"`
+	DateFormat     string            `yaml:"date_format"`
 	Unit           string            `yaml:"unit"`
 	MetricType     string            `yaml:"metric_type"`
+	Dimension      bool              `yaml:"dimension"`
 	External       string            `yaml:"external"`
 	Index          *bool             `yaml:"index"`
 	Enabled        *bool             `yaml:"enabled"`
@@ -69,12 +71,18 @@ func (orig *FieldDefinition) Update(fd FieldDefinition) {
 	if fd.Pattern != "" {
 		orig.Pattern = fd.Pattern
 	}
+	if fd.DateFormat != "" {
+		orig.DateFormat = fd.DateFormat
+	}
 	if fd.Unit != "" {
 		orig.Unit = fd.Unit
 	}
 	if fd.MetricType != "" {
 		orig.MetricType = fd.MetricType
 	}
+	if fd.Dimension {
+		orig.Dimension = fd.Dimension
+	}
 	if fd.External != "" {
 		orig.External = fd.External
 	}
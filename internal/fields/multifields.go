@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// textFieldTypes are field types that can be used interchangeably for full-text search.
+var textFieldTypes = []string{"text", "match_only_text"}
+
+// MultiFieldConflicts looks for multifields of the fields of the package that override the ones
+// expected by ECS in incompatible ways. Fields are checked against the multifields added by
+// ecs@mappings, the ECS schema is only loaded if multifields are defined for external fields.
+// Conflicts are reported in the fields directory of the validator.
+func (v *Validator) MultiFieldConflicts(ecsSchema *ECSSchema) ([]packages.Problem, error) {
+	if !hasMultiFields(v.packageSchema, false) {
+		return nil, nil
+	}
+	var reference []FieldDefinition
+	if hasMultiFields(v.packageSchema, true) {
+		var err error
+		reference, err = ecsSchema.Fields()
+		if err != nil {
+			return nil, fmt.Errorf("can't load ECS schema: %w", err)
+		}
+	}
+	return findMultiFieldConflicts(v.fieldsDir, v.packageSchema, reference, ""), nil
+}
+
+// hasMultiFields checks if any field in the schema defines multifields, only external ones
+// are considered if onlyExternal is set.
+func hasMultiFields(schema []FieldDefinition, onlyExternal bool) bool {
+	return slices.ContainsFunc(schema, func(def FieldDefinition) bool {
+		if len(def.MultiFields) > 0 && (!onlyExternal || def.External != "") {
+			return true
+		}
+		return hasMultiFields(def.Fields, onlyExternal)
+	})
+}
+
+func findMultiFieldConflicts(path string, schema []FieldDefinition, reference []FieldDefinition, prefix string) []packages.Problem {
+	var conflicts []packages.Problem
+	for _, def := range schema {
+		fullName := def.Name
+		if prefix != "" {
+			fullName = prefix + "." + fullName
+		}
+		conflicts = append(conflicts, findMultiFieldConflicts(path, def.Fields, reference, fullName)...)
+
+		if len(def.MultiFields) == 0 {
+			continue
+		}
+		expected := expectedMultiFields(fullName, reference)
+		for _, mf := range def.MultiFields {
+			i := slices.IndexFunc(expected, func(e FieldDefinition) bool { return e.Name == mf.Name })
+			if i < 0 {
+				continue
+			}
+			if !compatibleMultiFieldTypes(mf.Type, expected[i].Type) {
+				conflicts = append(conflicts, packages.Problem{
+					Path:    path,
+					Message: fmt.Sprintf("field %q defines multifield %q with type %q, but ECS expects type %q", fullName, mf.Name, mf.Type, expected[i].Type),
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// expectedMultiFields returns the multifields defined in ECS for the given field, and the ones
+// added by ecs@mappings.
+func expectedMultiFields(name string, reference []FieldDefinition) []FieldDefinition {
+	var expected []FieldDefinition
+	if def := FindElementDefinition(name, reference); def != nil {
+		expected = append(expected, def.MultiFields...)
+	}
+	for _, rule := range ecsMappingMultifieldsRules {
+		if !rule.match(name) {
+			continue
+		}
+		for _, mf := range rule.definitions {
+			if !slices.ContainsFunc(expected, func(e FieldDefinition) bool { return e.Name == mf.Name }) {
+				expected = append(expected, mf)
+			}
+		}
+	}
+	return expected
+}
+
+func compatibleMultiFieldTypes(fieldType, expectedType string) bool {
+	if fieldType == expectedType {
+		return true
+	}
+	return slices.Contains(textFieldTypes, fieldType) && slices.Contains(textFieldTypes, expectedType)
+}
@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestFindMultiFieldConflicts(t *testing.T) {
+	reference := []FieldDefinition{
+		{
+			Name: "process",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{
+					Name: "args",
+					Type: "keyword",
+					MultiFields: []FieldDefinition{
+						{Name: "caseless", Type: "keyword"},
+					},
+				},
+			},
+		},
+	}
+
+	schema := []FieldDefinition{
+		{
+			Name: "process",
+			Type: "group",
+			Fields: []FieldDefinition{
+				{
+					Name: "args",
+					Type: "keyword",
+					MultiFields: []FieldDefinition{
+						{Name: "caseless", Type: "wildcard"},
+					},
+				},
+				{
+					// Compatible with the match_only_text multifield added by ecs@mappings.
+					Name: "name",
+					Type: "keyword",
+					MultiFields: []FieldDefinition{
+						{Name: "text", Type: "text"},
+					},
+				},
+			},
+		},
+		{
+			Name: "url.original",
+			Type: "wildcard",
+			MultiFields: []FieldDefinition{
+				{Name: "text", Type: "keyword"},
+				{Name: "custom", Type: "keyword"},
+			},
+		},
+	}
+
+	expected := []packages.Problem{
+		{Path: "fields", Message: `field "process.args" defines multifield "caseless" with type "wildcard", but ECS expects type "keyword"`},
+		{Path: "fields", Message: `field "url.original" defines multifield "text" with type "keyword", but ECS expects type "match_only_text"`},
+	}
+	assert.Equal(t, expected, findMultiFieldConflicts("fields", schema, reference, ""))
+}
+
+func TestMultiFieldConflictsWithoutMultiFields(t *testing.T) {
+	v := &Validator{
+		packageSchema: []FieldDefinition{
+			{Name: "process.name", Type: "keyword"},
+		},
+	}
+	ecsSchema := &ECSSchema{
+		load: func() ([]FieldDefinition, error) {
+			t.Fatal("ECS schema should not be loaded if the package doesn't define multifields")
+			return nil, nil
+		},
+	}
+
+	conflicts, err := v.MultiFieldConflicts(ecsSchema)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
+
+func TestMultiFieldConflictsWithoutExternalFields(t *testing.T) {
+	v := &Validator{
+		fieldsDir: "fields",
+		packageSchema: []FieldDefinition{
+			{
+				Name: "url.original",
+				Type: "wildcard",
+				MultiFields: []FieldDefinition{
+					{Name: "text", Type: "keyword"},
+				},
+			},
+		},
+	}
+	ecsSchema := &ECSSchema{
+		load: func() ([]FieldDefinition, error) {
+			t.Fatal("ECS schema should not be loaded if the package doesn't define multifields for external fields")
+			return nil, nil
+		},
+	}
+
+	conflicts, err := v.MultiFieldConflicts(ecsSchema)
+	assert.NoError(t, err)
+	expected := []packages.Problem{
+		{Path: "fields", Message: `field "url.original" defines multifield "text" with type "keyword", but ECS expects type "match_only_text"`},
+	}
+	assert.Equal(t, expected, conflicts)
+}
+
+func TestMultiFieldConflictsWithExternalFields(t *testing.T) {
+	v := &Validator{
+		fieldsDir: "fields",
+		packageSchema: []FieldDefinition{
+			{
+				Name:     "process.args",
+				External: "ecs",
+				MultiFields: []FieldDefinition{
+					{Name: "caseless", Type: "wildcard"},
+				},
+			},
+		},
+	}
+	ecsSchema := &ECSSchema{
+		load: func() ([]FieldDefinition, error) {
+			return []FieldDefinition{
+				{
+					Name: "process.args",
+					Type: "keyword",
+					MultiFields: []FieldDefinition{
+						{Name: "caseless", Type: "keyword"},
+					},
+				},
+			}, nil
+		},
+	}
+
+	conflicts, err := v.MultiFieldConflicts(ecsSchema)
+	assert.NoError(t, err)
+	expected := []packages.Problem{
+		{Path: "fields", Message: `field "process.args" defines multifield "caseless" with type "wildcard", but ECS expects type "keyword"`},
+	}
+	assert.Equal(t, expected, conflicts)
+}
@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+var (
+	// grokCapturePattern matches named captures in grok patterns, like %{IP:source.ip} or %{NUMBER:size:int}.
+	grokCapturePattern = regexp.MustCompile(`%\{[A-Za-z0-9_]+:([^:}]+)(?::[^}]*)?\}`)
+
+	// dissectKeyPattern matches keys in dissect patterns, like %{source.ip} or %{+message/2}.
+	dissectKeyPattern = regexp.MustCompile(`%\{([^}]*)\}`)
+)
+
+// pipelineTargetField is a field set by a processor of an ingest pipeline.
+type pipelineTargetField struct {
+	path      string
+	line      int
+	processor string
+	field     string
+}
+
+func (f pipelineTargetField) undefinedProblem() packages.Problem {
+	return packages.Problem{
+		Path:    fmt.Sprintf("%s:%d", f.path, f.line),
+		Message: fmt.Sprintf("%s processor sets undefined field %q", f.processor, f.field),
+	}
+}
+
+// UndefinedPipelineTargetFields looks for fields set by grok, dissect and set processors in the
+// ingest pipelines of the given data stream that are not defined in the schema nor in ECS. Fields set
+// dynamically, or temporary fields starting with underscore, are not reported.
+func (v *Validator) UndefinedPipelineTargetFields(dataStreamPath string, ecsSchema *ECSSchema) ([]packages.Problem, error) {
+	pipelineFiles, err := filepath.Glob(filepath.Join(dataStreamPath, "elasticsearch", "ingest_pipeline", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for ingest pipelines: %w", err)
+	}
+
+	var undefined []packages.Problem
+	for _, path := range pipelineFiles {
+		switch filepath.Ext(path) {
+		case ".yml", ".yaml", ".json":
+		default:
+			continue
+		}
+		targets, err := readPipelineTargetFields(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range targets {
+			if isDefinedTargetField(target.field, v.Schema) {
+				continue
+			}
+			// The ECS schema is only loaded for the fields not defined in the package.
+			ecsFields, err := ecsSchema.Fields()
+			if err != nil {
+				return nil, fmt.Errorf("can't load ECS schema: %w", err)
+			}
+			if !isDefinedTargetField(target.field, ecsFields) {
+				undefined = append(undefined, target.undefinedProblem())
+			}
+		}
+	}
+	return undefined, nil
+}
+
+func isDefinedTargetField(name string, schema []FieldDefinition) bool {
+	if FindElementDefinition(name, schema) != nil {
+		return true
+	}
+	if skipValidationForField(name) || !isParentEnabled(name, schema) {
+		return true
+	}
+	_, ancestor := findAncestorElementDefinition(name, schema, func(_ string, def *FieldDefinition) bool {
+		return def.Type == "object" || def.Type == "flattened"
+	})
+	return ancestor != nil
+}
+
+func readPipelineTargetFields(path string) ([]pipelineTargetField, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read ingest pipeline (path: %s): %w", path, err)
+	}
+
+	var pipeline struct {
+		Processors []yaml.Node `yaml:"processors"`
+		OnFailure  []yaml.Node `yaml:"on_failure"`
+	}
+	err = yaml.Unmarshal(d, &pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse ingest pipeline (path: %s): %w", path, err)
+	}
+
+	return processorsTargetFields(path, append(pipeline.Processors, pipeline.OnFailure...)), nil
+}
+
+func processorsTargetFields(path string, processors []yaml.Node) []pipelineTargetField {
+	var targets []pipelineTargetField
+	for _, processor := range processors {
+		if processor.Kind != yaml.MappingNode || len(processor.Content) != 2 {
+			continue
+		}
+		processorType := processor.Content[0].Value
+
+		var config struct {
+			Field     string      `yaml:"field"`
+			Pattern   string      `yaml:"pattern"`
+			Patterns  []string    `yaml:"patterns"`
+			OnFailure []yaml.Node `yaml:"on_failure"`
+		}
+		err := processor.Content[1].Decode(&config)
+		if err != nil {
+			// Processors with unexpected configurations are reported by package validation.
+			continue
+		}
+
+		var fields []string
+		switch processorType {
+		case "set":
+			fields = append(fields, config.Field)
+		case "grok":
+			for _, pattern := range config.Patterns {
+				fields = append(fields, grokTargetFields(pattern)...)
+			}
+		case "dissect":
+			fields = append(fields, dissectTargetFields(config.Pattern)...)
+		}
+		for _, field := range fields {
+			if isDynamicTargetField(field) {
+				continue
+			}
+			target := pipelineTargetField{
+				path:      path,
+				line:      processor.Line,
+				processor: processorType,
+				field:     field,
+			}
+			if !slices.Contains(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+
+		targets = append(targets, processorsTargetFields(path, config.OnFailure)...)
+	}
+	return targets
+}
+
+func grokTargetFields(pattern string) []string {
+	var fields []string
+	for _, match := range grokCapturePattern.FindAllStringSubmatch(pattern, -1) {
+		fields = append(fields, match[1])
+	}
+	return fields
+}
+
+func dissectTargetFields(pattern string) []string {
+	var fields []string
+	for _, match := range dissectKeyPattern.FindAllStringSubmatch(pattern, -1) {
+		key := match[1]
+		// Skip keys and reference keys don't set fields with their names.
+		if key == "" || strings.HasPrefix(key, "?") || strings.HasPrefix(key, "*") || strings.HasPrefix(key, "&") {
+			continue
+		}
+		key = strings.TrimPrefix(key, "+")
+		key = strings.TrimSuffix(key, "->")
+		if i := strings.LastIndex(key, "/"); i >= 0 {
+			key = key[:i]
+		}
+		fields = append(fields, key)
+	}
+	return fields
+}
+
+// isDynamicTargetField returns true for fields whose name is resolved at ingest time, and for
+// temporary and metadata fields, that are not expected to be defined.
+func isDynamicTargetField(field string) bool {
+	return field == "" || strings.Contains(field, "{{") || strings.HasPrefix(field, "_") || strings.HasPrefix(field, "@metadata")
+}
@@ -0,0 +1,109 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestUndefinedPipelineTargetFields(t *testing.T) {
+	dataStreamPath := t.TempDir()
+	pipelinePath := filestest.WriteFile(t, dataStreamPath, "elasticsearch/ingest_pipeline/default.yml", `---
+processors:
+  - set:
+      field: test.defined
+      value: foo
+  - set:
+      field: test.undefined
+      value: foo
+  - set:
+      field: _tmp.value
+      value: foo
+  - set:
+      field: "{{{_ingest.on_failure_processor_type}}}"
+      value: foo
+  - grok:
+      field: message
+      patterns:
+        - '%{IP:source.ip} %{NUMBER:test.size:int} %{WORD:test.method}'
+  - dissect:
+      field: message
+      pattern: '%{test.defined} %{?skipped} %{+test.joined/2} %{test.padded->} %{labels.custom}'
+on_failure:
+  - set:
+      field: error.message
+      value: failed
+      on_failure:
+        - set:
+            field: test.nested_failure
+            value: foo
+`)
+
+	v := &Validator{
+		Schema: []FieldDefinition{
+			{
+				Name: "test",
+				Type: "group",
+				Fields: []FieldDefinition{
+					{Name: "defined", Type: "keyword"},
+					{Name: "size", Type: "long"},
+					{Name: "padded", Type: "keyword"},
+				},
+			},
+		},
+	}
+	ecsSchema := &ECSSchema{
+		load: func() ([]FieldDefinition, error) {
+			return []FieldDefinition{
+				{Name: "source.ip", Type: "ip"},
+				{Name: "labels", Type: "object", ObjectType: "keyword"},
+				{Name: "error.message", Type: "match_only_text"},
+			}, nil
+		},
+	}
+
+	undefined, err := v.UndefinedPipelineTargetFields(dataStreamPath, ecsSchema)
+	require.NoError(t, err)
+
+	expected := []packages.Problem{
+		{Path: pipelinePath + ":6", Message: `set processor sets undefined field "test.undefined"`},
+		{Path: pipelinePath + ":15", Message: `grok processor sets undefined field "test.method"`},
+		{Path: pipelinePath + ":19", Message: `dissect processor sets undefined field "test.joined"`},
+		{Path: pipelinePath + ":27", Message: `set processor sets undefined field "test.nested_failure"`},
+	}
+	assert.Equal(t, expected, undefined)
+}
+
+func TestUndefinedPipelineTargetFieldsWithoutECS(t *testing.T) {
+	dataStreamPath := t.TempDir()
+	filestest.WriteFile(t, dataStreamPath, "elasticsearch/ingest_pipeline/default.yml", `---
+processors:
+  - set:
+      field: test.defined
+      value: foo
+`)
+
+	v := &Validator{
+		Schema: []FieldDefinition{
+			{Name: "test.defined", Type: "keyword"},
+		},
+	}
+	ecsSchema := &ECSSchema{
+		load: func() ([]FieldDefinition, error) {
+			t.Fatal("ECS schema should not be loaded if all fields are defined in the package")
+			return nil, nil
+		},
+	}
+
+	undefined, err := v.UndefinedPipelineTargetFields(dataStreamPath, ecsSchema)
+	require.NoError(t, err)
+	assert.Empty(t, undefined)
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
@@ -131,12 +132,26 @@ type Validator struct {
 	// Schema contains definition records.
 	Schema []FieldDefinition
 
+	// packageSchema contains the definitions of the fields of the package, without imported schemas.
+	packageSchema []FieldDefinition
+
+	// fieldsDir is the directory with the definitions of the fields of the package.
+	fieldsDir string
+
 	// SpecVersion contains the version of the spec used by the package.
 	specVersion semver.Version
 
 	// expectedDatasets contains the value expected for dataset fields.
 	expectedDatasets []string
 
+	// strictExpectedDatasets makes validation fail when an expected dataset template
+	// references fields missing in the document, instead of skipping the template.
+	strictExpectedDatasets bool
+
+	// datasetMismatchAsWarning logs a warning instead of failing when event.dataset and
+	// data_stream.dataset have different values.
+	datasetMismatchAsWarning bool
+
 	defaultNumericConversion bool
 
 	// fields that store keywords, but can be received as numeric types.
@@ -154,6 +169,19 @@ type Validator struct {
 
 	disabledNormalization bool
 
+	// indexMode is the index mode of the data stream whose documents are validated.
+	indexMode string
+
+	// explain adds hints about how to fix undefined fields to validation errors.
+	explain bool
+
+	// nextECSReference is the reference of a version of ECS to validate documents with, in
+	// addition to the version the package depends on.
+	nextECSReference string
+
+	// nextECSValidator validates documents with the version of ECS in nextECSReference.
+	nextECSValidator *Validator
+
 	injectFieldsOptions InjectFieldsOptions
 }
 
@@ -222,6 +250,26 @@ func WithExpectedDatasets(datasets []string) ValidatorOption {
 	}
 }
 
+// WithStrictExpectedDatasets configures the validator to fail when an expected dataset template
+// can't be rendered because it references fields missing in the document. By default these
+// templates are skipped.
+func WithStrictExpectedDatasets(strict bool) ValidatorOption {
+	return func(v *Validator) error {
+		v.strictExpectedDatasets = strict
+		return nil
+	}
+}
+
+// WithDatasetMismatchAsWarning configures the validator to log a warning instead of failing when
+// event.dataset and data_stream.dataset have different values, for packages that set them differently
+// on purpose.
+func WithDatasetMismatchAsWarning(asWarning bool) ValidatorOption {
+	return func(v *Validator) error {
+		v.datasetMismatchAsWarning = asWarning
+		return nil
+	}
+}
+
 // WithEnabledImportAllECSSchema configures the validator to check or not the fields with the complete ECS schema.
 func WithEnabledImportAllECSSChema(importSchema bool) ValidatorOption {
 	return func(v *Validator) error {
@@ -238,6 +286,33 @@ func WithDisableNormalization(disabledNormalization bool) ValidatorOption {
 	}
 }
 
+// WithIndexMode configures the validator to enforce the rules of the given index mode on the
+// definitions of the fields found in documents.
+func WithIndexMode(mode string) ValidatorOption {
+	return func(v *Validator) error {
+		v.indexMode = mode
+		return nil
+	}
+}
+
+// WithExplanations configures the validator to explain how undefined fields could be defined.
+func WithExplanations(explain bool) ValidatorOption {
+	return func(v *Validator) error {
+		v.explain = explain
+		return nil
+	}
+}
+
+// WithNextECSReference configures the validator to also validate documents with the given version of ECS,
+// reporting fields that would be invalid after upgrading the ECS dependency of the package to this version.
+// The reference has the same format as the one used in the build manifest (e.g. git@v8.17.0).
+func WithNextECSReference(reference string) ValidatorOption {
+	return func(v *Validator) error {
+		v.nextECSReference = reference
+		return nil
+	}
+}
+
 // WithInjectFieldsOptions configures fields injection.
 func WithInjectFieldsOptions(options InjectFieldsOptions) ValidatorOption {
 	return func(v *Validator) error {
@@ -275,32 +350,61 @@ func createValidatorForDirectoryAndPackageRoot(fieldsParentDir string, finder pa
 	v.allowedCIDRs = initializeAllowedCIDRsList()
 
 	fieldsDir := filepath.Join(fieldsParentDir, "fields")
+	v.fieldsDir = fieldsDir
 
-	var fdm *DependencyManager
+	var packageRoot string
 	if !v.disabledDependencyManagement {
-		packageRoot, found, err := finder.FindPackageRoot()
+		root, found, err := finder.FindPackageRoot()
 		if err != nil {
 			return nil, fmt.Errorf("can't find package root: %w", err)
 		}
 		if !found {
 			return nil, errors.New("package root not found and dependency management is enabled")
 		}
-		fdm, v.Schema, err = initDependencyManagement(packageRoot, v.specVersion, v.enabledImportAllECSSchema)
+		packageRoot = root
+	}
+
+	v.packageSchema, v.Schema, err = v.loadSchema(fieldsDir, packageRoot, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if v.nextECSReference != "" {
+		if v.disabledDependencyManagement {
+			return nil, errors.New("validation with another version of ECS requires dependency management")
+		}
+		next := *v
+		next.nextECSReference = ""
+		next.packageSchema, next.Schema, err = v.loadSchema(fieldsDir, packageRoot, v.nextECSReference)
 		if err != nil {
-			return nil, fmt.Errorf("failed to initialize dependency management: %w", err)
+			return nil, fmt.Errorf("can't load schema with ECS %s: %w", v.nextECSReference, err)
+		}
+		v.nextECSValidator = &next
+	}
+	return v, nil
+}
+
+// loadSchema loads the definitions of the fields in the given directory, and the complete schema used
+// for validation, including imported external fields. If ecsReference is not empty, it overrides the
+// version of ECS defined in the build manifest of the package.
+func (v *Validator) loadSchema(fieldsDir string, packageRoot string, ecsReference string) (packageSchema []FieldDefinition, schema []FieldDefinition, err error) {
+	var fdm *DependencyManager
+	if !v.disabledDependencyManagement {
+		fdm, schema, err = initDependencyManagement(packageRoot, v.specVersion, v.enabledImportAllECSSchema, ecsReference)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize dependency management: %w", err)
 		}
 	}
 
 	fields, err := loadFieldsFromDir(fieldsDir, fdm, v.injectFieldsOptions)
 	if err != nil {
-		return nil, fmt.Errorf("can't load fields from directory (path: %s): %w", fieldsDir, err)
+		return nil, nil, fmt.Errorf("can't load fields from directory (path: %s): %w", fieldsDir, err)
 	}
 
-	v.Schema = append(fields, v.Schema...)
-	return v, nil
+	return fields, append(fields, schema...), nil
 }
 
-func initDependencyManagement(packageRoot string, specVersion semver.Version, importECSSchema bool) (*DependencyManager, []FieldDefinition, error) {
+func initDependencyManagement(packageRoot string, specVersion semver.Version, importECSSchema bool, ecsReference string) (*DependencyManager, []FieldDefinition, error) {
 	buildManifest, ok, err := buildmanifest.ReadBuildManifest(packageRoot)
 	if err != nil {
 		return nil, nil, fmt.Errorf("can't read build manifest: %w", err)
@@ -309,6 +413,9 @@ func initDependencyManagement(packageRoot string, specVersion semver.Version, im
 		// There is no build manifest, nothing to do.
 		return nil, nil, nil
 	}
+	if ecsReference != "" {
+		buildManifest.Dependencies.ECS.Reference = ecsReference
+	}
 
 	fdm, err := CreateFieldDependencyManager(buildManifest.Dependencies)
 	if err != nil {
@@ -437,25 +544,26 @@ func ecsPathWithMultifieldsMatch(name string) bool {
 	return false
 }
 
-// appendECSMappingMultifields adds multifields included in ecs@mappings that are not defined anywhere, for fields
-// that don't define any multifield.
-func appendECSMappingMultifields(schema []FieldDefinition, prefix string) []FieldDefinition {
-	rules := []struct {
-		match       func(name string) bool
-		definitions []FieldDefinition
-	}{
-		{
-			match: ecsPathWithMultifieldsMatch,
-			definitions: []FieldDefinition{
-				{
-					Name:     "text",
-					Type:     "match_only_text",
-					External: externalFieldAppendedTag,
-				},
+// ecsMappingMultifieldsRules are the multifields added by ecs@mappings to the fields matching them.
+var ecsMappingMultifieldsRules = []struct {
+	match       func(name string) bool
+	definitions []FieldDefinition
+}{
+	{
+		match: ecsPathWithMultifieldsMatch,
+		definitions: []FieldDefinition{
+			{
+				Name:     "text",
+				Type:     "match_only_text",
+				External: externalFieldAppendedTag,
 			},
 		},
-	}
+	},
+}
 
+// appendECSMappingMultifields adds multifields included in ecs@mappings that are not defined anywhere, for fields
+// that don't define any multifield.
+func appendECSMappingMultifields(schema []FieldDefinition, prefix string) []FieldDefinition {
 	var result []FieldDefinition
 	for _, def := range schema {
 		fullName := def.Name
@@ -464,7 +572,7 @@ func appendECSMappingMultifields(schema []FieldDefinition, prefix string) []Fiel
 		}
 		def.Fields = appendECSMappingMultifields(def.Fields, fullName)
 
-		for _, rule := range rules {
+		for _, rule := range ecsMappingMultifieldsRules {
 			if !rule.match(fullName) {
 				continue
 			}
@@ -562,12 +670,31 @@ func (v *Validator) ValidateDocumentBody(body json.RawMessage) multierror.Error
 func (v *Validator) ValidateDocumentMap(body common.MapStr) multierror.Error {
 	errs := v.validateDocumentValues(body)
 	errs = append(errs, v.validateMapElement("", body, body)...)
+	if v.nextECSValidator != nil {
+		errs = append(errs, v.validateDocumentWithNextECS(body, errs)...)
+	}
 	if len(errs) == 0 {
 		return nil
 	}
 	return errs
 }
 
+// validateDocumentWithNextECS validates the document with the next version of ECS, and returns the errors
+// that were not found when validating with the current version.
+func (v *Validator) validateDocumentWithNextECS(body common.MapStr, current multierror.Error) multierror.Error {
+	var errs multierror.Error
+	for _, err := range v.nextECSValidator.ValidateDocumentMap(body) {
+		found := slices.ContainsFunc(current, func(e error) bool {
+			return e.Error() == err.Error()
+		})
+		if found {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("validation would fail with ECS %s: %w", v.nextECSReference, err))
+	}
+	return errs
+}
+
 var datasetFieldNames = []string{
 	"event.dataset",
 	"data_stream.dataset",
@@ -576,45 +703,126 @@ var datasetFieldNames = []string{
 func (v *Validator) validateDocumentValues(body common.MapStr) multierror.Error {
 	var errs multierror.Error
 	if !v.specVersion.LessThan(semver2_0_0) && v.expectedDatasets != nil {
-		for _, datasetField := range datasetFieldNames {
-			value, err := body.GetValue(datasetField)
-			if errors.Is(err, common.ErrKeyNotFound) {
-				continue
-			}
+		errs = append(errs, v.validateExpectedDatasets(body)...)
+	}
+	if !v.specVersion.LessThan(semver2_0_0) {
+		if err := v.validateDatasetsMatch(body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
 
-			// Why do we render the expected datasets here?
-			// Because the expected datasets can contain
-			// mustache templates, and not just static
-			// strings.
-			//
-			// For example, the expected datasets for the
-			// Kubernetes container logs dataset can be:
-			//
-			//   - "{{kubernetes.labels.elastic_co/dataset}}"
-			//
-			var renderedExpectedDatasets []string
-			for _, dataset := range v.expectedDatasets {
-				renderedDataset, err := mustache.Render(dataset, body)
-				if err != nil {
-					err := fmt.Errorf("can't render expected dataset %q: %w", dataset, err)
-					errs = append(errs, err)
-					return errs
-				}
-				renderedExpectedDatasets = append(renderedExpectedDatasets, renderedDataset)
-			}
+// validateExpectedDatasets checks that the dataset fields of the document have one of the
+// expected datasets.
+func (v *Validator) validateExpectedDatasets(body common.MapStr) multierror.Error {
+	var errs multierror.Error
+	for _, datasetField := range datasetFieldNames {
+		value, err := body.GetValue(datasetField)
+		if errors.Is(err, common.ErrKeyNotFound) {
+			continue
+		}
 
-			str, ok := valueToString(value, v.disabledNormalization)
-			exists := stringInArray(str, renderedExpectedDatasets)
-			if !ok || !exists {
-				err := fmt.Errorf("field %q should have value in %q, it has \"%v\"",
-					datasetField, v.expectedDatasets, value)
-				errs = append(errs, err)
-			}
+		renderedExpectedDatasets, err := v.renderExpectedDatasets(body)
+		if err != nil {
+			// Rendering doesn't depend on the field, it would fail again for the next one.
+			errs = append(errs, err)
+			break
+		}
+
+		str, ok := valueToString(value, v.disabledNormalization)
+		exists := stringInArray(str, renderedExpectedDatasets)
+		if !ok || !exists {
+			err := fmt.Errorf("field %q should have value in %q, it has \"%v\"",
+				datasetField, v.expectedDatasets, value)
+			errs = append(errs, err)
 		}
 	}
 	return errs
 }
 
+// renderExpectedDatasets renders the expected datasets with the values of the document.
+//
+// Why do we render the expected datasets here?
+// Because the expected datasets can contain
+// mustache templates, and not just static
+// strings.
+//
+// For example, the expected datasets for the
+// Kubernetes container logs dataset can be:
+//
+//   - "{{kubernetes.labels.elastic_co/dataset}}"
+//
+// Templates referencing fields that are not in the document are skipped,
+// as happens with conditional routing, unless strict mode is enabled.
+func (v *Validator) renderExpectedDatasets(body common.MapStr) ([]string, error) {
+	var renderedExpectedDatasets []string
+	for _, dataset := range v.expectedDatasets {
+		tmpl, err := mustache.ParseString(dataset)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse expected dataset %q: %w", dataset, err)
+		}
+		if missing := missingTemplateFields(tmpl, body); len(missing) > 0 {
+			if v.strictExpectedDatasets {
+				return nil, fmt.Errorf("can't render expected dataset %q: missing fields in document: %s", dataset, strings.Join(missing, ", "))
+			}
+			logger.Debugf("Skipping expected dataset %q, missing fields in document: %s", dataset, strings.Join(missing, ", "))
+			continue
+		}
+		renderedDataset, err := tmpl.Render(body)
+		if err != nil {
+			return nil, fmt.Errorf("can't render expected dataset %q: %w", dataset, err)
+		}
+		renderedExpectedDatasets = append(renderedExpectedDatasets, renderedDataset)
+	}
+	return renderedExpectedDatasets, nil
+}
+
+// validateDatasetsMatch checks that event.dataset and data_stream.dataset have the same value when
+// both are present in the document. Different values are usually caused by mistakes in pipelines.
+func (v *Validator) validateDatasetsMatch(body common.MapStr) error {
+	eventDataset, err := body.GetValue("event.dataset")
+	if err != nil {
+		return nil
+	}
+	dataStreamDataset, err := body.GetValue("data_stream.dataset")
+	if err != nil {
+		return nil
+	}
+	// Values of unexpected types are reported by the validation of the fields.
+	eventDatasetStr, ok := eventDataset.(string)
+	if !ok {
+		return nil
+	}
+	dataStreamDatasetStr, ok := dataStreamDataset.(string)
+	if !ok || eventDatasetStr == dataStreamDatasetStr {
+		return nil
+	}
+
+	err = fmt.Errorf("field \"event.dataset\" should have the same value as \"data_stream.dataset\" (%q), it has %q",
+		dataStreamDatasetStr, eventDatasetStr)
+	if v.datasetMismatchAsWarning {
+		logger.Warn(err.Error())
+		return nil
+	}
+	return err
+}
+
+// missingTemplateFields returns the fields referenced by the variables of the template
+// that are not present in the document.
+func missingTemplateFields(tmpl *mustache.Template, body common.MapStr) []string {
+	var missing []string
+	for _, tag := range tmpl.Tags() {
+		if tag.Type() != mustache.Variable {
+			continue
+		}
+		if _, err := body.GetValue(tag.Name()); errors.Is(err, common.ErrKeyNotFound) {
+			missing = append(missing, tag.Name())
+		}
+	}
+	return missing
+}
+
 func stringInArray(target string, arr []string) bool {
 	// Check if target is part of the array
 	found := false
@@ -679,6 +887,14 @@ func (v *Validator) validateMapElement(root string, elem common.MapStr, doc comm
 	return errs
 }
 
+// undefinedFieldError adds an explanation of how to define the field to the error, if explanations are enabled.
+func (v *Validator) undefinedFieldError(err error, key string, val any) error {
+	if !v.explain {
+		return err
+	}
+	return fmt.Errorf("%w (%s)", err, explainUndefinedField(key, val, v.Schema))
+}
+
 func (v *Validator) validateScalarElement(key string, val any, doc common.MapStr) error {
 	if key == "" {
 		return nil // root key is always valid
@@ -692,13 +908,13 @@ func (v *Validator) validateScalarElement(key string, val any, doc common.MapStr
 		case isFlattenedSubfield(key, v.Schema):
 			return nil // flattened subfield, it will be stored as member of the flattened ancestor.
 		case isArrayOfObjects(val):
-			return fmt.Errorf(`field %q is used as array of objects, expected explicit definition with type group or nested`, key)
+			return v.undefinedFieldError(fmt.Errorf(`field %q is used as array of objects, expected explicit definition with type group or nested`, key), key, val)
 		case couldBeMultifield(key, v.Schema):
-			return fmt.Errorf(`field %q is undefined, could be a multifield`, key)
+			return v.undefinedFieldError(fmt.Errorf(`field %q is undefined, could be a multifield`, key), key, val)
 		case !isParentEnabled(key, v.Schema):
 			return nil // parent mapping is disabled
 		default:
-			return fmt.Errorf(`field %q is undefined`, key)
+			return v.undefinedFieldError(fmt.Errorf(`field %q is undefined`, key), key, val)
 		}
 	}
 
@@ -709,7 +925,12 @@ func (v *Validator) validateScalarElement(key string, val any, doc common.MapStr
 		}
 	}
 
-	err := v.parseElementValue(key, *definition, val, doc)
+	err := validateIndexModeDefinition(v.indexMode, *definition)
+	if err != nil {
+		return fmt.Errorf("field %q is not valid for index mode %q: %w", key, v.indexMode, err)
+	}
+
+	err = v.parseElementValue(key, *definition, val, doc)
 	if err != nil {
 		return fmt.Errorf("parsing field value failed: %w", err)
 	}
@@ -1178,6 +1399,9 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 		default:
 			return invalidTypeError()
 		}
+		if err := ensureDateFormatMatches(key, val, definition.DateFormat); err != nil {
+			return err
+		}
 	// IP values should be actual IPs, included in the ranges of IPs available
 	// in the geoip test database.
 	// If a pattern is provided, it checks if the value matches.
@@ -1234,7 +1458,8 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 			return fmt.Errorf("field %q is a group of fields of type %s, it cannot store values", key, definition.Type)
 		}
 	// Numbers should have been parsed as float64, otherwise they are not numbers.
-	case "float", "long", "double":
+	// They can be received as strings if the field is listed in the string number fields.
+	case "float", "long", "double", "scaled_float":
 		switch val := val.(type) {
 		case float64:
 		case json.Number:
@@ -1242,7 +1467,7 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 			if !slices.Contains(v.stringNumberFields, key) {
 				return invalidTypeError()
 			}
-			if _, err := strconv.ParseFloat(val, 64); err != nil {
+			if !isNumericString(val) {
 				return invalidTypeError()
 			}
 		default:
@@ -1256,6 +1481,16 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 	return nil
 }
 
+// isNumericString checks if the string contains a finite number, as accepted by Elasticsearch
+// for numeric fields. Negative numbers and scientific notation are accepted.
+func isNumericString(s string) bool {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return false
+	}
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
 // isAllowedIPValue checks if the provided IP is allowed for testing
 // The set of allowed IPs are:
 // - private IPs as described in RFC 1918 & RFC 4193
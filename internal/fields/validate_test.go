@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/elastic-package/internal/common"
+	filestest "github.com/elastic/elastic-package/internal/files/test"
 	"github.com/elastic/elastic-package/internal/multierror"
 )
 
@@ -147,6 +148,47 @@ func TestValidate_WithStringNumberFields(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_ScaledFloatAsString(t *testing.T) {
+	definition := FieldDefinition{
+		Name: "metric",
+		Type: "scaled_float",
+	}
+	cases := []struct {
+		title              string
+		value              any
+		stringNumberFields []string
+		fail               bool
+	}{
+		{title: "number", value: float64(12.34)},
+		{title: "numeric string", value: "12.34", stringNumberFields: []string{"metric"}},
+		{title: "negative numeric string", value: "-12.34", stringNumberFields: []string{"metric"}},
+		{title: "scientific notation", value: "1.234e1", stringNumberFields: []string{"metric"}},
+		{title: "negative scientific notation", value: "-1.234E-2", stringNumberFields: []string{"metric"}},
+		{title: "array of numeric strings", value: []any{"1", "-2.5"}, stringNumberFields: []string{"metric"}},
+		{title: "numeric string not listed", value: "12.34", fail: true},
+		{title: "not a number", value: "foo", stringNumberFields: []string{"metric"}, fail: true},
+		{title: "NaN", value: "NaN", stringNumberFields: []string{"metric"}, fail: true},
+		{title: "infinity", value: "-Inf", stringNumberFields: []string{"metric"}, fail: true},
+		{title: "boolean", value: true, stringNumberFields: []string{"metric"}, fail: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			v := Validator{
+				Schema:                       []FieldDefinition{definition},
+				disabledDependencyManagement: true,
+				stringNumberFields:           c.stringNumberFields,
+			}
+			err := v.parseElementValue("metric", definition, c.value, common.MapStr{})
+			if c.fail {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidate_WithEnabledImportAllECSSchema(t *testing.T) {
 	finder := packageRootTestFinder{"../../test/packages/other/imported_mappings_tests"}
 
@@ -391,6 +433,135 @@ func TestValidate_ExpectedDatasets(t *testing.T) {
 	}
 }
 
+func TestValidate_ExpectedDatasetsTemplates(t *testing.T) {
+	expectedDatasets := []string{"{{kubernetes.labels.dataset}}", "kubernetes.container_logs"}
+
+	cases := []struct {
+		title          string
+		doc            common.MapStr
+		strict         bool
+		expectedErrors []string
+	}{
+		{
+			title: "rendered template",
+			doc: common.MapStr{
+				"event.dataset": "nginx.access",
+				"kubernetes":    common.MapStr{"labels": common.MapStr{"dataset": "nginx.access"}},
+			},
+		},
+		{
+			title: "missing field skips template",
+			doc: common.MapStr{
+				"event.dataset": "kubernetes.container_logs",
+			},
+		},
+		{
+			title: "missing field in strict mode",
+			doc: common.MapStr{
+				"event.dataset": "kubernetes.container_logs",
+			},
+			strict:         true,
+			expectedErrors: []string{`can't render expected dataset "{{kubernetes.labels.dataset}}": missing fields in document: kubernetes.labels.dataset`},
+		},
+		{
+			title: "missing field in strict mode and different datasets",
+			doc: common.MapStr{
+				"event.dataset":       "kubernetes.container_logs",
+				"data_stream.dataset": "kubernetes.pod_logs",
+			},
+			strict: true,
+			expectedErrors: []string{
+				`can't render expected dataset "{{kubernetes.labels.dataset}}": missing fields in document: kubernetes.labels.dataset`,
+				`field "event.dataset" should have the same value as "data_stream.dataset"`,
+			},
+		},
+		{
+			title: "wrong dataset",
+			doc: common.MapStr{
+				"event.dataset": "nginx.error",
+				"kubernetes":    common.MapStr{"labels": common.MapStr{"dataset": "nginx.access"}},
+			},
+			expectedErrors: []string{`field "event.dataset" should have value`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			validator, err := CreateValidatorForDirectory("testdata",
+				WithSpecVersion("2.0.0"),
+				WithExpectedDatasets(expectedDatasets),
+				WithStrictExpectedDatasets(c.strict),
+				WithDisabledDependencyManagement(),
+			)
+			require.NoError(t, err)
+
+			errs := validator.validateDocumentValues(c.doc)
+			if assert.Len(t, errs, len(c.expectedErrors)) {
+				for i, expectedError := range c.expectedErrors {
+					assert.Contains(t, errs[i].Error(), expectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_DatasetsMatch(t *testing.T) {
+	cases := []struct {
+		title         string
+		doc           common.MapStr
+		asWarning     bool
+		expectedError string
+	}{
+		{
+			title: "same datasets",
+			doc: common.MapStr{
+				"event.dataset":       "apache.status",
+				"data_stream.dataset": "apache.status",
+			},
+		},
+		{
+			title: "only event.dataset",
+			doc: common.MapStr{
+				"event.dataset": "apache.status",
+			},
+		},
+		{
+			title: "different datasets",
+			doc: common.MapStr{
+				"event.dataset":       "apache.access",
+				"data_stream.dataset": "apache.status",
+			},
+			expectedError: `field "event.dataset" should have the same value as "data_stream.dataset" ("apache.status"), it has "apache.access"`,
+		},
+		{
+			title: "different datasets as warning",
+			doc: common.MapStr{
+				"event.dataset":       "apache.access",
+				"data_stream.dataset": "apache.status",
+			},
+			asWarning: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			validator, err := CreateValidatorForDirectory("testdata",
+				WithSpecVersion("2.0.0"),
+				WithDatasetMismatchAsWarning(c.asWarning),
+				WithDisabledDependencyManagement(),
+			)
+			require.NoError(t, err)
+
+			errs := validator.validateDocumentValues(c.doc)
+			if c.expectedError == "" {
+				assert.Empty(t, errs)
+			} else if assert.Len(t, errs, 1) {
+				assert.Equal(t, c.expectedError, errs[0].Error())
+			}
+		})
+	}
+}
+
 func Test_parseElementValue(t *testing.T) {
 	for _, test := range []struct {
 		key         string
@@ -435,6 +606,35 @@ func Test_parseElementValue(t *testing.T) {
 			},
 			fail: true,
 		},
+		{
+			key:   "constant_keyword matches declared value",
+			value: "logs",
+			definition: FieldDefinition{
+				Type:  "constant_keyword",
+				Value: "logs",
+			},
+		},
+		{
+			key:   "constant_keyword does not match declared value",
+			value: "metrics",
+			definition: FieldDefinition{
+				Type:  "constant_keyword",
+				Value: "logs",
+			},
+			fail: true,
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `value "metrics" does not match the declared constant_keyword value "logs"`)
+			},
+		},
+		{
+			key:   "constant_keyword array with a value not matching declared value",
+			value: []any{"logs", "metrics"},
+			definition: FieldDefinition{
+				Type:  "constant_keyword",
+				Value: "logs",
+			},
+			fail: true,
+		},
 		// keyword and constant_keyword (other)
 		{
 			key:   "bad type for keyword",
@@ -478,6 +678,118 @@ func Test_parseElementValue(t *testing.T) {
 			},
 			fail: true,
 		},
+		{
+			key:   "date with custom date format",
+			value: "2020/11/02 18:01:03.123",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "yyyy/MM/dd HH:mm:ss.SSS",
+			},
+		},
+		{
+			key:   "date not matching custom date format",
+			value: "2020-11-02T18:01:03Z",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "yyyy/MM/dd HH:mm:ss.SSS",
+			},
+			fail: true,
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `value 2020-11-02T18:01:03Z does not match the expected date format "yyyy/MM/dd HH:mm:ss.SSS"`)
+			},
+		},
+		{
+			key:   "date matching one of multiple date formats",
+			value: "02/Nov/2020:18:01:03 +0000",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "strict_date_optional_time||dd/MMM/yyyy:HH:mm:ss Z",
+			},
+		},
+		{
+			key:   "date as milliseconds with epoch format",
+			value: float64(1420070400001),
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "strict_date_optional_time||epoch_millis",
+			},
+		},
+		{
+			key:   "date as milliseconds without epoch format",
+			value: float64(1420070400001),
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "strict_date",
+			},
+			fail: true,
+		},
+		{
+			key:   "date not matching exact built-in date format",
+			value: "2020-11-02T18:01:03Z",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "strict_date",
+			},
+			fail: true,
+		},
+		{
+			key:   "date with lenient built-in date format is not validated",
+			value: "2020-11",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "strict_date_optional_time",
+			},
+		},
+		{
+			key:   "date with escaped quote in quoted literal of custom date format",
+			value: "18 o'clock",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "HH 'o''clock'",
+			},
+		},
+		{
+			key:   "date not matching escaped quote in quoted literal of custom date format",
+			value: "18 oclock",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "HH 'o''clock'",
+			},
+			fail: true,
+		},
+		{
+			key:   "date with escaped quote in custom date format",
+			value: "2020-11-02'18",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "yyyy-MM-dd''HH",
+			},
+		},
+		{
+			key:   "date not matching escaped quote in custom date format",
+			value: "2020-11-0218",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "yyyy-MM-dd''HH",
+			},
+			fail: true,
+		},
+		{
+			key:   "date with optional sections in custom date format is not validated",
+			value: "2020/11/02",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "yyyy/MM/dd[ HH:mm:ss]",
+			},
+		},
+		{
+			key:   "date with unsupported date format is not validated",
+			value: "2020-W45",
+			definition: FieldDefinition{
+				Type:       "date",
+				DateFormat: "weekyear_week",
+			},
+		},
 		// ip
 		{
 			key:   "ip",
@@ -1179,6 +1491,101 @@ func readTestResults(t *testing.T, path string) (f results) {
 	return
 }
 
+func TestValidateWithNextECSReference(t *testing.T) {
+	packageRoot := t.TempDir()
+	currentECS := filestest.WriteFile(t, packageRoot, "ecs_current.yml", `
+source:
+  type: group
+  fields:
+    source.domain:
+      name: domain
+      type: keyword
+    source.legacy:
+      name: legacy
+      type: keyword
+    source.port:
+      name: port
+      type: long
+`)
+	nextECS := filestest.WriteFile(t, packageRoot, "ecs_next.yml", `
+source:
+  type: group
+  fields:
+    source.domain:
+      name: domain
+      type: keyword
+    source.port:
+      name: port
+      type: keyword
+`)
+	filestest.WriteFile(t, packageRoot, "manifest.yml", `
+format_version: 3.0.0
+name: test
+version: 1.0.0
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("_dev", "build", "build.yml"), `
+dependencies:
+  ecs:
+    reference: file://`+currentECS+`
+    import_mappings: true
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "logs", "fields", "base-fields.yml"), `
+- name: message
+  type: text
+`)
+
+	validator, err := createValidatorForDirectoryAndPackageRoot(filepath.Join(packageRoot, "data_stream", "logs"),
+		packageRootTestFinder{packageRoot},
+		WithSpecVersion("3.0.0"),
+		WithEnabledImportAllECSSChema(true),
+		WithNextECSReference("file://"+nextECS),
+	)
+	require.NoError(t, err)
+
+	cases := []struct {
+		title    string
+		document string
+		expected []string
+	}{
+		{
+			title:    "valid with both versions",
+			document: `{"message":"hello","source":{"domain":"example.com"}}`,
+		},
+		{
+			title:    "field removed in next version",
+			document: `{"message":"hello","source":{"legacy":"foo"}}`,
+			expected: []string{
+				`validation would fail with ECS file://` + nextECS + `: field "source.legacy" is undefined`,
+			},
+		},
+		{
+			title:    "field type changed in next version",
+			document: `{"message":"hello","source":{"port":42}}`,
+			expected: []string{
+				`validation would fail with ECS file://` + nextECS + `: parsing field value failed: field "source.port"'s Go type, float64, does not match the expected field type: keyword (field value: 42)`,
+			},
+		},
+		{
+			title:    "invalid with both versions",
+			document: `{"message":"hello","source":{"unknown":"foo"}}`,
+			expected: []string{
+				`field "source.unknown" is undefined`,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			errs := validator.ValidateDocumentBody(json.RawMessage(c.document))
+			var messages []string
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, c.expected, messages)
+		})
+	}
+}
+
 func readSampleEvent(t *testing.T, path string) json.RawMessage {
 	c, err := os.ReadFile(path)
 	require.NoError(t, err)
@@ -100,6 +100,63 @@ func Sign(targetFile string, options SignOptions) error {
 		return fmt.Errorf("can't write the signature file: %w", err)
 	}
 
+	logger.Debug("Verify the signature file")
+	err = verifyDetachedSignature(targetFile, armoredSignature, keyRing)
+	if err != nil {
+		return fmt.Errorf("verification of the signature file failed (path: %s): %w", targetSigFile, err)
+	}
+
 	logger.Infof("Signature file written: %s", targetSigFile)
 	return nil
 }
+
+// VerifySignature function verifies the detached signature of the target file using the provided public key.
+// It returns the fingerprint of the key used for the verification.
+func VerifySignature(targetFile, signatureFile, publicKeyFile string) (string, error) {
+	publicKey, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return "", fmt.Errorf("can't read the public keyfile (path: %s): %w", publicKeyFile, err)
+	}
+
+	verificationKey, err := crypto.NewKeyFromArmored(string(publicKey))
+	if err != nil {
+		return "", fmt.Errorf("crypto.NewKeyFromArmored failed: %w", err)
+	}
+
+	keyRing, err := crypto.NewKeyRing(verificationKey)
+	if err != nil {
+		return "", fmt.Errorf("crypto.NewKeyRing failed: %w", err)
+	}
+
+	armoredSignature, err := os.ReadFile(signatureFile)
+	if err != nil {
+		return "", fmt.Errorf("can't read the signature file (path: %s): %w", signatureFile, err)
+	}
+
+	err = verifyDetachedSignature(targetFile, string(armoredSignature), keyRing)
+	if err != nil {
+		return "", err
+	}
+	return verificationKey.GetFingerprint(), nil
+}
+
+// verifyDetachedSignature function checks that the armored signature is valid for the target file
+// using the public part of the given key ring.
+func verifyDetachedSignature(targetFile, armoredSignature string, keyRing *crypto.KeyRing) error {
+	signature, err := crypto.NewPGPSignatureFromArmored(armoredSignature)
+	if err != nil {
+		return fmt.Errorf("crypto.NewPGPSignatureFromArmored failed: %w", err)
+	}
+
+	messageReader, err := os.Open(targetFile)
+	if err != nil {
+		return fmt.Errorf("os.Open failed (targetFile: %s): %w", targetFile, err)
+	}
+	defer messageReader.Close()
+
+	err = keyRing.VerifyDetachedStream(messageReader, signature, crypto.GetUnixTime())
+	if err != nil {
+		return fmt.Errorf("keyRing.VerifyDetachedStream failed: %w", err)
+	}
+	return nil
+}
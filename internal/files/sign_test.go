@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package files
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestSign(t *testing.T) {
+	dir := t.TempDir()
+	passphrase := []byte("test-passphrase")
+
+	key, err := crypto.GenerateKey("Elastic Package Test", "", "x25519", 0)
+	require.NoError(t, err)
+	key, err = key.Lock(passphrase)
+	require.NoError(t, err)
+	armoredKey, err := key.Armor()
+	require.NoError(t, err)
+
+	privateKeyPath := filestest.WriteFile(t, dir, "private.asc", armoredKey)
+	t.Setenv(signerPrivateKeyfileEnv, privateKeyPath)
+	t.Setenv(signerPassphraseEnv, string(passphrase))
+	require.NoError(t, VerifySignerConfiguration())
+
+	targetFile := filestest.WriteFile(t, dir, "package-1.0.0.zip", "package contents")
+
+	err = Sign(targetFile, SignOptions{PackageName: "package", PackageVersion: "1.0.0"})
+	require.NoError(t, err)
+
+	armoredSignature, err := os.ReadFile(targetFile + ".sig")
+	require.NoError(t, err)
+
+	publicKey, err := key.ToPublic()
+	require.NoError(t, err)
+	keyRing, err := crypto.NewKeyRing(publicKey)
+	require.NoError(t, err)
+
+	assert.NoError(t, verifyDetachedSignature(targetFile, string(armoredSignature), keyRing))
+
+	armoredPublicKey, err := key.GetArmoredPublicKey()
+	require.NoError(t, err)
+	publicKeyPath := filestest.WriteFile(t, dir, "public.asc", armoredPublicKey)
+
+	fingerprint, err := VerifySignature(targetFile, targetFile+".sig", publicKeyPath)
+	require.NoError(t, err)
+	assert.Equal(t, key.GetFingerprint(), fingerprint)
+
+	filestest.WriteFile(t, dir, "package-1.0.0.zip", "tampered contents")
+	assert.Error(t, verifyDetachedSignature(targetFile, string(armoredSignature), keyRing))
+
+	_, err = VerifySignature(targetFile, targetFile+".sig", publicKeyPath)
+	assert.Error(t, err)
+}
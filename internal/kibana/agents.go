@@ -33,10 +33,32 @@ type Agent struct {
 		Elastic struct {
 			Agent struct {
 				LogLevel string `json:"log_level"`
+				Version  string `json:"version"`
 			} `json:"agent"`
 		} `json:"elastic"`
 	} `json:"local_metadata"`
-	Status string `json:"status"`
+	Status string   `json:"status"`
+	Tags   []string `json:"tags,omitempty"`
+
+	// Components contains the status of the components run by the agent, as reported to Fleet.
+	Components []AgentComponent `json:"components,omitempty"`
+}
+
+// AgentComponent is a component run by an Elastic Agent, with the status of its units.
+type AgentComponent struct {
+	ID      string               `json:"id"`
+	Type    string               `json:"type"`
+	Status  string               `json:"status"`
+	Message string               `json:"message"`
+	Units   []AgentComponentUnit `json:"units,omitempty"`
+}
+
+// AgentComponentUnit is an input or output unit of a component run by an Elastic Agent.
+type AgentComponentUnit struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
 }
 
 // String method returns string representation of an agent.
@@ -140,7 +162,7 @@ func (c *Client) waitUntilPolicyAssigned(ctx context.Context, a Agent, p Policy)
 	defer ticker.Stop()
 
 	for {
-		agent, err := c.getAgent(ctx, a.ID)
+		agent, err := c.GetAgent(ctx, a.ID)
 		if err != nil {
 			return fmt.Errorf("can't get the agent: %w", err)
 		}
@@ -167,7 +189,8 @@ func (c *Client) waitUntilPolicyAssigned(ctx context.Context, a Agent, p Policy)
 	return nil
 }
 
-func (c *Client) getAgent(ctx context.Context, agentID string) (*Agent, error) {
+// GetAgent obtains information about an agent enrolled with Fleet.
+func (c *Client) GetAgent(ctx context.Context, agentID string) (*Agent, error) {
 	statusCode, respBody, err := c.get(ctx, fmt.Sprintf("%s/agents/%s", FleetAPI, agentID))
 	if err != nil {
 		return nil, fmt.Errorf("could not list agents: %w", err)
@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -143,6 +144,16 @@ func CertificateAuthority(certificateAuthority string) ClientOption {
 	}
 }
 
+// KnownVersion option sets the version of Kibana, so it is not requested when creating the client.
+// Invalid versions are ignored.
+func KnownVersion(version string) ClientOption {
+	return func(c *Client) {
+		number, snapshot := strings.CutSuffix(version, SNAPSHOT_SUFFIX)
+		c.versionInfo = VersionInfo{Number: number, BuildSnapshot: snapshot}
+		c.semver, _ = semver.NewVersion(number)
+	}
+}
+
 // HTTPClientSetup adds an initializing function for the http client.
 func HTTPClientSetup(setup func(*http.Client) *http.Client) ClientOption {
 	return func(c *Client) {
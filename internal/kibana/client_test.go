@@ -73,3 +73,24 @@ func writeCACertFile(t *testing.T, cert *x509.Certificate) string {
 
 	return caCertFile
 }
+
+func TestClientKnownVersion(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Address(server.URL), KnownVersion("8.17.0-SNAPSHOT"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, requests)
+
+	version, err := client.Version()
+	require.NoError(t, err)
+	assert.Equal(t, "8.17.0-SNAPSHOT", version.Version())
+	assert.True(t, version.IsSnapshot())
+
+	_, err = NewClient(Address(server.URL), RetryMax(0), KnownVersion("latest"))
+	assert.Error(t, err)
+}
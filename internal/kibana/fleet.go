@@ -19,6 +19,12 @@ type FleetOutput struct {
 	Hosts []string  `json:"hosts,omitempty"`
 	Type  string    `json:"type,omitempty"`
 	SSL   *AgentSSL `json:"ssl,omitempty"`
+
+	// Settings of Kafka outputs.
+	Topic    string `json:"topic,omitempty"`
+	AuthType string `json:"auth_type,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
 }
 
 type FleetServerHost struct {
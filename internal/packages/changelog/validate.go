@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// EntryTypes contains the valid types of changelog entries.
+var EntryTypes = []string{"bugfix", "enhancement", "breaking-change"}
+
+// Validate checks that the changelog follows the required structure, and that its
+// latest revision matches the given manifest version.
+func Validate(revisions []Revision, manifestVersion string) error {
+	if len(revisions) == 0 {
+		return errors.New("changelog has no entries")
+	}
+
+	var errs []error
+	if revisions[0].Version != manifestVersion {
+		errs = append(errs, fmt.Errorf("latest changelog version %q doesn't match manifest version %q", revisions[0].Version, manifestVersion))
+	}
+
+	var previous *semver.Version
+	for _, revision := range revisions {
+		version, err := semver.StrictNewVersion(revision.Version)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid changelog version %q: %w", revision.Version, err))
+		} else {
+			if previous != nil && !version.LessThan(previous) {
+				errs = append(errs, fmt.Errorf("changelog version %q should be lower than the previous version %q", revision.Version, previous.Original()))
+			}
+			previous = version
+		}
+
+		if len(revision.Changes) == 0 {
+			errs = append(errs, fmt.Errorf("changelog version %q has no changes", revision.Version))
+		}
+		for i, change := range revision.Changes {
+			if change.Description == "" {
+				errs = append(errs, fmt.Errorf("change %d of version %q has no description", i, revision.Version))
+			}
+			if !slices.Contains(EntryTypes, change.Type) {
+				errs = append(errs, fmt.Errorf("change %d of version %q has invalid type %q (expected one of: %v)", i, revision.Version, change.Type, EntryTypes))
+			}
+			if change.Link == "" {
+				errs = append(errs, fmt.Errorf("change %d of version %q has no link", i, revision.Version))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package changelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	change := Entry{
+		Description: "One change",
+		Type:        "enhancement",
+		Link:        "https://github.com/elastic/elastic-package/pull/1",
+	}
+
+	cases := []struct {
+		title           string
+		revisions       []Revision
+		manifestVersion string
+		expectedErrors  []string
+	}{
+		{
+			title: "valid changelog",
+			revisions: []Revision{
+				{Version: "1.1.0", Changes: []Entry{change}},
+				{Version: "1.0.0", Changes: []Entry{change}},
+			},
+			manifestVersion: "1.1.0",
+		},
+		{
+			title:           "empty changelog",
+			manifestVersion: "1.0.0",
+			expectedErrors:  []string{"changelog has no entries"},
+		},
+		{
+			title: "version mismatch",
+			revisions: []Revision{
+				{Version: "1.0.0", Changes: []Entry{change}},
+			},
+			manifestVersion: "1.1.0",
+			expectedErrors:  []string{`latest changelog version "1.0.0" doesn't match manifest version "1.1.0"`},
+		},
+		{
+			title: "unordered versions",
+			revisions: []Revision{
+				{Version: "1.0.0", Changes: []Entry{change}},
+				{Version: "1.1.0", Changes: []Entry{change}},
+			},
+			manifestVersion: "1.0.0",
+			expectedErrors:  []string{`changelog version "1.1.0" should be lower than the previous version "1.0.0"`},
+		},
+		{
+			title: "invalid entries",
+			revisions: []Revision{
+				{Version: "1.0.0", Changes: []Entry{{Type: "feature"}}},
+				{Version: "0.1.0"},
+			},
+			manifestVersion: "1.0.0",
+			expectedErrors: []string{
+				`change 0 of version "1.0.0" has no description`,
+				`change 0 of version "1.0.0" has invalid type "feature" (expected one of: [bugfix enhancement breaking-change])`,
+				`change 0 of version "1.0.0" has no link`,
+				`changelog version "0.1.0" has no changes`,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := Validate(c.revisions, c.manifestVersion)
+			if len(c.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range c.expectedErrors {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
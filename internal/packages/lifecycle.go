@@ -0,0 +1,145 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DataStreamLifecycleFile is the name of the file with the data stream lifecycle (DLM) configuration.
+const DataStreamLifecycleFile = "lifecycle.yml"
+
+var dataRetentionRegexp = regexp.MustCompile(`^[0-9]+(d|h|m|s|ms|micros|nanos)$`)
+
+// FindLifecycleProblems checks the ILM policies and data stream lifecycles (DLM) of the data streams
+// of the package. ILM policies referenced in data stream manifests must be defined in the data stream
+// following the same rules as the package spec: the policy name must start with
+// "<type>-<package>.<data stream>-", and be defined in a JSON file named after the rest of the
+// name. The definitions must be valid.
+func FindLifecycleProblems(packageRoot string) ([]Problem, error) {
+	manifest, err := ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	dataStreamManifestPaths, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*", DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data stream manifests: %w", err)
+	}
+
+	var problems []Problem
+	for _, manifestPath := range dataStreamManifestPaths {
+		dataStreamManifest, err := ReadDataStreamManifest(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading data stream manifest failed: %w", err)
+		}
+		dataStreamPath := filepath.Dir(manifestPath)
+
+		policyPaths, err := filepath.Glob(filepath.Join(dataStreamPath, "elasticsearch", "ilm", "*"))
+		if err != nil {
+			return nil, fmt.Errorf("can't look for ILM policies: %w", err)
+		}
+		for _, policyPath := range policyPaths {
+			ext := filepath.Ext(policyPath)
+			if ext != ".json" && ext != ".yml" {
+				continue
+			}
+			err := validateILMPolicyFile(policyPath)
+			if err != nil {
+				problems = append(problems, Problem{Path: policyPath, Message: err.Error()})
+			}
+		}
+
+		if policy := dataStreamManifest.ILMPolicy; policy != "" {
+			err := validateILMPolicyReference(policy, dataStreamManifest.Type, manifest.Name, dataStreamPath)
+			if err != nil {
+				problems = append(problems, Problem{Path: manifestPath, Message: err.Error()})
+			}
+		}
+
+		lifecyclePath := filepath.Join(dataStreamPath, DataStreamLifecycleFile)
+		err = validateDataStreamLifecycleFile(lifecyclePath)
+		if err != nil {
+			problems = append(problems, Problem{Path: lifecyclePath, Message: err.Error()})
+		}
+	}
+
+	return problems, nil
+}
+
+// validateILMPolicyReference checks that an ILM policy referenced in a data stream manifest is
+// defined in the data stream, as expected by the package spec.
+func validateILMPolicyReference(policy, dataStreamType, packageName, dataStreamPath string) error {
+	prefix := fmt.Sprintf("%s-%s.%s-", dataStreamType, packageName, filepath.Base(dataStreamPath))
+	if !strings.HasPrefix(policy, prefix) {
+		return fmt.Errorf("ILM policy %q must start with %q", policy, prefix)
+	}
+	policyPath := filepath.Join(dataStreamPath, "elasticsearch", "ilm", strings.TrimPrefix(policy, prefix)+".json")
+	_, err := os.Stat(policyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("ILM policy %q not found in the data stream, expected definition in %q", policy, policyPath)
+	}
+	if err != nil {
+		return fmt.Errorf("can't look for ILM policy %q: %w", policy, err)
+	}
+	return nil
+}
+
+func validateILMPolicyFile(path string) error {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading ILM policy failed: %w", err)
+	}
+
+	// JSON is valid YAML, so both formats can be parsed the same way.
+	var definition struct {
+		Policy *struct {
+			Phases map[string]any `yaml:"phases"`
+		} `yaml:"policy"`
+	}
+	err = yaml.Unmarshal(d, &definition)
+	if err != nil {
+		return fmt.Errorf("parsing ILM policy failed: %w", err)
+	}
+	if definition.Policy == nil {
+		return errors.New("ILM policy definition must contain a \"policy\" object")
+	}
+	if len(definition.Policy.Phases) == 0 {
+		return errors.New("ILM policy must define at least one phase")
+	}
+	return nil
+}
+
+func validateDataStreamLifecycleFile(path string) error {
+	d, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading data stream lifecycle failed: %w", err)
+	}
+
+	var lifecycle struct {
+		DataRetention string `yaml:"data_retention"`
+	}
+	err = yaml.Unmarshal(d, &lifecycle)
+	if err != nil {
+		return fmt.Errorf("parsing data stream lifecycle failed: %w", err)
+	}
+	if lifecycle.DataRetention == "" {
+		return errors.New("data stream lifecycle must define data_retention")
+	}
+	if !dataRetentionRegexp.MatchString(lifecycle.DataRetention) {
+		return fmt.Errorf("invalid data_retention %q, expected a time unit like \"7d\"", lifecycle.DataRetention)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestFindLifecycleProblems(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, PackageManifestFile, `
+name: test
+type: integration
+`)
+
+	// Valid data stream, with a policy defined in the data stream.
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "valid", DataStreamManifestFile), `
+title: Valid
+type: logs
+ilm_policy: logs-test.valid-default_policy
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "valid", "elasticsearch", "ilm", "default_policy.json"), `{
+  "policy": {
+    "phases": {
+      "hot": {"actions": {"rollover": {"max_age": "1d"}}}
+    }
+  }
+}`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "valid", DataStreamLifecycleFile), `data_retention: "7d"`)
+
+	// Data stream referencing a policy not defined in the package.
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "builtin", DataStreamManifestFile), `
+title: Builtin
+type: metrics
+ilm_policy: metrics
+`)
+
+	// Data stream with problems.
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "invalid", DataStreamManifestFile), `
+title: Invalid
+type: logs
+dataset: custom
+ilm_policy: logs-test.invalid-empty
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "invalid", "elasticsearch", "ilm", "empty.yml"), `
+policy:
+  phases: {}
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "invalid", DataStreamLifecycleFile), `data_retention: "one week"`)
+
+	problems, err := FindLifecycleProblems(packageRoot)
+	require.NoError(t, err)
+
+	invalidPath := filepath.Join(packageRoot, "data_stream", "invalid")
+	expected := []Problem{
+		{
+			Path:    filepath.Join(packageRoot, "data_stream", "builtin", DataStreamManifestFile),
+			Message: `ILM policy "metrics" must start with "metrics-test.builtin-"`,
+		},
+		{
+			Path:    filepath.Join(invalidPath, "elasticsearch", "ilm", "empty.yml"),
+			Message: "ILM policy must define at least one phase",
+		},
+		{
+			Path:    filepath.Join(invalidPath, DataStreamManifestFile),
+			Message: fmt.Sprintf("ILM policy %q not found in the data stream, expected definition in %q", "logs-test.invalid-empty", filepath.Join(invalidPath, "elasticsearch", "ilm", "empty.json")),
+		},
+		{
+			Path:    filepath.Join(invalidPath, DataStreamLifecycleFile),
+			Message: `invalid data_retention "one week", expected a time unit like "7d"`,
+		},
+	}
+	assert.Equal(t, expected, problems)
+}
@@ -63,11 +63,20 @@ func (vv VarValue) MarshalJSON() ([]byte, error) {
 	return []byte("null"), nil
 }
 
+// IsEmpty returns true if the variable value is not set, or it is an empty string or list.
+func (vv VarValue) IsEmpty() bool {
+	if vv.list != nil {
+		return len(vv.list) == 0
+	}
+	return vv.scalar == nil || vv.scalar == ""
+}
+
 // Variable is an instance of configuration variable (named, typed).
 type Variable struct {
 	Name    string   `config:"name" json:"name" yaml:"name"`
 	Type    string   `config:"type" json:"type" yaml:"type"`
 	Default VarValue `config:"default" json:"default" yaml:"default"`
+	Secret  bool     `config:"secret" json:"secret,omitempty" yaml:"secret,omitempty"`
 }
 
 // Input is a single input configuration.
@@ -179,10 +188,12 @@ type DataStreamManifest struct {
 	Dataset       string         `config:"dataset" json:"dataset" yaml:"dataset"`
 	Hidden        bool           `config:"hidden" json:"hidden" yaml:"hidden"`
 	Release       string         `config:"release" json:"release" yaml:"release"`
+	ILMPolicy     string         `config:"ilm_policy" json:"ilm_policy" yaml:"ilm_policy"`
 	Elasticsearch *Elasticsearch `config:"elasticsearch" json:"elasticsearch" yaml:"elasticsearch"`
 	Streams       []struct {
-		Input string     `config:"input" json:"input" yaml:"input"`
-		Vars  []Variable `config:"vars" json:"vars" yaml:"vars"`
+		Input        string     `config:"input" json:"input" yaml:"input"`
+		TemplatePath string     `config:"template_path" json:"template_path" yaml:"template_path"`
+		Vars         []Variable `config:"vars" json:"vars" yaml:"vars"`
 	} `config:"streams" json:"streams" yaml:"streams"`
 	Agent Agent `config:"agent" json:"agent" yaml:"agent"`
 }
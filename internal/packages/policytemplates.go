@@ -0,0 +1,41 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FindMissingPolicyTemplateDataStreams looks for data streams listed in the policy templates
+// of the package that don't exist under the "data_stream" directory.
+func FindMissingPolicyTemplateDataStreams(packageRoot string) ([]Problem, error) {
+	manifestPath := filepath.Join(packageRoot, PackageManifestFile)
+	manifest, err := ReadPackageManifest(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	var result []Problem
+	for _, policyTemplate := range manifest.PolicyTemplates {
+		for _, dataStream := range policyTemplate.DataStreams {
+			path := filepath.Join(packageRoot, "data_stream", dataStream, DataStreamManifestFile)
+			_, err := os.Stat(path)
+			if errors.Is(err, os.ErrNotExist) {
+				result = append(result, Problem{
+					Path:    manifestPath,
+					Message: fmt.Sprintf("policy template %q references data stream %q, but it doesn't exist", policyTemplate.Name, dataStream),
+				})
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("can't check data stream %q: %w", dataStream, err)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestFindMissingPolicyTemplateDataStreams(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, PackageManifestFile, `
+name: test
+type: integration
+policy_templates:
+  - name: logs
+    data_streams:
+      - access
+      - acess
+  - name: metrics
+    data_streams:
+      - status
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "access", DataStreamManifestFile), "title: Access\ntype: logs\n")
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "status", DataStreamManifestFile), "title: Status\ntype: metrics\n")
+
+	missing, err := FindMissingPolicyTemplateDataStreams(packageRoot)
+	require.NoError(t, err)
+
+	expected := []Problem{
+		{
+			Path:    filepath.Join(packageRoot, PackageManifestFile),
+			Message: `policy template "logs" references data stream "acess", but it doesn't exist`,
+		},
+	}
+	assert.Equal(t, expected, missing)
+}
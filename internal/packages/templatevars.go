@@ -0,0 +1,293 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FindUndeclaredTemplateVariables looks for variables referenced in the agent templates of the
+// package that are not declared in its manifests. Stream templates can use the variables of the
+// package, its policy templates, the input of the stream and the stream itself. Input templates
+// can use the variables of the package, its policy templates and inputs.
+// Undeclared variables are rendered as empty values, what can produce errors at runtime, such as
+// template errors in the HTTPJSON input.
+// Ingest pipelines are not checked, the templates in their processors reference fields of the
+// processed documents, policy variables are only available in agent templates.
+func FindUndeclaredTemplateVariables(packageRoot string) ([]Problem, error) {
+	manifest, err := ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	declared := variableNames(manifest.Vars)
+	inputVars := make(map[string][]string)
+	for _, policyTemplate := range manifest.PolicyTemplates {
+		declared = append(declared, variableNames(policyTemplate.Vars)...)
+		for _, input := range policyTemplate.Inputs {
+			inputVars[input.Type] = append(inputVars[input.Type], variableNames(input.Vars)...)
+		}
+	}
+
+	inputDeclared := slices.Clone(declared)
+	for _, vars := range inputVars {
+		inputDeclared = append(inputDeclared, vars...)
+	}
+	inputTemplates, err := filepath.Glob(filepath.Join(packageRoot, "agent", "input", "*.hbs"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for input templates: %w", err)
+	}
+	result, err := findUndeclaredVariablesInTemplates(inputTemplates, inputDeclared)
+	if err != nil {
+		return nil, err
+	}
+
+	dataStreamManifestPaths, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*", DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data stream manifests: %w", err)
+	}
+	for _, path := range dataStreamManifestPaths {
+		dataStreamManifest, err := ReadDataStreamManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading data stream manifest failed: %w", err)
+		}
+		for _, stream := range dataStreamManifest.Streams {
+			templatePath := stream.TemplatePath
+			if templatePath == "" {
+				templatePath = defaultStreamTemplatePath
+			}
+			streamTemplate := filepath.Join(filepath.Dir(path), "agent", "stream", templatePath)
+			if _, err := os.Stat(streamTemplate); errors.Is(err, os.ErrNotExist) {
+				// Missing templates are reported by the package spec validation.
+				continue
+			}
+
+			streamDeclared := slices.Concat(declared, inputVars[stream.Input], variableNames(stream.Vars))
+			undeclared, err := findUndeclaredVariablesInTemplates([]string{streamTemplate}, streamDeclared)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, undeclared...)
+		}
+	}
+
+	return result, nil
+}
+
+func variableNames(vars []Variable) []string {
+	names := make([]string, len(vars))
+	for i, v := range vars {
+		names[i] = v.Name
+	}
+	return names
+}
+
+func findUndeclaredVariablesInTemplates(paths []string, declared []string) ([]Problem, error) {
+	var result []Problem
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read template (path: %s): %w", path, err)
+		}
+		for _, name := range templateVariables(string(content)) {
+			if !isDeclaredVariable(name, declared) {
+				result = append(result, Problem{Path: path, Message: fmt.Sprintf("variable %q is not declared", name)})
+			}
+		}
+	}
+	return result, nil
+}
+
+// isDeclaredVariable checks if a reference is to a declared variable. References can access
+// attributes of the variable values (e.g. "ssl.certificate_authorities" for the "ssl" variable),
+// and variable names can contain dots, so all the prefixes of the reference are checked.
+func isDeclaredVariable(name string, declared []string) bool {
+	for prefix := name; prefix != ""; {
+		if slices.Contains(declared, prefix) {
+			return true
+		}
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return false
+}
+
+const (
+	defaultStreamTemplatePath = "stream.yml.hbs"
+
+	contextBlock = "#context"
+)
+
+var (
+	templateExpressionRegexp = regexp.MustCompile(`{{{?~?\s*([#/^!]?)([\s\S]*?)\s*~?}?}}`)
+	templateTokenRegexp      = regexp.MustCompile(`"[^"]*"|'[^']*'|\|[^|]*\||[()]|[^\s()]+`)
+	templateBlockParamRegexp = regexp.MustCompile(`^\|\s*([^|]*?)\s*\|$`)
+)
+
+// templateBlock is a block opened in a handlebars template.
+type templateBlock struct {
+	name string
+
+	// guards are the variables checked by an "if" block, that have a value in its contents.
+	guards []string
+}
+
+// templateVariables returns the variables referenced in a handlebars template. References to
+// block params, to the context of "each" and "with" blocks, and to data provided by Fleet, such as
+// "data_stream.dataset", are not included.
+// Variables checked by "if" and "unless" blocks are not included either, as undeclared variables
+// are empty and their blocks are not rendered. For the same reason, references to these variables
+// inside the "if" blocks that check them are not included.
+func templateVariables(template string) []string {
+	var variables []string
+	var blocks []templateBlock
+	var locals []string
+	for _, match := range templateExpressionRegexp.FindAllStringSubmatch(template, -1) {
+		modifier, expression := match[1], match[2]
+		if modifier == "!" {
+			continue
+		}
+		tokens := templateTokenRegexp.FindAllString(expression, -1)
+		if len(tokens) == 0 {
+			continue
+		}
+		if tokens[0] == "else" {
+			if len(blocks) > 0 {
+				// Guards of the block don't have a value in the else branch, but the
+				// guards of "else if" do.
+				top := &blocks[len(blocks)-1]
+				top.guards = nil
+				if len(tokens) > 1 && tokens[1] == "if" {
+					top.guards = guardVariables(tokens[2:])
+				}
+			}
+			continue
+		}
+		if modifier == "/" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+
+		// Variables are the arguments of helpers and blocks, or the only token of the expression.
+		args := tokens
+		if modifier == "#" || modifier == "^" || len(tokens) > 1 {
+			args = tokens[1:]
+		}
+		inContext := slices.ContainsFunc(blocks, func(b templateBlock) bool { return b.name == contextBlock })
+		isGuard := false
+		if modifier == "#" {
+			block := templateBlock{name: tokens[0]}
+			switch {
+			case len(blockParams(args)) > 0:
+				locals = append(locals, blockParams(args)...)
+			case block.name == "each" || block.name == "with":
+				// The context changes inside these blocks, references can be to attributes
+				// of the items, so they are not checked.
+				block.name = contextBlock
+			case block.name == "if":
+				block.guards = guardVariables(args)
+				isGuard = true
+			case block.name == "unless":
+				isGuard = true
+			}
+			blocks = append(blocks, block)
+		}
+		if inContext || isGuard {
+			continue
+		}
+
+		for i, arg := range args {
+			if arg == "(" || arg == ")" || i > 0 && args[i-1] == "(" {
+				// Parentheses of subexpressions, and the helpers they call.
+				continue
+			}
+			name, ok := templateVariableName(arg)
+			if !ok || slices.Contains(locals, strings.SplitN(name, ".", 2)[0]) || slices.Contains(variables, name) {
+				continue
+			}
+			if isGuardedVariable(name, blocks) {
+				continue
+			}
+			variables = append(variables, name)
+		}
+	}
+	return variables
+}
+
+// guardVariables returns the variables checked by the arguments of an "if" block. Only simple
+// conditions on a variable are considered, not the results of helpers.
+func guardVariables(args []string) []string {
+	if len(args) != 1 {
+		return nil
+	}
+	name, ok := templateVariableName(args[0])
+	if !ok {
+		return nil
+	}
+	return []string{name}
+}
+
+// isGuardedVariable checks if a reference is inside an "if" block that checks the same variable.
+func isGuardedVariable(name string, blocks []templateBlock) bool {
+	for _, block := range blocks {
+		for _, guard := range block.guards {
+			if name == guard || strings.HasPrefix(name, guard+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// blockParams returns the names of the block params defined in the arguments of a block,
+// e.g. "tag" and "i" in "{{#each tags as |tag i|}}".
+func blockParams(args []string) []string {
+	for i, arg := range args {
+		if arg != "as" || i+1 >= len(args) {
+			continue
+		}
+		matches := templateBlockParamRegexp.FindStringSubmatch(args[i+1])
+		if len(matches) < 2 {
+			return nil
+		}
+		return strings.Fields(matches[1])
+	}
+	return nil
+}
+
+// templateVariableName returns the name of the variable referenced by a token, if the token is
+// a reference to a variable and not a literal or a reference to other data.
+func templateVariableName(token string) (string, bool) {
+	if i := strings.IndexByte(token, '='); i >= 0 {
+		// Hash argument.
+		token = token[i+1:]
+	}
+	switch {
+	case token == "", token == "as", token == "true", token == "false", token == "null", token == "undefined":
+		return "", false
+	case strings.HasPrefix(token, `"`), strings.HasPrefix(token, "'"), strings.HasPrefix(token, "|"):
+		return "", false
+	case strings.HasPrefix(token, "@"), strings.HasPrefix(token, "../"), strings.HasPrefix(token, "this"), strings.HasPrefix(token, "."):
+		return "", false
+	case strings.HasPrefix(token, "data_stream."), strings.HasPrefix(token, "_"):
+		return "", false
+	}
+	if _, err := strconv.ParseFloat(token, 64); err == nil {
+		return "", false
+	}
+	return strings.TrimSuffix(token, ".length"), true
+}
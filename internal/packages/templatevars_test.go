@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestTemplateVariables(t *testing.T) {
+	cases := []struct {
+		title    string
+		template string
+		expected []string
+	}{
+		{
+			title:    "simple references",
+			template: "url: {{url}}\ninterval: {{{ interval }}}\n",
+			expected: []string{"url", "interval"},
+		},
+		{
+			title:    "blocks and helpers",
+			template: "{{#if enabled}}\nssl: {{to_json ssl}}\n{{else}}\n{{/if}}\n{{#contains \"forwarded\" tags}}\npublish: false\n{{/contains}}\n",
+			expected: []string{"ssl", "tags"},
+		},
+		{
+			title:    "attributes",
+			template: "{{#if processors.length}}\n{{processors}}\n{{/if}}\n{{ssl.certificate_authorities}}\n",
+			expected: []string{"ssl.certificate_authorities"},
+		},
+		{
+			title:    "guarded references",
+			template: "{{#if proxy_url}}\nproxy_url: {{proxy_url}}\n{{else}}\nproxy: {{proxy_url}}\n{{/if}}\n{{#unless ssl}}\nssl: {{ssl.enabled}}\n{{/unless}}\n{{#if tags}}\n{{#if ssl}}{{tags}} {{ssl}}{{/if}}\n{{else if url}}\n{{url}} {{tags}}\n{{/if}}\n",
+			expected: []string{"proxy_url", "ssl.enabled", "tags"},
+		},
+		{
+			title:    "block params",
+			template: "{{#each paths as |path i|}}\n  - {{path}}\n{{/each}}\n",
+			expected: []string{"paths"},
+		},
+		{
+			title:    "each context",
+			template: "{{#each hosts}}\n  - {{this}}\n  - {{name}}\n{{/each}}\n{{period}}\n",
+			expected: []string{"hosts", "period"},
+		},
+		{
+			title:    "fleet data and literals",
+			template: "{{! comment with {{var}} }}\ndataset: {{data_stream.dataset}}\n{{#if (contains 5 \"a\")}}{{/if}}\n",
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, templateVariables(c.template))
+		})
+	}
+}
+
+func TestFindUndeclaredTemplateVariables(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, PackageManifestFile, `
+name: test
+type: integration
+vars:
+  - name: api_key
+    type: password
+policy_templates:
+  - name: test
+    inputs:
+      - type: httpjson
+        vars:
+          - name: proxy_url
+            type: text
+      - type: cel
+        vars:
+          - name: resource_url
+            type: text
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "logs", DataStreamManifestFile), `
+title: Logs
+type: logs
+streams:
+  - input: httpjson
+    template_path: httpjson.yml.hbs
+    vars:
+      - name: interval
+        type: text
+  - input: cel
+    vars:
+      - name: ssl
+        type: yaml
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "logs", "agent", "stream", "httpjson.yml.hbs"), `
+request.url: {{url}}
+request.proxy_url: {{proxy_url}}
+interval: {{interval}}
+auth: {{api_key}}
+{{#if ssl}}
+ssl: {{ssl}}
+{{/if}}
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "logs", "agent", "stream", "stream.yml.hbs"), `
+resource.url: {{resource_url}}
+resource.ssl: {{ssl}}
+interval: {{interval}}
+`)
+
+	undeclared, err := FindUndeclaredTemplateVariables(packageRoot)
+	require.NoError(t, err)
+
+	streamDir := filepath.Join(packageRoot, "data_stream", "logs", "agent", "stream")
+	assert.ElementsMatch(t, []Problem{
+		{Path: filepath.Join(streamDir, "httpjson.yml.hbs"), Message: `variable "url" is not declared`},
+		{Path: filepath.Join(streamDir, "stream.yml.hbs"), Message: `variable "interval" is not declared`},
+	}, undeclared)
+}
@@ -0,0 +1,31 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"fmt"
+)
+
+// FindTransformsWithoutVersion looks for transforms of the package that don't define
+// _meta.fleet_transform_version. Fleet includes this version in the IDs of the installed
+// transforms, so they can't be resolved without it.
+func FindTransformsWithoutVersion(packageRoot string) ([]Problem, error) {
+	transforms, err := ReadTransformsFromPackageRoot(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading transforms failed: %w", err)
+	}
+
+	var result []Problem
+	for _, transform := range transforms {
+		if transform.Definition.Meta.FleetTransformVersion != "" {
+			continue
+		}
+		result = append(result, Problem{
+			Path:    transform.Path,
+			Message: fmt.Sprintf("transform %q doesn't define _meta.fleet_transform_version", transform.Name),
+		})
+	}
+	return result, nil
+}
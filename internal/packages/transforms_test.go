@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestFindTransformsWithoutVersion(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, filepath.Join("elasticsearch", "transform", "latest", "transform.yml"), `
+source:
+  index: "logs-test.events-*"
+_meta:
+  fleet_transform_version: 0.1.0
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("elasticsearch", "transform", "pivot", "transform.yml"), `
+source:
+  index: "logs-test.events-*"
+_meta:
+  managed: true
+`)
+
+	missing, err := FindTransformsWithoutVersion(packageRoot)
+	require.NoError(t, err)
+
+	expected := []Problem{
+		{
+			Path:    filepath.Join(packageRoot, "elasticsearch", "transform", "pivot", "transform.yml"),
+			Message: `transform "pivot" doesn't define _meta.fleet_transform_version`,
+		},
+	}
+	assert.Equal(t, expected, missing)
+}
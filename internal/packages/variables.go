@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// FindSecretVariablesWithDefaults looks for variables marked as secret that have a non-empty
+// default value in the manifests of the package. These defaults would be included in the policies,
+// leaking the secrets.
+func FindSecretVariablesWithDefaults(packageRoot string) ([]Problem, error) {
+	manifestPath := filepath.Join(packageRoot, PackageManifestFile)
+	manifest, err := ReadPackageManifest(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	var result []Problem
+	add := func(path, location string, vars []Variable) {
+		for _, v := range vars {
+			if v.Secret && !v.Default.IsEmpty() {
+				result = append(result, Problem{
+					Path:    path,
+					Message: fmt.Sprintf("secret variable %q (%s) must not have a default value", v.Name, location),
+				})
+			}
+		}
+	}
+
+	add(manifestPath, "package", manifest.Vars)
+	for _, policyTemplate := range manifest.PolicyTemplates {
+		add(manifestPath, fmt.Sprintf("policy template %q", policyTemplate.Name), policyTemplate.Vars)
+		for _, input := range policyTemplate.Inputs {
+			add(manifestPath, fmt.Sprintf("input %q of policy template %q", input.Type, policyTemplate.Name), input.Vars)
+		}
+	}
+
+	dataStreamManifestPaths, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*", DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data stream manifests: %w", err)
+	}
+	for _, path := range dataStreamManifestPaths {
+		dataStreamManifest, err := ReadDataStreamManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading data stream manifest failed: %w", err)
+		}
+		for _, stream := range dataStreamManifest.Streams {
+			add(path, fmt.Sprintf("stream with input %q", stream.Input), stream.Vars)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestFindSecretVariablesWithDefaults(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, PackageManifestFile, `
+name: test
+type: integration
+vars:
+  - name: api_key
+    type: password
+    secret: true
+    default: changeme
+  - name: token
+    type: password
+    secret: true
+  - name: url
+    type: text
+    default: http://localhost
+policy_templates:
+  - name: test
+    vars:
+      - name: password
+        type: password
+        secret: true
+        default: ""
+    inputs:
+      - type: httpjson
+        vars:
+          - name: client_secret
+            type: password
+            secret: true
+            default: [secret]
+`)
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "logs", DataStreamManifestFile), `
+title: Logs
+type: logs
+streams:
+  - input: httpjson
+    vars:
+      - name: passphrase
+        type: password
+        secret: true
+        default: passphrase
+      - name: headers
+        type: yaml
+        default: "X-Header: value"
+`)
+
+	found, err := FindSecretVariablesWithDefaults(packageRoot)
+	require.NoError(t, err)
+
+	manifestPath := filepath.Join(packageRoot, PackageManifestFile)
+	expected := []Problem{
+		{Path: manifestPath, Message: `secret variable "api_key" (package) must not have a default value`},
+		{Path: manifestPath, Message: `secret variable "client_secret" (input "httpjson" of policy template "test") must not have a default value`},
+		{Path: filepath.Join(packageRoot, "data_stream", "logs", DataStreamManifestFile), Message: `secret variable "passphrase" (stream with input "httpjson") must not have a default value`},
+	}
+	assert.Equal(t, expected, found)
+}
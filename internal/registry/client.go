@@ -9,27 +9,47 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/logger"
 )
 
 const (
-	productionURL = "https://epr.elastic.co"
+	ProductionURL = "https://epr.elastic.co"
 )
 
 var (
 	// Production is a pre-configured production client
-	Production = NewClient(productionURL)
+	Production = NewClient(ProductionURL)
 )
 
 // Client is responsible for exporting dashboards from Kibana.
 type Client struct {
 	baseURL string
+	headers map[string]string
 }
 
+// ClientOption is functional option modifying Package Registry client.
+type ClientOption func(*Client)
+
 // NewClient creates a new instance of the client.
-func NewClient(baseURL string) *Client {
-	return &Client{
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
 		baseURL: baseURL,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Headers option sets additional headers to include in all requests to the Package Registry,
+// as the ones needed to authenticate in private mirrors.
+func Headers(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.headers = headers
+	}
 }
 
 func (c *Client) get(resourcePath string) (int, []byte, error) {
@@ -50,6 +70,10 @@ func (c *Client) get(resourcePath string) (int, []byte, error) {
 	if err != nil {
 		return 0, nil, fmt.Errorf("could not create request to Package Registry API resource: %s: %w", resourcePath, err)
 	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	logger.Debugf("Sending request to Package Registry: GET %s (headers: %s)", u.String(), c.redactedHeaders())
 
 	client := http.Client{}
 	resp, err := client.Do(req)
@@ -65,3 +89,14 @@ func (c *Client) get(resourcePath string) (int, []byte, error) {
 
 	return resp.StatusCode, body, nil
 }
+
+// redactedHeaders returns a description of the custom headers that can be logged,
+// without exposing their values.
+func (c *Client) redactedHeaders() string {
+	names := make([]string, 0, len(c.headers))
+	for name := range c.headers {
+		names = append(names, name+": [REDACTED]")
+	}
+	sort.Strings(names)
+	return "[" + strings.Join(names, ", ") + "]"
+}
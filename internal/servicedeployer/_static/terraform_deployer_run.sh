@@ -8,21 +8,53 @@ set -euxo pipefail
 # See more: https://github.com/elastic/package-spec/issues/269
 cp -r /stage/. /workspace
 
+# When TF_REGIONS is set, definitions are applied once per region, each one in its own workspace.
+regions="${TF_REGIONS:-}"
+
+with_region() {
+  local region=$1
+  shift
+  AWS_REGION="${region}" AWS_DEFAULT_REGION="${region}" TF_VAR_REGION="${region}" "$@"
+}
+
 cleanup() {
   r=$?
 
   set -x
-  terraform destroy -auto-approve
+  if [ -z "${regions}" ]; then
+    terraform destroy -auto-approve
+  else
+    # Try to destroy the resources of all regions, even if some of them fail.
+    for region in ${regions}; do
+      terraform workspace select "${region}" || continue
+      with_region "${region}" terraform destroy -auto-approve || r=1
+    done
+  fi
 
   exit $r
 }
 trap cleanup EXIT INT TERM
 
 terraform init
-terraform plan
-terraform apply -auto-approve
 
-terraform output -json > /output/tfOutputValues.json
+if [ -z "${regions}" ]; then
+  terraform plan
+  terraform apply -auto-approve
+
+  terraform output -json > /output/tfOutputValues.json
+else
+  for region in ${regions}; do
+    terraform workspace select -or-create "${region}"
+    with_region "${region}" terraform plan
+    with_region "${region}" terraform apply -auto-approve
+
+    terraform output -json > "/output/tfOutputValues-${region}.json"
+  done
+
+  # Outputs of the first region are also available without prefix.
+  first_region=${regions%% *}
+  cp "/output/tfOutputValues-${first_region}.json" /output/tfOutputValues.json
+fi
 
 touch /tmp/tf-applied # This file is used as indicator (healthcheck) that the service is UP, and so it must be placed as the last statement in the script
 
@@ -94,6 +94,14 @@ func (d *DockerComposeServiceDeployer) SetUp(ctx context.Context, svcInfo Servic
 		return nil, fmt.Errorf("stack network is not ready: %w", err)
 	}
 
+	// Verify the additional networks
+	for _, network := range svcInfo.AdditionalNetworkNames {
+		_, err = docker.InspectNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("additional network %q is not available: %w", network, err)
+		}
+	}
+
 	// Clean service logs
 	if d.runTestsOnly {
 		// service logs folder must no be deleted to avoid breaking log files written
@@ -164,6 +172,13 @@ func (d *DockerComposeServiceDeployer) SetUp(ctx context.Context, svcInfo Servic
 				return nil, fmt.Errorf("can't attach service container to the stack network: %w", err)
 			}
 		}
+
+		for _, network := range svcInfo.AdditionalNetworkNames {
+			err = docker.ConnectToNetworkWithAlias(p.ContainerName(serviceName), network, aliases)
+			if err != nil {
+				return nil, fmt.Errorf("can't attach service container to the network %q: %w", network, err)
+			}
+		}
 	}
 
 	// Build service container name
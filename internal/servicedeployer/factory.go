@@ -36,6 +36,9 @@ type FactoryOptions struct {
 	RunTearDown  bool
 	RunTestsOnly bool
 	RunSetup     bool
+
+	// TerraformRegions is the list of regions where the Terraform service deployer applies its definitions.
+	TerraformRegions []string
 }
 
 // Factory chooses the appropriate service runner for the given data stream, depending
@@ -113,6 +116,7 @@ func Factory(options FactoryOptions) (ServiceDeployer, error) {
 		if _, err := os.Stat(serviceDeployerPath); err == nil {
 			opts := TerraformServiceDeployerOptions{
 				DefinitionsDir: serviceDeployerPath,
+				Regions:        options.TerraformRegions,
 			}
 			return NewTerraformServiceDeployer(opts)
 		}
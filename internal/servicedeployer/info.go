@@ -28,6 +28,10 @@ type ServiceInfo struct {
 	// AgentNetworkName is the network name where the agent is running.
 	AgentNetworkName string
 
+	// AdditionalNetworkNames are user-defined networks the service is connected to,
+	// besides the network where the agent is running.
+	AdditionalNetworkNames []string
+
 	// Ports is a list of ports that the service listens on, as addressable
 	// from the Agent container.
 	Ports []int
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/elastic/go-resource"
@@ -43,18 +45,48 @@ var terraformDeployerDockerfileContent string
 // TerraformServiceDeployer is responsible for deploying infrastructure described with Terraform definitions.
 type TerraformServiceDeployer struct {
 	definitionsDir string
+	regions        []string
 }
 
 type TerraformServiceDeployerOptions struct {
 	DefinitionsDir string
+
+	// Regions is the list of regions where the definitions are applied. Definitions are applied
+	// once if empty.
+	Regions []string
 }
 
+var terraformRegionPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
 // addTerraformOutputs method reads the terraform outputs generated in the json format and
 // adds them to the custom properties of ServiceInfo and can be used in the handlebars template
-// like `{{TF_OUTPUT_queue_url}}` where `queue_url` is the output configured
-func addTerraformOutputs(svcInfo *ServiceInfo) error {
+// like `{{TF_OUTPUT_queue_url}}` where `queue_url` is the output configured.
+// When definitions are applied in multiple regions, the outputs of each region are also added with
+// the region as prefix, like `{{TF_OUTPUT_us_east_1_queue_url}}`.
+func addTerraformOutputs(svcInfo *ServiceInfo, regions []string) error {
 	// Read the `output.json` file where terraform outputs are generated
 	outputFile := filepath.Join(svcInfo.OutputDir, terraformOutputJSONFile)
+	err := addTerraformOutputsFromFile(svcInfo, outputFile, terraformOutputPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, region := range regions {
+		outputFile := filepath.Join(svcInfo.OutputDir, terraformRegionOutputJSONFile(region))
+		prefix := terraformOutputPrefix + strings.ReplaceAll(region, "-", "_") + "_"
+		err := addTerraformOutputsFromFile(svcInfo, outputFile, prefix)
+		if err != nil {
+			return fmt.Errorf("failed to add outputs of region %s: %w", region, err)
+		}
+	}
+	return nil
+}
+
+func terraformRegionOutputJSONFile(region string) string {
+	return strings.TrimSuffix(terraformOutputJSONFile, ".json") + "-" + region + ".json"
+}
+
+func addTerraformOutputsFromFile(svcInfo *ServiceInfo, outputFile string, prefix string) error {
 	content, err := os.ReadFile(outputFile)
 	if err != nil {
 		return fmt.Errorf("failed to read terraform output file: %w", err)
@@ -81,15 +113,21 @@ func addTerraformOutputs(svcInfo *ServiceInfo) error {
 	}
 	// Prefix variables names with TF_OUTPUT_
 	for k, outputs := range terraformOutputs {
-		svcInfo.CustomProperties[terraformOutputPrefix+k] = outputs.Value
+		svcInfo.CustomProperties[prefix+k] = outputs.Value
 	}
 	return nil
 }
 
 // NewTerraformServiceDeployer creates an instance of TerraformServiceDeployer.
 func NewTerraformServiceDeployer(opts TerraformServiceDeployerOptions) (*TerraformServiceDeployer, error) {
+	for _, region := range opts.Regions {
+		if !terraformRegionPattern.MatchString(region) {
+			return nil, fmt.Errorf("invalid region %q, it can only contain lowercase letters, numbers and dashes", region)
+		}
+	}
 	return &TerraformServiceDeployer{
 		definitionsDir: opts.DefinitionsDir,
+		regions:        opts.Regions,
 	}, nil
 }
 
@@ -164,7 +202,7 @@ func (tsd TerraformServiceDeployer) SetUp(ctx context.Context, svcInfo ServiceIn
 
 	svcInfo.Agent.Host.NamePrefix = "docker-fleet-agent"
 
-	err = addTerraformOutputs(&svcInfo)
+	err = addTerraformOutputs(&svcInfo, tsd.regions)
 	if err != nil {
 		return nil, fmt.Errorf("could not handle terraform output: %w", err)
 	}
@@ -16,6 +16,7 @@ const (
 	tfDir       = "TF_DIR"
 	tfOutputDir = "TF_OUTPUT_DIR"
 	tfTestRunID = "TF_VAR_TEST_RUN_ID"
+	tfRegions   = "TF_REGIONS"
 
 	envYmlFile = "env.yml"
 )
@@ -26,6 +27,7 @@ func (tsd TerraformServiceDeployer) buildTerraformExecutorEnvironment(info Servi
 	vars[tfTestRunID] = info.Test.RunID
 	vars[tfDir] = tsd.definitionsDir
 	vars[tfOutputDir] = info.OutputDir
+	vars[tfRegions] = strings.Join(tsd.regions, " ")
 
 	var pairs []string
 	for k, v := range vars {
@@ -6,6 +6,7 @@ package servicedeployer
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -168,7 +169,7 @@ func TestAddTerraformOutputs(t *testing.T) {
 			}
 
 			// Test that the terraform output values are generated correctly
-			err := addTerraformOutputs(&tc.svcInfo)
+			err := addTerraformOutputs(&tc.svcInfo, nil)
 			if tc.expectedError {
 				require.Error(t, err)
 				return
@@ -178,3 +179,51 @@ func TestAddTerraformOutputs(t *testing.T) {
 		})
 	}
 }
+
+func TestAddTerraformOutputsMultipleRegions(t *testing.T) {
+	svcInfo := ServiceInfo{OutputDir: t.TempDir()}
+	outputs := map[string]string{
+		"tfOutputValues.json":           `{"queue_url": {"value": "https://sqs.us-east-1.amazonaws.com/queue"}}`,
+		"tfOutputValues-us-east-1.json": `{"queue_url": {"value": "https://sqs.us-east-1.amazonaws.com/queue"}}`,
+		"tfOutputValues-eu-west-1.json": `{"queue_url": {"value": "https://sqs.eu-west-1.amazonaws.com/queue"}}`,
+	}
+	for name, content := range outputs {
+		require.NoError(t, os.WriteFile(filepath.Join(svcInfo.OutputDir, name), []byte(content), 0644))
+	}
+
+	err := addTerraformOutputs(&svcInfo, []string{"us-east-1", "eu-west-1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"TF_OUTPUT_queue_url":           "https://sqs.us-east-1.amazonaws.com/queue",
+		"TF_OUTPUT_us_east_1_queue_url": "https://sqs.us-east-1.amazonaws.com/queue",
+		"TF_OUTPUT_eu_west_1_queue_url": "https://sqs.eu-west-1.amazonaws.com/queue",
+	}, svcInfo.CustomProperties)
+}
+
+func TestNewTerraformServiceDeployerRegions(t *testing.T) {
+	_, err := NewTerraformServiceDeployer(TerraformServiceDeployerOptions{Regions: []string{"us-east-1", "eu-west-1"}})
+	require.NoError(t, err)
+
+	_, err = NewTerraformServiceDeployer(TerraformServiceDeployerOptions{Regions: []string{"us-east-1; rm -rf /"}})
+	assert.Error(t, err)
+}
+
+func TestFindTerraformDefinitions(t *testing.T) {
+	packageRoot := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(packageRoot, "_dev", "deploy", "tf"),
+		filepath.Join(packageRoot, "data_stream", "cloud", "_dev", "deploy", "tf"),
+		filepath.Join(packageRoot, "data_stream", "local", "_dev", "deploy", "docker"),
+	} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+
+	definitions, err := FindTerraformDefinitions(packageRoot, "_dev/deploy")
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(packageRoot, "_dev", "deploy", "tf"),
+		filepath.Join(packageRoot, "data_stream", "cloud", "_dev", "deploy", "tf"),
+	}
+	assert.Equal(t, expected, definitions)
+}
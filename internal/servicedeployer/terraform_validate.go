@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package servicedeployer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/elastic/elastic-package/internal/files"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+const terraformDeployerName = "tf"
+
+// FindTerraformDefinitions returns the directories containing the definitions of the Terraform
+// service deployer, at the package level and in any of its data streams.
+func FindTerraformDefinitions(packageRootPath, devDeployDir string) ([]string, error) {
+	roots := []string{packageRootPath}
+	dataStreams, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data streams: %w", err)
+	}
+	roots = append(roots, dataStreams...)
+
+	var definitions []string
+	for _, root := range roots {
+		definitionsDir := filepath.Join(root, devDeployDir, terraformDeployerName)
+		info, err := os.Stat(definitionsDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat failed (path: %s): %w", definitionsDir, err)
+		}
+		if info.IsDir() {
+			definitions = append(definitions, definitionsDir)
+		}
+	}
+	return definitions, nil
+}
+
+// ValidateTerraformDefinitions runs "terraform validate" on the given definitions. They are copied
+// to a temporary directory, and initialized without backend, so the package is not modified and
+// no credentials are needed.
+func ValidateTerraformDefinitions(ctx context.Context, definitionsDir string) error {
+	workDir, err := os.MkdirTemp("", "elastic-package-terraform-")
+	if err != nil {
+		return fmt.Errorf("can't create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	err = files.CopyAll(definitionsDir, workDir)
+	if err != nil {
+		return fmt.Errorf("can't copy terraform definitions: %w", err)
+	}
+
+	err = runTerraform(ctx, workDir, "init", "-backend=false", "-input=false", "-no-color")
+	if err != nil {
+		return err
+	}
+	return runTerraform(ctx, workDir, "validate", "-no-color")
+}
+
+func runTerraform(ctx context.Context, workDir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd.Dir = workDir
+	output := new(bytes.Buffer)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	logger.Debugf("run command: %s", cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("terraform %s failed (output=%q): %w", args[0], output.String(), err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+)
+
+// testPolicyNamePrefix is the prefix of the names of the agent policies created by test runners.
+const testPolicyNamePrefix = "ep-test-"
+
+// EnrolledAgent is an agent enrolled in Fleet with one of the policies used by elastic-package.
+type EnrolledAgent struct {
+	kibana.Agent
+
+	PolicyName string
+}
+
+// ListEnrolledAgents returns the agents enrolled in Fleet with the policy of the agents managed by
+// elastic-package, or with one of the policies created for tests.
+func ListEnrolledAgents(ctx context.Context, kibanaClient *kibana.Client) ([]EnrolledAgent, error) {
+	rawPolicies, err := kibanaClient.ListRawPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list agent policies: %w", err)
+	}
+	policyNames := make(map[string]string)
+	for _, raw := range rawPolicies {
+		var policy struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		err := json.Unmarshal(raw, &policy)
+		if err != nil {
+			return nil, fmt.Errorf("could not read agent policy: %w", err)
+		}
+		policyNames[policy.ID] = policy.Name
+	}
+
+	agents, err := kibanaClient.ListAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterEnrolledAgents(agents, policyNames), nil
+}
+
+func filterEnrolledAgents(agents []kibana.Agent, policyNames map[string]string) []EnrolledAgent {
+	var enrolled []EnrolledAgent
+	for _, agent := range agents {
+		policyName := policyNames[agent.PolicyID]
+		if agent.PolicyID != managedAgentPolicyID && !strings.HasPrefix(policyName, testPolicyNamePrefix) {
+			continue
+		}
+		enrolled = append(enrolled, EnrolledAgent{
+			Agent:      agent,
+			PolicyName: policyName,
+		})
+	}
+	sort.Slice(enrolled, func(i, j int) bool {
+		if enrolled[i].PolicyName != enrolled[j].PolicyName {
+			return enrolled[i].PolicyName < enrolled[j].PolicyName
+		}
+		return enrolled[i].LocalMetadata.Host.Name < enrolled[j].LocalMetadata.Host.Name
+	})
+	return enrolled
+}
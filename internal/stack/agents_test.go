@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+)
+
+func TestFilterEnrolledAgents(t *testing.T) {
+	policyNames := map[string]string{
+		managedAgentPolicyID: "Elastic-Agent (elastic-package)",
+		"fleet-server":       "Fleet Server (elastic-package)",
+		"test-policy-id":     "ep-test-system-nginx-access-default-test-default-20240101",
+		"other":              "Custom policy",
+	}
+	agent := func(id, policyID string) kibana.Agent {
+		a := kibana.Agent{ID: id, PolicyID: policyID}
+		a.LocalMetadata.Host.Name = id
+		return a
+	}
+	agents := []kibana.Agent{
+		agent("fleet-server-agent", "fleet-server"),
+		agent("stack-agent", managedAgentPolicyID),
+		agent("test-agent", "test-policy-id"),
+		agent("other-agent", "other"),
+		agent("unknown-agent", "unknown"),
+	}
+
+	expected := []EnrolledAgent{
+		{Agent: agent("stack-agent", managedAgentPolicyID), PolicyName: "Elastic-Agent (elastic-package)"},
+		{Agent: agent("test-agent", "test-policy-id"), PolicyName: "ep-test-system-nginx-access-default-test-default-20240101"},
+	}
+	assert.Equal(t, expected, filterEnrolledAgents(agents, policyNames))
+}
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+)
+
+// testDataStreamPatterns are the patterns of the data streams that can be created by tests.
+var testDataStreamPatterns = []string{"logs-*", "metrics-*", "traces-*", "synthetics-*"}
+
+// testDataStreamNamePattern matches the names of the data streams created by system tests,
+// whose namespace is the identifier of the test run.
+var testDataStreamNamePattern = regexp.MustCompile(`^(logs|metrics|traces|synthetics)-.+-[1-9][0-9]{4}$`)
+
+// StaleDataStreams returns the data streams created by tests that are older than the given age.
+// These data streams are usually left when tests are interrupted before tearing them down.
+func StaleDataStreams(ctx context.Context, client *elasticsearch.Client, age time.Duration) ([]elasticsearch.DataStream, error) {
+	dataStreams, err := client.DataStreams(ctx, testDataStreamPatterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data streams: %w", err)
+	}
+	return filterStaleDataStreams(dataStreams, time.Now().Add(-age)), nil
+}
+
+func filterStaleDataStreams(dataStreams []elasticsearch.DataStream, createdBefore time.Time) []elasticsearch.DataStream {
+	var stale []elasticsearch.DataStream
+	for _, dataStream := range dataStreams {
+		if !testDataStreamNamePattern.MatchString(dataStream.Name) {
+			continue
+		}
+		if dataStream.CreationDate.IsZero() || !dataStream.CreationDate.Before(createdBefore) {
+			continue
+		}
+		stale = append(stale, dataStream)
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Name < stale[j].Name })
+	return stale
+}
+
+// DeleteDataStreams deletes the given data streams.
+func DeleteDataStreams(ctx context.Context, client *elasticsearch.Client, dataStreams []elasticsearch.DataStream) error {
+	for _, dataStream := range dataStreams {
+		err := client.DeleteDataStream(ctx, dataStream.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+)
+
+func TestFilterStaleDataStreams(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	dataStreams := []elasticsearch.DataStream{
+		{Name: "logs-nginx.access-54321", CreationDate: old},
+		{Name: "metrics-nginx.stubstatus-12345", CreationDate: old},
+		{Name: "logs-nginx.error-23456", CreationDate: now},
+		{Name: "logs-nginx.access-default", CreationDate: old},
+		{Name: "logs-nginx.access-ep", CreationDate: old},
+		{Name: "logs-nginx.access-123456", CreationDate: old},
+		{Name: "logs-system.syslog-34567"},
+	}
+
+	expected := []elasticsearch.DataStream{
+		{Name: "logs-nginx.access-54321", CreationDate: old},
+		{Name: "metrics-nginx.stubstatus-12345", CreationDate: old},
+	}
+	assert.Equal(t, expected, filterStaleDataStreams(dataStreams, now.Add(-24*time.Hour)))
+}
@@ -8,12 +8,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/elastic/elastic-package/internal/elasticsearch"
 	"github.com/elastic/elastic-package/internal/kibana"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/profile"
+	"github.com/elastic/elastic-package/internal/registry"
 )
 
 // NewElasticsearchClient creates an Elasticsearch client with the settings provided by the shellinit
@@ -79,6 +81,13 @@ func NewElasticsearchClientFromProfile(profile *profile.Profile, customOptions .
 		elasticsearch.OptionWithUsername(elasticsearchUsername),
 		elasticsearch.OptionWithCertificateAuthority(caCertificate),
 	}
+	recorderSetup, err := clientsRecorder(profile, "elasticsearch")
+	if err != nil {
+		return nil, err
+	}
+	if recorderSetup != nil {
+		options = append(options, elasticsearch.OptionWithTransportSetup(recorderSetup))
+	}
 	options = append(options, customOptions...)
 	return elasticsearch.NewClient(options...)
 }
@@ -146,10 +155,36 @@ func NewKibanaClientFromProfile(profile *profile.Profile, customOptions ...kiban
 		kibana.Username(elasticsearchUsername),
 		kibana.CertificateAuthority(caCertificate),
 	}
+	recorderSetup, err := clientsRecorder(profile, "kibana")
+	if err != nil {
+		return nil, err
+	}
+	if recorderSetup != nil {
+		options = append(options, kibana.HTTPClientSetup(func(client *http.Client) *http.Client {
+			client.Transport = recorderSetup(client.Transport)
+			return client
+		}))
+	}
 	options = append(options, customOptions...)
 	return kibana.NewClient(options...)
 }
 
+// NewRegistryClientFromProfile creates a client for the production Package Registry, including
+// the custom headers configured in the provided profile.
+func NewRegistryClientFromProfile(profile *profile.Profile, customOptions ...registry.ClientOption) (*registry.Client, error) {
+	var headers map[string]string
+	err := profile.Decode(configRegistryHeaders, &headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from profile: %w", configRegistryHeaders, err)
+	}
+
+	options := []registry.ClientOption{
+		registry.Headers(headers),
+	}
+	options = append(options, customOptions...)
+	return registry.NewClient(registry.ProductionURL, options...), nil
+}
+
 // FindCACertificate looks for the CA certificate for the stack in the current profile.
 // If not found, it uses the environment variable provided by shellinit.
 func FindCACertificate(profile *profile.Profile) (string, error) {
@@ -171,6 +206,11 @@ func FindCACertificate(profile *profile.Profile) (string, error) {
 }
 
 func checkClientStackAvailability(profile *profile.Profile) error {
+	if replayingRecordedRequests(profile) {
+		// Requests are not sent to the stack when replaying them.
+		return nil
+	}
+
 	config, err := LoadConfig(profile)
 	if err != nil {
 		return fmt.Errorf("cannot load stack configuration: %w", err)
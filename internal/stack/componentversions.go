@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+const (
+	serverlessBuildFlavor = "serverless"
+
+	// composeFleetServerPolicyID is the ID of the policy of the Fleet Server started by the compose provider.
+	composeFleetServerPolicyID = "fleet-server-policy"
+)
+
+// ComponentVersion is the version of a component of the stack, as reported by its API.
+type ComponentVersion struct {
+	Name    string
+	Version string
+}
+
+// ComponentVersions queries the APIs of the components of the stack to obtain the versions they are
+// running. Components that cannot be queried are reported as unavailable instead of failing, so
+// versions can be obtained also from partially healthy stacks.
+func ComponentVersions(ctx context.Context, profile *profile.Profile) []ComponentVersion {
+	versions := []ComponentVersion{
+		elasticsearchVersion(ctx, profile),
+	}
+
+	kibanaClient, err := NewKibanaClientFromProfile(profile)
+	if err != nil {
+		unavailable := "unavailable: " + err.Error()
+		return append(versions,
+			ComponentVersion{Name: "kibana", Version: unavailable},
+			ComponentVersion{Name: "fleet-server", Version: unavailable},
+			ComponentVersion{Name: "elastic-agent", Version: unavailable},
+		)
+	}
+
+	serverless := false
+	kibanaVersion := ComponentVersion{Name: "kibana", Version: "unknown"}
+	versionInfo, err := kibanaClient.Version()
+	if err == nil {
+		serverless = versionInfo.BuildFlavor == serverlessBuildFlavor
+		if serverless {
+			kibanaVersion.Version = serverlessBuildFlavor
+		} else {
+			kibanaVersion.Version = versionInfo.Version()
+		}
+	}
+	versions = append(versions, kibanaVersion)
+
+	agents, err := kibanaClient.ListAgents(ctx)
+	if err != nil {
+		unavailable := "unavailable: " + err.Error()
+		return append(versions,
+			ComponentVersion{Name: "fleet-server", Version: unavailable},
+			ComponentVersion{Name: "elastic-agent", Version: unavailable},
+		)
+	}
+
+	fleetServerVersion := ComponentVersion{Name: "fleet-server", Version: "not found"}
+	if serverless {
+		// Fleet Server is managed by Elastic Cloud in serverless projects, and it is not enrolled as an agent.
+		fleetServerVersion.Version = "managed (serverless)"
+	}
+	if found := agentVersions(agents, composeFleetServerPolicyID, managedFleetServerPolicyID); len(found) > 0 {
+		fleetServerVersion.Version = strings.Join(found, ", ")
+	}
+	versions = append(versions, fleetServerVersion)
+
+	agentVersion := ComponentVersion{Name: "elastic-agent", Version: "no agents enrolled"}
+	if found := agentVersions(agents, managedAgentPolicyID); len(found) > 0 {
+		agentVersion.Version = strings.Join(found, ", ")
+	}
+	versions = append(versions, agentVersion)
+
+	return versions
+}
+
+func elasticsearchVersion(ctx context.Context, profile *profile.Profile) ComponentVersion {
+	version := ComponentVersion{Name: "elasticsearch"}
+	client, err := NewElasticsearchClientFromProfile(profile)
+	if err != nil {
+		version.Version = "unavailable: " + err.Error()
+		return version
+	}
+
+	info, err := client.Info(ctx)
+	switch {
+	case err != nil:
+		version.Version = "unavailable: " + err.Error()
+	case info.Version.BuildFlavor == serverlessBuildFlavor:
+		version.Version = serverlessBuildFlavor
+	default:
+		version.Version = info.Version.Number
+	}
+	return version
+}
+
+// agentVersions returns the sorted list of distinct versions of the agents enrolled with any of
+// the given policies.
+func agentVersions(agents []kibana.Agent, policyIDs ...string) []string {
+	var versions []string
+	for _, agent := range agents {
+		if !slices.Contains(policyIDs, agent.PolicyID) {
+			continue
+		}
+		version := agent.LocalMetadata.Elastic.Agent.Version
+		if version == "" || slices.Contains(versions, version) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
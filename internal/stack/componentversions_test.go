@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+)
+
+func TestAgentVersions(t *testing.T) {
+	agent := func(policyID, version string) kibana.Agent {
+		a := kibana.Agent{PolicyID: policyID}
+		a.LocalMetadata.Elastic.Agent.Version = version
+		return a
+	}
+	agents := []kibana.Agent{
+		agent(composeFleetServerPolicyID, "8.15.0"),
+		agent(managedAgentPolicyID, "8.15.0"),
+		agent(managedAgentPolicyID, "8.14.3"),
+		agent(managedAgentPolicyID, "8.15.0"),
+		agent(managedAgentPolicyID, ""),
+		agent("other", "8.13.0"),
+	}
+
+	assert.Equal(t, []string{"8.14.3", "8.15.0"}, agentVersions(agents, managedAgentPolicyID))
+	assert.Equal(t, []string{"8.15.0"}, agentVersions(agents, composeFleetServerPolicyID, managedFleetServerPolicyID))
+	assert.Empty(t, agentVersions(agents, "unknown"))
+}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/elastic/elastic-package/internal/compose"
 	"github.com/elastic/elastic-package/internal/docker"
-	"github.com/elastic/elastic-package/internal/install"
 )
 
 type ServiceStatus struct {
@@ -56,14 +55,14 @@ func dockerComposeBuild(ctx context.Context, options Options) error {
 		return fmt.Errorf("could not create docker compose project: %w", err)
 	}
 
-	appConfig, err := install.Configuration(install.OptionWithStackVersion(options.StackVersion))
+	imageRefs, err := stackImageRefs(options.Profile, options.StackVersion)
 	if err != nil {
-		return fmt.Errorf("can't read application configuration: %w", err)
+		return fmt.Errorf("can't select stack images: %w", err)
 	}
 
 	opts := compose.CommandOptions{
 		Env: newEnvBuilder().
-			withEnvs(appConfig.StackImageRefs().AsEnv()).
+			withEnvs(imageRefs.AsEnv()).
 			withEnv(stackVariantAsEnv(options.StackVersion)).
 			withEnvs(options.Profile.ComposeEnvVars()).
 			build(),
@@ -82,14 +81,14 @@ func dockerComposePull(ctx context.Context, options Options) error {
 		return fmt.Errorf("could not create docker compose project: %w", err)
 	}
 
-	appConfig, err := install.Configuration(install.OptionWithStackVersion(options.StackVersion))
+	imageRefs, err := pinnedStackImageRefs(options.Profile, options.StackVersion)
 	if err != nil {
-		return fmt.Errorf("can't read application configuration: %w", err)
+		return fmt.Errorf("can't select stack images: %w", err)
 	}
 
 	opts := compose.CommandOptions{
 		Env: newEnvBuilder().
-			withEnvs(appConfig.StackImageRefs().AsEnv()).
+			withEnvs(imageRefs.AsEnv()).
 			withEnv(stackVariantAsEnv(options.StackVersion)).
 			withEnvs(options.Profile.ComposeEnvVars()).
 			build(),
@@ -113,14 +112,14 @@ func dockerComposeUp(ctx context.Context, options Options) error {
 		args = append(args, "-d")
 	}
 
-	appConfig, err := install.Configuration(install.OptionWithStackVersion(options.StackVersion))
+	imageRefs, err := pinnedStackImageRefs(options.Profile, options.StackVersion)
 	if err != nil {
-		return fmt.Errorf("can't read application configuration: %w", err)
+		return fmt.Errorf("can't select stack images: %w", err)
 	}
 
 	opts := compose.CommandOptions{
 		Env: newEnvBuilder().
-			withEnvs(appConfig.StackImageRefs().AsEnv()).
+			withEnvs(imageRefs.AsEnv()).
 			withEnv(stackVariantAsEnv(options.StackVersion)).
 			withEnvs(options.Profile.ComposeEnvVars()).
 			build(),
@@ -140,14 +139,14 @@ func dockerComposeDown(ctx context.Context, options Options) error {
 		return fmt.Errorf("could not create docker compose project: %w", err)
 	}
 
-	appConfig, err := install.Configuration(install.OptionWithStackVersion(options.StackVersion))
+	imageRefs, err := stackImageRefs(options.Profile, options.StackVersion)
 	if err != nil {
-		return fmt.Errorf("can't read application configuration: %w", err)
+		return fmt.Errorf("can't select stack images: %w", err)
 	}
 
 	downOptions := compose.CommandOptions{
 		Env: newEnvBuilder().
-			withEnvs(appConfig.StackImageRefs().AsEnv()).
+			withEnvs(imageRefs.AsEnv()).
 			withEnv(stackVariantAsEnv(options.StackVersion)).
 			withEnvs(options.Profile.ComposeEnvVars()).
 			build(),
@@ -230,6 +229,7 @@ func newServiceStatus(description *docker.ContainerDescription) (*ServiceStatus,
 }
 
 func getVersionFromDockerImage(dockerImage string) string {
+	dockerImage, _, _ = strings.Cut(dockerImage, "@")
 	_, version, found := strings.Cut(dockerImage, ":")
 	if found {
 		return version
@@ -6,10 +6,12 @@ package stack
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/elastic/elastic-package/internal/logger"
@@ -75,55 +77,74 @@ func dumpStackLogs(ctx context.Context, options DumpOptions) ([]DumpResult, erro
 		}
 	}
 
-	var results []DumpResult
+	var dumpedServices []string
 	for _, serviceName := range services {
 		if len(options.Services) > 0 && !slices.Contains(options.Services, serviceName) {
 			continue
 		}
+		dumpedServices = append(dumpedServices, serviceName)
+	}
 
-		logger.Debugf("Dump stack logs for %s", serviceName)
-
-		content, err := dockerComposeLogsSince(ctx, serviceName, options.Profile, options.Since)
-		if err != nil {
-			return nil, fmt.Errorf("can't fetch service logs (service: %s): %v", serviceName, err)
-		}
-		if options.Output == "" {
-			results = append(results, DumpResult{
-				ServiceName: serviceName,
-				Logs:        content,
-			})
-			continue
-		}
-
-		result := DumpResult{
-			ServiceName: serviceName,
-		}
-
+	if options.Output != "" {
 		logsPath := filepath.Join(options.Output, "logs")
 		err = os.MkdirAll(logsPath, 0755)
 		if err != nil {
 			return nil, fmt.Errorf("can't create output location (path: %s): %w", logsPath, err)
 		}
+	}
+
+	// Logs of each service are dumped in parallel, results keep the order of the services.
+	results := make([]DumpResult, len(dumpedServices))
+	errs := make([]error, len(dumpedServices))
+	var wg sync.WaitGroup
+	for i, serviceName := range dumpedServices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = dumpServiceLogs(ctx, serviceName, options)
+		}()
+	}
+	wg.Wait()
 
-		logPath, err := writeLogFiles(logsPath, serviceName, content)
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func dumpServiceLogs(ctx context.Context, serviceName string, options DumpOptions) (DumpResult, error) {
+	logger.Debugf("Dump stack logs for %s", serviceName)
+
+	result := DumpResult{
+		ServiceName: serviceName,
+	}
+	content, err := dockerComposeLogsSince(ctx, serviceName, options.Profile, options.Since)
+	if err != nil {
+		return result, fmt.Errorf("can't fetch service logs (service: %s): %v", serviceName, err)
+	}
+	if options.Output == "" {
+		result.Logs = content
+		return result, nil
+	}
+
+	logsPath := filepath.Join(options.Output, "logs")
+	logPath, err := writeLogFiles(logsPath, serviceName, content)
+	if err != nil {
+		return result, fmt.Errorf("can't write log files for service %q: %w", serviceName, err)
+	}
+	result.LogsFile = logPath
+
+	switch serviceName {
+	case elasticAgentService, fleetServerService:
+		logPath, err := copyDockerInternalLogs(serviceName, logsPath, options.Profile)
 		if err != nil {
-			return nil, fmt.Errorf("can't write log files for service %q: %w", serviceName, err)
+			return result, fmt.Errorf("can't copy internal logs (service: %s): %w", serviceName, err)
 		}
-		result.LogsFile = logPath
-
-		switch serviceName {
-		case elasticAgentService, fleetServerService:
-			logPath, err := copyDockerInternalLogs(serviceName, logsPath, options.Profile)
-			if err != nil {
-				return nil, fmt.Errorf("can't copy internal logs (service: %s): %w", serviceName, err)
-			}
-			result.InternalLogsDir = logPath
-		}
-
-		results = append(results, result)
+		result.InternalLogsDir = logPath
 	}
 
-	return results, nil
+	return result, nil
 }
 
 func writeLogFiles(logsPath, serviceName string, content []byte) (string, error) {
@@ -74,6 +74,15 @@ func (p *environmentProvider) BootUp(ctx context.Context, options Options) error
 			return fmt.Errorf("failed to create logstash output: %w", err)
 		}
 		config.OutputID = fleetLogstashOutput
+		if KafkaFleetOutputID(options.Profile) != "" {
+			logger.Warn("Logstash is enabled, the Kafka output configured in the profile won't be used")
+		}
+	} else if KafkaFleetOutputID(options.Profile) != "" {
+		err := addKafkaFleetOutput(ctx, options.Profile, p.kibana)
+		if err != nil {
+			return fmt.Errorf("failed to create kafka output: %w", err)
+		}
+		config.OutputID = fleetKafkaOutput
 	} else {
 		internalHost := DockerInternalHost(config.ElasticsearchHost)
 		if internalHost != config.ElasticsearchHost {
@@ -85,15 +94,20 @@ func (p *environmentProvider) BootUp(ctx context.Context, options Options) error
 		}
 	}
 
-	// We need to store the config here to be able to clean up the logstash output if something
+	// We need to store the config here to be able to clean up the data output if something
 	// fails later.
 	err = storeConfig(options.Profile, config)
 	if err != nil {
 		return fmt.Errorf("failed to store config: %w", err)
 	}
 
+	registryClient, err := NewRegistryClientFromProfile(options.Profile)
+	if err != nil {
+		return fmt.Errorf("failed to create package registry client: %w", err)
+	}
+
 	selfMonitor := options.Profile.Config(configSelfMonitorEnabled, "false") == "true"
-	policy, err := createAgentPolicy(ctx, p.kibana, options.StackVersion, config.OutputID, selfMonitor)
+	policy, err := createAgentPolicy(ctx, p.kibana, registryClient, options.StackVersion, config.OutputID, selfMonitor)
 	if err != nil {
 		return fmt.Errorf("failed to create agent policy: %w", err)
 	}
@@ -176,7 +190,12 @@ func (p *environmentProvider) setupFleet(ctx context.Context, config Config, opt
 			return config, fmt.Errorf("failed to add Fleet Server host: %w", err)
 		}
 
-		_, err = createFleetServerPolicy(ctx, p.kibana, options.StackVersion, options.Profile.ProfileName)
+		registryClient, err := NewRegistryClientFromProfile(options.Profile)
+		if err != nil {
+			return config, fmt.Errorf("failed to create package registry client: %w", err)
+		}
+
+		_, err = createFleetServerPolicy(ctx, p.kibana, registryClient, options.StackVersion, options.Profile.ProfileName)
 		if err != nil {
 			return config, fmt.Errorf("failed to create agent policy for Fleet Server: %w", err)
 		}
@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/docker"
+	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+// ImageLockFile is the file in the profile where the digests of the stack images are pinned.
+const ImageLockFile = "images.lock.yml"
+
+// imageLock contains the image references pinned to digests, per stack version.
+type imageLock map[string]install.ImageRefs
+
+func pinImageDigestsEnabled(profile *profile.Profile) bool {
+	return profile.Config(configPinImageDigests, "false") == "true"
+}
+
+func readImageLock(path string) (imageLock, error) {
+	d, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return imageLock{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read image lock file: %w", err)
+	}
+
+	lock := imageLock{}
+	err = yaml.Unmarshal(d, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("can't unmarshal image lock file (path: %s): %w", path, err)
+	}
+	return lock, nil
+}
+
+func writeImageLock(path string, lock imageLock) error {
+	d, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("can't marshal image lock: %w", err)
+	}
+	err = os.WriteFile(path, d, 0644)
+	if err != nil {
+		return fmt.Errorf("can't write image lock file: %w", err)
+	}
+	return nil
+}
+
+// stackImageRefs returns the image references to use for the stack. When the profile is configured to
+// pin image digests, the references pinned in the lock file are used if available. Images are not
+// pinned here, so commands that don't start the stack don't pull images nor modify the lock file.
+func stackImageRefs(profile *profile.Profile, stackVersion string) (install.ImageRefs, error) {
+	appConfig, err := install.Configuration(install.OptionWithStackVersion(stackVersion))
+	if err != nil {
+		return install.ImageRefs{}, fmt.Errorf("can't read application configuration: %w", err)
+	}
+	refs := appConfig.StackImageRefs()
+	if !pinImageDigestsEnabled(profile) {
+		return refs, nil
+	}
+
+	lock, err := readImageLock(profile.Path(ImageLockFile))
+	if err != nil {
+		return install.ImageRefs{}, err
+	}
+	if pinned, found := lock[stackVersion]; found {
+		return pinned, nil
+	}
+	return refs, nil
+}
+
+// pinnedStackImageRefs returns the image references to use when starting or updating the stack.
+// When the profile is configured to pin image digests, the images not pinned yet in the lock file
+// are pulled and pinned to their current digests.
+func pinnedStackImageRefs(profile *profile.Profile, stackVersion string) (install.ImageRefs, error) {
+	refs, err := stackImageRefs(profile, stackVersion)
+	if err != nil {
+		return install.ImageRefs{}, err
+	}
+	if !pinImageDigestsEnabled(profile) {
+		return refs, nil
+	}
+
+	logstashEnabled := profile.Config(configLogstashEnabled, "false") == "true"
+	pinned, err := pinImageRefs(refs, logstashEnabled)
+	if err != nil {
+		return install.ImageRefs{}, err
+	}
+	if pinned == refs {
+		return pinned, nil
+	}
+
+	lockPath := profile.Path(ImageLockFile)
+	lock, err := readImageLock(lockPath)
+	if err != nil {
+		return install.ImageRefs{}, err
+	}
+	lock[stackVersion] = pinned
+	err = writeImageLock(lockPath, lock)
+	if err != nil {
+		return install.ImageRefs{}, err
+	}
+	logger.Infof("Stack images for version %s pinned in %s", stackVersion, lockPath)
+	return pinned, nil
+}
+
+// RefreshImageLock pulls the stack images and pins their current digests in the lock file of the profile.
+func RefreshImageLock(profile *profile.Profile, stackVersion string) error {
+	lockPath := profile.Path(ImageLockFile)
+	lock, err := readImageLock(lockPath)
+	if err != nil {
+		return err
+	}
+	delete(lock, stackVersion)
+	err = writeImageLock(lockPath, lock)
+	if err != nil {
+		return err
+	}
+
+	_, err = pinnedStackImageRefs(profile, stackVersion)
+	return err
+}
+
+// pinImageRefs pins the digests of the image references. The Logstash image is only pinned if
+// Logstash is enabled, to avoid pulling an image that is not going to be used.
+func pinImageRefs(refs install.ImageRefs, logstashEnabled bool) (install.ImageRefs, error) {
+	toPin := []*string{&refs.ElasticAgent, &refs.Elasticsearch, &refs.Kibana}
+	if logstashEnabled {
+		toPin = append(toPin, &refs.Logstash)
+	}
+
+	var err error
+	for _, ref := range toPin {
+		*ref, err = pinImageRef(*ref)
+		if err != nil {
+			return install.ImageRefs{}, err
+		}
+	}
+	return refs, nil
+}
+
+func pinImageRef(ref string) (string, error) {
+	if ref == "" || strings.Contains(ref, "@") {
+		return ref, nil
+	}
+
+	err := docker.Pull(ref)
+	if err != nil {
+		return "", fmt.Errorf("pulling image %s failed: %w", ref, err)
+	}
+	repoDigests, err := docker.ImageRepoDigests(ref)
+	if err != nil {
+		return "", err
+	}
+	digest, err := selectRepoDigest(ref, repoDigests)
+	if err != nil {
+		return "", err
+	}
+	return ref + "@" + digest, nil
+}
+
+// selectRepoDigest returns the digest of the repository of the image reference.
+func selectRepoDigest(ref string, repoDigests []string) (string, error) {
+	repository := imageRepository(ref)
+	for _, repoDigest := range repoDigests {
+		name, digest, found := strings.Cut(repoDigest, "@")
+		if found && name == repository {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("no digest found for image %s", ref)
+}
+
+// imageRepository returns the repository of an image reference, without tag or digest.
+func imageRepository(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	lastSlash := strings.LastIndex(ref, "/")
+	if i := strings.LastIndex(ref, ":"); i > lastSlash {
+		ref = ref[:i]
+	}
+	return ref
+}
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/install"
+)
+
+func TestImageRepository(t *testing.T) {
+	cases := map[string]string{
+		"docker.elastic.co/elasticsearch/elasticsearch:8.15.0":               "docker.elastic.co/elasticsearch/elasticsearch",
+		"docker.elastic.co/elasticsearch/elasticsearch:8.15.0@sha256:abcdef": "docker.elastic.co/elasticsearch/elasticsearch",
+		"localhost:5000/elastic-agent:8.15.0":                                "localhost:5000/elastic-agent",
+		"localhost:5000/elastic-agent":                                       "localhost:5000/elastic-agent",
+		"tianon/true":                                                        "tianon/true",
+	}
+	for ref, expected := range cases {
+		t.Run(ref, func(t *testing.T) {
+			assert.Equal(t, expected, imageRepository(ref))
+		})
+	}
+}
+
+func TestSelectRepoDigest(t *testing.T) {
+	repoDigests := []string{
+		"mirror.example.com/kibana/kibana@sha256:1111",
+		"docker.elastic.co/kibana/kibana@sha256:2222",
+	}
+
+	digest, err := selectRepoDigest("docker.elastic.co/kibana/kibana:8.15.0", repoDigests)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:2222", digest)
+
+	_, err = selectRepoDigest("docker.elastic.co/elasticsearch/elasticsearch:8.15.0", repoDigests)
+	assert.Error(t, err)
+}
+
+func TestImageLockReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ImageLockFile)
+
+	lock, err := readImageLock(path)
+	require.NoError(t, err)
+	assert.Empty(t, lock)
+
+	lock["8.15.0"] = install.ImageRefs{
+		Elasticsearch: "docker.elastic.co/elasticsearch/elasticsearch:8.15.0@sha256:1111",
+		Kibana:        "docker.elastic.co/kibana/kibana:8.15.0@sha256:2222",
+	}
+	require.NoError(t, writeImageLock(path, lock))
+
+	read, err := readImageLock(path)
+	require.NoError(t, err)
+	assert.Equal(t, lock, read)
+}
+
+func TestGetVersionFromPinnedDockerImage(t *testing.T) {
+	assert.Equal(t, "8.15.0", getVersionFromDockerImage("docker.elastic.co/kibana/kibana:8.15.0@sha256:2222"))
+}
+
+func TestPinImageRefsSkipsDisabledLogstash(t *testing.T) {
+	refs := install.ImageRefs{
+		ElasticAgent:  "docker.elastic.co/elastic-agent/elastic-agent:8.15.0@sha256:1111",
+		Elasticsearch: "docker.elastic.co/elasticsearch/elasticsearch:8.15.0@sha256:2222",
+		Kibana:        "docker.elastic.co/kibana/kibana:8.15.0@sha256:3333",
+		Logstash:      "docker.elastic.co/logstash/logstash:8.15.0",
+	}
+
+	pinned, err := pinImageRefs(refs, false)
+	require.NoError(t, err)
+	assert.Equal(t, refs, pinned)
+}
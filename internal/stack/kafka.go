@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+const (
+	configKafkaOutputHosts    = "stack.kafka_output.hosts"
+	configKafkaOutputTopic    = "stack.kafka_output.topic"
+	configKafkaOutputUsername = "stack.kafka_output.username"
+	configKafkaOutputPassword = "stack.kafka_output.password"
+
+	fleetKafkaOutput        = "fleet-kafka-output"
+	kafkaOutputDefaultTopic = "elastic-package"
+)
+
+// kafkaFleetOutput returns the definition of the Kafka output configured in the profile. It
+// returns false if no Kafka output is configured.
+func kafkaFleetOutput(profile *profile.Profile) (kibana.FleetOutput, bool) {
+	var hosts []string
+	for _, host := range strings.Split(profile.Config(configKafkaOutputHosts, ""), ",") {
+		host = strings.TrimSpace(host)
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return kibana.FleetOutput{}, false
+	}
+
+	output := kibana.FleetOutput{
+		ID:       fleetKafkaOutput,
+		Name:     fleetKafkaOutput,
+		Type:     "kafka",
+		Hosts:    hosts,
+		Topic:    profile.Config(configKafkaOutputTopic, kafkaOutputDefaultTopic),
+		AuthType: "none",
+	}
+	if username := profile.Config(configKafkaOutputUsername, ""); username != "" {
+		output.AuthType = "user_pass"
+		output.Username = username
+		output.Password = profile.Config(configKafkaOutputPassword, "")
+	}
+	return output, true
+}
+
+// KafkaFleetOutputID returns the ID of the Kafka output configured in the profile, or an empty
+// string if no Kafka output is configured.
+func KafkaFleetOutputID(profile *profile.Profile) string {
+	if _, found := kafkaFleetOutput(profile); !found {
+		return ""
+	}
+	return fleetKafkaOutput
+}
+
+// addKafkaFleetOutput creates or updates the Kafka output configured in the profile, so it can
+// be used as data output in agent policies.
+func addKafkaFleetOutput(ctx context.Context, profile *profile.Profile, kibanaClient *kibana.Client) error {
+	output, found := kafkaFleetOutput(profile)
+	if !found {
+		return nil
+	}
+
+	err := kibanaClient.AddFleetOutput(ctx, output)
+	if errors.Is(err, kibana.ErrConflict) {
+		// Output already exists, update it in case the settings have changed.
+		update := output
+		update.ID = ""
+		err = kibanaClient.UpdateFleetOutput(ctx, update, output.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add kafka fleet output: %w", err)
+	}
+	return nil
+}
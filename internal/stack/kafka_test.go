@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+func TestKafkaFleetOutput(t *testing.T) {
+	cases := []struct {
+		title     string
+		overrides map[string]string
+		expected  kibana.FleetOutput
+		found     bool
+	}{
+		{
+			title: "not configured",
+		},
+		{
+			title: "default topic",
+			overrides: map[string]string{
+				configKafkaOutputHosts: "kafka-1:9092, kafka-2:9092",
+			},
+			expected: kibana.FleetOutput{
+				ID:       fleetKafkaOutput,
+				Name:     fleetKafkaOutput,
+				Type:     "kafka",
+				Hosts:    []string{"kafka-1:9092", "kafka-2:9092"},
+				Topic:    kafkaOutputDefaultTopic,
+				AuthType: "none",
+			},
+			found: true,
+		},
+		{
+			title: "with credentials",
+			overrides: map[string]string{
+				configKafkaOutputHosts:    "kafka:9092",
+				configKafkaOutputTopic:    "logs",
+				configKafkaOutputUsername: "elastic",
+				configKafkaOutputPassword: "changeme",
+			},
+			expected: kibana.FleetOutput{
+				ID:       fleetKafkaOutput,
+				Name:     fleetKafkaOutput,
+				Type:     "kafka",
+				Hosts:    []string{"kafka:9092"},
+				Topic:    "logs",
+				AuthType: "user_pass",
+				Username: "elastic",
+				Password: "changeme",
+			},
+			found: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			var p profile.Profile
+			p.RuntimeOverrides(c.overrides)
+
+			output, found := kafkaFleetOutput(&p)
+			assert.Equal(t, c.found, found)
+			assert.Equal(t, c.expected, output)
+		})
+	}
+}
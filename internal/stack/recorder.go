@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/dnaeon/go-vcr.v3/cassette"
+	"gopkg.in/dnaeon/go-vcr.v3/recorder"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+const (
+	recorderModeConfigName = "stack.recorder.mode"
+	recorderDirConfigName  = "stack.recorder.dir"
+
+	recorderModeRecord = "record"
+	recorderModeReplay = "replay"
+
+	defaultRecorderDir = "cassettes"
+)
+
+// sensitiveRecordedHeaders are headers removed from the recorded requests.
+var sensitiveRecordedHeaders = []string{"Authorization"}
+
+var (
+	recordingTransportsMutex sync.Mutex
+	recordingTransports      = make(map[string]*recordingTransport)
+)
+
+// recordingTransport records or replays the HTTP interactions of the clients, using the same
+// cassette for all the clients of a service created in the same process.
+type recordingTransport struct {
+	recorder *recorder.Recorder
+	record   bool
+}
+
+// realTransportContextKey is the key of the context value with the real transport of the client
+// that sends a request.
+type realTransportContextKey struct{}
+
+// clientRecordingTransport is the transport of a client that uses a shared recorder. Requests
+// sent to the service use the real transport of the client, so clients with different settings,
+// like TLS options, can share the same cassette.
+type clientRecordingTransport struct {
+	shared        *recordingTransport
+	realTransport http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *clientRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := context.WithValue(req.Context(), realTransportContextKey{}, t.realTransport)
+	return t.shared.recorder.RoundTrip(req.WithContext(ctx))
+}
+
+// contextRealTransport sends the requests with the real transport of the client that sent them.
+type contextRealTransport struct{}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (contextRealTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport, ok := req.Context().Value(realTransportContextKey{}).(http.RoundTripper)
+	if !ok || transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req)
+}
+
+// clientsRecorder returns a function that wraps the transport of the clients of the given service
+// with a recorder, if it is enabled in the profile. In record mode the interactions with the
+// service are stored in a cassette, and in replay mode they are replayed from it, without
+// sending any request to the service.
+func clientsRecorder(profile *profile.Profile, service string) (func(http.RoundTripper) http.RoundTripper, error) {
+	if profile == nil {
+		return nil, nil
+	}
+
+	var mode recorder.Mode
+	switch configured := profile.Config(recorderModeConfigName, ""); configured {
+	case "":
+		return nil, nil
+	case recorderModeRecord:
+		mode = recorder.ModeRecordOnly
+	case recorderModeReplay:
+		mode = recorder.ModeReplayOnly
+	default:
+		return nil, fmt.Errorf("unknown value for %s: %q, expected %q or %q", recorderModeConfigName, configured, recorderModeRecord, recorderModeReplay)
+	}
+
+	dir := profile.Config(recorderDirConfigName, profile.Path(defaultRecorderDir))
+	transport, err := newRecordingTransport(filepath.Join(dir, service), mode)
+	if err != nil {
+		return nil, err
+	}
+	return func(realTransport http.RoundTripper) http.RoundTripper {
+		return &clientRecordingTransport{
+			shared:        transport,
+			realTransport: realTransport,
+		}
+	}, nil
+}
+
+// SaveRecordedRequests saves the cassettes of the requests recorded in this process. It is
+// called once before exiting, as saving a cassette writes all its interactions.
+func SaveRecordedRequests() error {
+	recordingTransportsMutex.Lock()
+	defer recordingTransportsMutex.Unlock()
+
+	var errs []error
+	for name, transport := range recordingTransports {
+		if !transport.record {
+			continue
+		}
+		err := transport.recorder.Stop()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to save recorded requests (cassette: %s): %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// replayingRecordedRequests returns true if the profile is configured to replay recorded requests.
+func replayingRecordedRequests(profile *profile.Profile) bool {
+	return profile != nil && profile.Config(recorderModeConfigName, "") == recorderModeReplay
+}
+
+func newRecordingTransport(cassetteName string, mode recorder.Mode) (*recordingTransport, error) {
+	recordingTransportsMutex.Lock()
+	defer recordingTransportsMutex.Unlock()
+
+	if transport, found := recordingTransports[cassetteName]; found {
+		return transport, nil
+	}
+
+	rec, err := recorder.NewWithOptions(&recorder.Options{
+		CassetteName:       cassetteName,
+		Mode:               mode,
+		RealTransport:      contextRealTransport{},
+		SkipRequestLatency: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP recorder (cassette: %s): %w", cassetteName, err)
+	}
+	rec.AddHook(removeSensitiveHeaders, recorder.AfterCaptureHook)
+
+	logger.Debugf("Using HTTP recorder (mode: %s, cassette: %s)", profileRecorderMode(mode), cassetteName)
+	transport := &recordingTransport{
+		recorder: rec,
+		record:   mode == recorder.ModeRecordOnly,
+	}
+	recordingTransports[cassetteName] = transport
+	return transport, nil
+}
+
+func profileRecorderMode(mode recorder.Mode) string {
+	if mode == recorder.ModeRecordOnly {
+		return recorderModeRecord
+	}
+	return recorderModeReplay
+}
+
+func removeSensitiveHeaders(i *cassette.Interaction) error {
+	for _, header := range sensitiveRecordedHeaders {
+		delete(i.Request.Headers, header)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+func TestClientsRecorder(t *testing.T) {
+	cassettesDir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "recorded response")
+	}))
+
+	doRequest := func(t *testing.T, mode string) string {
+		t.Cleanup(func() {
+			recordingTransports = make(map[string]*recordingTransport)
+		})
+
+		var p profile.Profile
+		p.RuntimeOverrides(map[string]string{
+			recorderModeConfigName: mode,
+			recorderDirConfigName:  cassettesDir,
+		})
+		setup, err := clientsRecorder(&p, "test")
+		require.NoError(t, err)
+		require.NotNil(t, setup)
+
+		client := http.Client{Transport: setup(http.DefaultTransport)}
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "ApiKey secret")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("record", func(t *testing.T) {
+		assert.Equal(t, "recorded response", doRequest(t, recorderModeRecord))
+		require.NoError(t, SaveRecordedRequests())
+
+		cassette, err := os.ReadFile(filepath.Join(cassettesDir, "test.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(cassette), "recorded response")
+		assert.NotContains(t, string(cassette), "secret")
+	})
+
+	server.Close()
+
+	t.Run("replay", func(t *testing.T) {
+		assert.Equal(t, "recorded response", doRequest(t, recorderModeReplay))
+	})
+}
+
+func TestClientsRecorderDisabled(t *testing.T) {
+	var p profile.Profile
+	setup, err := clientsRecorder(&p, "test")
+	require.NoError(t, err)
+	assert.Nil(t, setup)
+}
+
+func TestClientsRecorderUnknownMode(t *testing.T) {
+	var p profile.Profile
+	p.RuntimeOverrides(map[string]string{
+		recorderModeConfigName: "rewind",
+	})
+	_, err := clientsRecorder(&p, "test")
+	assert.Error(t, err)
+}
+
+func TestClientsRecorderRealTransports(t *testing.T) {
+	t.Cleanup(func() {
+		recordingTransports = make(map[string]*recordingTransport)
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	var p profile.Profile
+	p.RuntimeOverrides(map[string]string{
+		recorderModeConfigName: recorderModeRecord,
+		recorderDirConfigName:  t.TempDir(),
+	})
+	setup, err := clientsRecorder(&p, "test")
+	require.NoError(t, err)
+
+	// Each client must send its requests with its own real transport.
+	var firstUsed, secondUsed bool
+	first := http.Client{Transport: setup(trackingTransport(&firstUsed))}
+	second := http.Client{Transport: setup(trackingTransport(&secondUsed))}
+
+	resp, err := first.Get(server.URL + "/first")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, firstUsed)
+	assert.False(t, secondUsed)
+
+	resp, err = second.Get(server.URL + "/second")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, secondUsed)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func trackingTransport(used *bool) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		*used = true
+		return http.DefaultTransport.RoundTrip(req)
+	})
+}
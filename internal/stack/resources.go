@@ -62,7 +62,10 @@ const (
 	configKibanaHTTP2Enabled = "stack.kibana_http2_enabled"
 	configLogsDBEnabled      = "stack.logsdb_enabled"
 	configLogstashEnabled    = "stack.logstash_enabled"
+	configPinImageDigests    = "stack.pin_image_digests"
 	configSelfMonitorEnabled = "stack.self_monitor_enabled"
+
+	configRegistryHeaders = "registry.headers"
 )
 
 var (
@@ -145,6 +148,8 @@ func applyResources(profile *profile.Profile, stackVersion string) error {
 		return fmt.Errorf("failed to unmarshal stack.agent.ports: %w", err)
 	}
 
+	kafkaOutput, _ := kafkaFleetOutput(profile)
+
 	resourceManager := resource.NewManager()
 	resourceManager.AddFacter(resource.StaticFacter{
 		"registry_base_image":   PackageRegistryBaseImage,
@@ -168,6 +173,12 @@ func applyResources(profile *profile.Profile, stackVersion string) error {
 		"logsdb_enabled":       profile.Config(configLogsDBEnabled, "false"),
 		"logstash_enabled":     profile.Config(configLogstashEnabled, "false"),
 		"self_monitor_enabled": profile.Config(configSelfMonitorEnabled, "false"),
+
+		"kafka_output_hosts":     strings.Join(kafkaOutput.Hosts, ", "),
+		"kafka_output_topic":     kafkaOutput.Topic,
+		"kafka_output_auth_type": kafkaOutput.AuthType,
+		"kafka_output_username":  kafkaOutput.Username,
+		"kafka_output_password":  kafkaOutput.Password,
 	})
 
 	if err := os.MkdirAll(stackDir, 0755); err != nil {
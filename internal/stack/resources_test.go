@@ -69,6 +69,55 @@ func TestApplyResourcesWithCustomGeoipDir(t *testing.T) {
 	assert.Contains(t, volumes, expectedVolume)
 }
 
+func TestApplyResourcesWithKafkaOutput(t *testing.T) {
+	const profileName = "kafka_output"
+
+	elasticPackagePath := t.TempDir()
+	profilesPath := filepath.Join(elasticPackagePath, "profiles")
+
+	t.Setenv("ELASTIC_PACKAGE_DATA_HOME", elasticPackagePath)
+
+	err := profile.CreateProfile(profile.Options{
+		ProfilesDirPath: profilesPath,
+		Name:            profileName,
+	})
+	require.NoError(t, err)
+
+	configPath := filepath.Join(profilesPath, profileName, profile.PackageProfileConfigFile)
+	config := "stack.kafka_output.hosts: kafka-1:9092,kafka-2:9092\n" +
+		"stack.kafka_output.username: elastic\n" +
+		"stack.kafka_output.password: \"p4ss: word\"\n"
+	err = os.WriteFile(configPath, []byte(config), 0644)
+	require.NoError(t, err)
+
+	p, err := profile.LoadProfile(profileName)
+	require.NoError(t, err)
+
+	err = applyResources(p, "8.15.0")
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(p.Path(ProfileStackPath, KibanaConfigFile))
+	require.NoError(t, err)
+
+	var kibanaConfig struct {
+		Outputs []map[string]any `yaml:"xpack.fleet.outputs"`
+	}
+	err = yaml.Unmarshal(d, &kibanaConfig)
+	require.NoError(t, err)
+
+	require.Len(t, kibanaConfig.Outputs, 2)
+	assert.Equal(t, map[string]any{
+		"id":        fleetKafkaOutput,
+		"name":      fleetKafkaOutput,
+		"type":      "kafka",
+		"hosts":     []any{"kafka-1:9092", "kafka-2:9092"},
+		"topic":     kafkaOutputDefaultTopic,
+		"auth_type": "user_pass",
+		"username":  "elastic",
+		"password":  "p4ss: word",
+	}, kibanaConfig.Outputs[1])
+}
+
 func TestSemverLessThan(t *testing.T) {
 	b, err := semverLessThan("8.9.0", "8.10.0-SNAPSHOT")
 	require.NoError(t, err)
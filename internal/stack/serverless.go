@@ -127,6 +127,12 @@ func (sp *serverlessProvider) createProject(ctx context.Context, settings projec
 		if err != nil {
 			return Config{}, err
 		}
+	} else {
+		// The Kafka output is removed with the project.
+		err = addKafkaFleetOutput(ctx, sp.profile, sp.kibanaClient)
+		if err != nil {
+			return Config{}, err
+		}
 	}
 
 	return config, nil
@@ -272,10 +278,17 @@ func (sp *serverlessProvider) BootUp(ctx context.Context, options Options) error
 		outputID := ""
 		if settings.LogstashEnabled {
 			outputID = serverless.FleetLogstashOutput
+		} else {
+			outputID = KafkaFleetOutputID(sp.profile)
+		}
+
+		registryClient, err := NewRegistryClientFromProfile(options.Profile)
+		if err != nil {
+			return fmt.Errorf("failed to create package registry client: %w", err)
 		}
 
 		logger.Infof("Creating agent policy")
-		_, err = createAgentPolicy(ctx, sp.kibanaClient, options.StackVersion, outputID, settings.SelfMonitor)
+		_, err = createAgentPolicy(ctx, sp.kibanaClient, registryClient, options.StackVersion, outputID, settings.SelfMonitor)
 
 		if err != nil {
 			return fmt.Errorf("failed to create agent policy: %w", err)
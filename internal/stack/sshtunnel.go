@@ -0,0 +1,123 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/profile"
+	"github.com/elastic/elastic-package/internal/wait"
+)
+
+const (
+	configSSHTunnelHost    = "stack.ssh_tunnel.host"
+	configSSHTunnelPort    = "stack.ssh_tunnel.port"
+	configSSHTunnelUser    = "stack.ssh_tunnel.user"
+	configSSHTunnelKeyFile = "stack.ssh_tunnel.key_file"
+	configSSHTunnelPorts   = "stack.ssh_tunnel.ports"
+
+	sshTunnelReadyTimeout = 30 * time.Second
+)
+
+// defaultSSHTunnelPorts are the ports of Elasticsearch, Kibana and Fleet Server.
+var defaultSSHTunnelPorts = []int{9200, 5601, 8220}
+
+// SSHTunnel is a tunnel forwarding local ports to a remote stack.
+type SSHTunnel struct {
+	cmd *exec.Cmd
+}
+
+// StartSSHTunnel forwards the ports of the stack to a remote host over SSH, if it is configured
+// in the profile. It returns a nil tunnel if no tunnel is configured. The ssh binary needs to be
+// available in the PATH.
+func StartSSHTunnel(ctx context.Context, profile *profile.Profile) (*SSHTunnel, error) {
+	host := profile.Config(configSSHTunnelHost, "")
+	if host == "" {
+		return nil, nil
+	}
+
+	var ports []int
+	err := profile.Decode(configSSHTunnelPorts, &ports)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from profile: %w", configSSHTunnelPorts, err)
+	}
+	if len(ports) == 0 {
+		ports = defaultSSHTunnelPorts
+	}
+
+	args := []string{"-N", "-o", "ExitOnForwardFailure=yes", "-o", "BatchMode=yes"}
+	if port := profile.Config(configSSHTunnelPort, ""); port != "" {
+		args = append(args, "-p", port)
+	}
+	if keyFile := profile.Config(configSSHTunnelKeyFile, ""); keyFile != "" {
+		args = append(args, "-i", keyFile)
+	}
+	for _, port := range ports {
+		args = append(args, "-L", fmt.Sprintf("127.0.0.1:%d:127.0.0.1:%d", port, port))
+	}
+	if user := profile.Config(configSSHTunnelUser, ""); user != "" {
+		host = user + "@" + host
+	}
+	args = append(args, host)
+
+	cmd := exec.Command("ssh", args...)
+	logger.Debugf("run command: %s", cmd)
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start SSH tunnel: %w", err)
+	}
+	tunnel := &SSHTunnel{cmd: cmd}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	ready, err := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
+		select {
+		case err := <-exited:
+			return false, fmt.Errorf("SSH tunnel exited: %w", err)
+		default:
+		}
+		for _, port := range ports {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), time.Second)
+			if err != nil {
+				return false, nil
+			}
+			conn.Close()
+		}
+		return true, nil
+	}, 500*time.Millisecond, sshTunnelReadyTimeout)
+	if err == nil && !ready {
+		err = errors.New("timeout waiting for forwarded ports")
+	}
+	if err != nil {
+		tunnel.Close()
+		return nil, fmt.Errorf("SSH tunnel to %s not ready: %w", host, err)
+	}
+
+	logger.Debugf("SSH tunnel to %s ready (ports: %v)", host, ports)
+	return tunnel, nil
+}
+
+// Close tears down the tunnel.
+func (t *SSHTunnel) Close() error {
+	if t == nil || t.cmd.Process == nil {
+		return nil
+	}
+	err := t.cmd.Process.Kill()
+	if err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to stop SSH tunnel: %w", err)
+	}
+	return nil
+}
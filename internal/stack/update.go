@@ -11,7 +11,8 @@ import (
 	"github.com/elastic/elastic-package/internal/docker"
 )
 
-// Update pulls down the most recent versions of the Docker images.
+// Update pulls down the most recent versions of the Docker images. If image digests are pinned
+// in the profile, they are refreshed too.
 func Update(ctx context.Context, options Options) error {
 	err := applyResources(options.Profile, options.StackVersion)
 	if err != nil {
@@ -23,6 +24,13 @@ func Update(ctx context.Context, options Options) error {
 		return fmt.Errorf("pulling package-registry docker image failed: %w", err)
 	}
 
+	if pinImageDigestsEnabled(options.Profile) {
+		err = RefreshImageLock(options.Profile, options.StackVersion)
+		if err != nil {
+			return fmt.Errorf("refreshing pinned image digests failed: %w", err)
+		}
+	}
+
 	err = dockerComposePull(ctx, options)
 	if err != nil {
 		return fmt.Errorf("pulling docker images failed: %w", err)
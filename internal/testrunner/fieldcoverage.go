@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+// FieldCoverageReport contains the coverage of the fields defined in the data streams of a package
+// by the documents used in its tests.
+type FieldCoverageReport struct {
+	PackageName string
+	DataStreams []DataStreamFieldCoverage
+}
+
+// DataStreamFieldCoverage is the coverage of the fields of a data stream.
+type DataStreamFieldCoverage struct {
+	Name string
+	fields.FieldsCoverage
+}
+
+// Defined returns the number of fields defined in all the data streams.
+func (r *FieldCoverageReport) Defined() int {
+	var defined int
+	for _, ds := range r.DataStreams {
+		defined += len(ds.Defined)
+	}
+	return defined
+}
+
+// Covered returns the number of fields covered in all the data streams.
+func (r *FieldCoverageReport) Covered() int {
+	var covered int
+	for _, ds := range r.DataStreams {
+		covered += len(ds.Covered)
+	}
+	return covered
+}
+
+// Percentage returns the percentage of fields covered in all the data streams.
+func (r *FieldCoverageReport) Percentage() float64 {
+	if r.Defined() == 0 {
+		return 100
+	}
+	return float64(r.Covered()) * 100 / float64(r.Defined())
+}
+
+// GenerateFieldCoverageReport calculates the fields of each data stream of the package that are
+// exercised by the test documents. Test documents are the sample events and the expected results
+// of pipeline tests.
+func GenerateFieldCoverageReport(packageName, packageRootPath string) (*FieldCoverageReport, error) {
+	dataStreamDir := filepath.Join(packageRootPath, "data_stream")
+	dataStreams, err := os.ReadDir(dataStreamDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &FieldCoverageReport{PackageName: packageName}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't list data streams directory: %w", err)
+	}
+
+	report := FieldCoverageReport{PackageName: packageName}
+	for _, dataStream := range dataStreams {
+		if !dataStream.IsDir() {
+			continue
+		}
+		dataStreamPath := filepath.Join(dataStreamDir, dataStream.Name())
+		validator, err := fields.CreateValidatorForDirectory(dataStreamPath, fields.WithDisabledDependencyManagement())
+		if err != nil {
+			return nil, fmt.Errorf("can't load fields of data stream %s: %w", dataStream.Name(), err)
+		}
+		docs, err := readTestDocuments(dataStreamPath)
+		if err != nil {
+			return nil, fmt.Errorf("can't read test documents of data stream %s: %w", dataStream.Name(), err)
+		}
+		report.DataStreams = append(report.DataStreams, DataStreamFieldCoverage{
+			Name:           dataStream.Name(),
+			FieldsCoverage: validator.FieldsCoverage(docs),
+		})
+	}
+	return &report, nil
+}
+
+// readTestDocuments reads the sample event and the expected results of the pipeline tests of a data stream.
+func readTestDocuments(dataStreamPath string) ([]common.MapStr, error) {
+	var docs []common.MapStr
+
+	sampleEventPath := filepath.Join(dataStreamPath, "sample_event.json")
+	content, err := os.ReadFile(sampleEventPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("can't read sample event (path: %s): %w", sampleEventPath, err)
+	}
+	if err == nil {
+		var event common.MapStr
+		err = json.Unmarshal(content, &event)
+		if err != nil {
+			return nil, fmt.Errorf("can't unmarshal sample event (path: %s): %w", sampleEventPath, err)
+		}
+		docs = append(docs, event)
+	}
+
+	expectedPaths, err := filepath.Glob(filepath.Join(dataStreamPath, "_dev", "test", "pipeline", "*-expected.json"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for expected results of pipeline tests: %w", err)
+	}
+	for _, expectedPath := range expectedPaths {
+		content, err := os.ReadFile(expectedPath)
+		if err != nil {
+			return nil, fmt.Errorf("can't read expected results (path: %s): %w", expectedPath, err)
+		}
+		var expected struct {
+			Expected []common.MapStr `json:"expected"`
+		}
+		err = json.Unmarshal(content, &expected)
+		if err != nil {
+			return nil, fmt.Errorf("can't unmarshal expected results (path: %s): %w", expectedPath, err)
+		}
+		for _, doc := range expected.Expected {
+			// Documents dropped by the pipeline are null.
+			if doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs, nil
+}
+
+const fieldCoverageBadgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{width}}" height="20" role="img" aria-label="{{label}}: {{value}}">
+<title>{{label}}: {{value}}</title>
+<rect width="{{labelWidth}}" height="20" fill="#555"/>
+<rect x="{{labelWidth}}" width="{{valueWidth}}" height="20" fill="{{color}}"/>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{labelX}}" y="14">{{label}}</text>
+<text x="{{valueX}}" y="14">{{value}}</text>
+</g>
+</svg>
+`
+
+// FieldCoverageBadge returns an SVG badge showing the given percentage of field coverage.
+func FieldCoverageBadge(percentage float64) []byte {
+	const label = "field coverage"
+	value := fmt.Sprintf("%.1f%%", percentage)
+
+	// Approximate width of the text, there is no need to be exact.
+	labelWidth := len(label)*7 + 10
+	valueWidth := len(value)*7 + 10
+
+	color := "#e05d44" // red
+	switch {
+	case percentage >= 80:
+		color = "#4c1" // green
+	case percentage >= 60:
+		color = "#dfb317" // yellow
+	}
+
+	badge := strings.NewReplacer(
+		"{{width}}", fmt.Sprint(labelWidth+valueWidth),
+		"{{labelWidth}}", fmt.Sprint(labelWidth),
+		"{{valueWidth}}", fmt.Sprint(valueWidth),
+		"{{labelX}}", fmt.Sprint(labelWidth/2),
+		"{{valueX}}", fmt.Sprint(labelWidth+valueWidth/2),
+		"{{color}}", color,
+		"{{label}}", label,
+		"{{value}}", value,
+	).Replace(fieldCoverageBadgeTemplate)
+	return []byte(badge)
+}
+
+// WriteFieldCoverageBadge writes the field coverage badge of a package in the test coverage reports
+// folder, and returns the path of the written file.
+func WriteFieldCoverageBadge(report *FieldCoverageReport) (string, error) {
+	dest, err := testCoverageReportsDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine test coverage reports folder: %w", err)
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", fmt.Errorf("could not create test coverage reports folder: %w", err)
+	}
+
+	filePath := filepath.Join(dest, fmt.Sprintf("field-coverage-%s.svg", report.PackageName))
+	if err := os.WriteFile(filePath, FieldCoverageBadge(report.Percentage()), 0644); err != nil {
+		return "", fmt.Errorf("could not write field coverage badge: %w", err)
+	}
+	return filePath, nil
+}
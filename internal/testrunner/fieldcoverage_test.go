@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestGenerateFieldCoverageReport(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, "manifest.yml", "format_version: 3.0.0\nname: test\nversion: 1.0.0\n")
+	filestest.WriteFile(t, packageRoot, "data_stream/logs/fields/fields.yml", `
+- name: source
+  type: group
+  fields:
+    - name: ip
+      type: ip
+    - name: port
+      type: long
+- name: message
+  type: text
+`)
+	filestest.WriteFile(t, packageRoot, "data_stream/logs/sample_event.json", `{"source": {"ip": "10.0.0.1"}}`)
+	filestest.WriteFile(t, packageRoot, "data_stream/logs/_dev/test/pipeline/test-logs.log-expected.json", `{"expected": [{"message": "test"}, null]}`)
+	filestest.WriteFile(t, packageRoot, "data_stream/metrics/fields/fields.yml", `
+- name: source.bytes
+  type: long
+`)
+
+	report, err := GenerateFieldCoverageReport("test", packageRoot)
+	require.NoError(t, err)
+
+	require.Len(t, report.DataStreams, 2)
+	assert.Equal(t, "logs", report.DataStreams[0].Name)
+	assert.Equal(t, []string{"message", "source.ip"}, report.DataStreams[0].Covered)
+	assert.Equal(t, []string{"source.port"}, report.DataStreams[0].Uncovered())
+	assert.Equal(t, "metrics", report.DataStreams[1].Name)
+	assert.Empty(t, report.DataStreams[1].Covered)
+
+	assert.Equal(t, 4, report.Defined())
+	assert.Equal(t, 2, report.Covered())
+	assert.Equal(t, float64(50), report.Percentage())
+}
+
+func TestFieldCoverageBadge(t *testing.T) {
+	cases := []struct {
+		percentage float64
+		value      string
+		color      string
+	}{
+		{percentage: 100, value: "100.0%", color: "#4c1"},
+		{percentage: 66.66, value: "66.7%", color: "#dfb317"},
+		{percentage: 12.5, value: "12.5%", color: "#e05d44"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			badge := string(FieldCoverageBadge(c.percentage))
+			assert.Contains(t, badge, "<svg")
+			assert.Contains(t, badge, ">field coverage<")
+			assert.Contains(t, badge, ">"+c.value+"<")
+			assert.Contains(t, badge, `fill="`+c.color+`"`)
+		})
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/elastic/go-ucfg"
 	"github.com/elastic/go-ucfg/yaml"
@@ -20,11 +23,88 @@ type globalTestConfig struct {
 	Policy   GlobalRunnerTestConfig `config:"policy"`
 	Static   GlobalRunnerTestConfig `config:"static"`
 	System   GlobalRunnerTestConfig `config:"system"`
+
+	// Coverage contains settings for the coverage reports of the package.
+	Coverage struct {
+		// Type overrides the format of the coverage reports of the package.
+		Type string `config:"type"`
+	} `config:"coverage"`
+}
+
+// CoverageType returns the format of the coverage reports for the package, which is the one
+// configured in the package if any, or the given default type otherwise. It should not be used
+// when the format is explicitly selected by the user.
+func (c *globalTestConfig) CoverageType(defaultType string) string {
+	if c.Coverage.Type != "" {
+		return c.Coverage.Type
+	}
+	return defaultType
 }
 
 type GlobalRunnerTestConfig struct {
 	Parallel        bool `config:"parallel"`
 	SkippableConfig `config:",inline"`
+
+	// BeforeTest and AfterTest are paths to scripts, relative to the package root, to run before
+	// and after each test. Only supported by system tests.
+	BeforeTest string `config:"before_test"`
+	AfterTest  string `config:"after_test"`
+
+	// WaitFor is the path to a script, relative to the package root, that is run until it succeeds
+	// before checking the documents ingested by each test. Only supported by system tests.
+	WaitFor string `config:"wait_for"`
+
+	// NormalizedSampleEvent enables the verification of sample events being formatted as when they
+	// are generated by system tests. Only supported by static tests.
+	NormalizedSampleEvent bool `config:"normalized_sample_event"`
+
+	// NormalizedExpectedResults enables the verification of expected results being formatted as
+	// when they are generated. Only supported by pipeline tests.
+	NormalizedExpectedResults bool `config:"normalized_expected_results"`
+
+	// AllowDatasetMismatch reports documents with different values in event.dataset and
+	// data_stream.dataset as warnings instead of failures. Supported by pipeline, static and
+	// system tests.
+	AllowDatasetMismatch bool `config:"allow_dataset_mismatch"`
+
+	// StrictExpectedDatasets makes validation fail when an expected dataset template references
+	// fields missing in the documents, instead of skipping the template. Supported by pipeline,
+	// static and system tests.
+	StrictExpectedDatasets bool `config:"strict_expected_datasets"`
+
+	// Variables are user-defined values available for substitution in the test configuration
+	// files with the `var` helper. Only supported by system tests.
+	Variables map[string]string `config:"variables"`
+
+	// Readiness contains readiness probes by input type, readiness is not checked for other
+	// inputs. Only supported by system tests.
+	Readiness map[string]ReadinessProbe `config:"readiness"`
+
+	// AgentWarnings selects the warning messages of the Elastic Agent that make tests fail when
+	// they are run with --fail-on-agent-warnings. Only supported by system tests.
+	AgentWarnings AgentWarningsConfig `config:"agent_warnings"`
+}
+
+// AgentWarningsConfig contains regular expressions to select warning messages in logs.
+type AgentWarningsConfig struct {
+	// Includes are the patterns of the messages to report. All warnings are reported if empty.
+	Includes []string `config:"includes"`
+
+	// Excludes are the patterns of the messages to ignore, even if they match any of the includes.
+	Excludes []string `config:"excludes"`
+}
+
+// ReadinessProbe configures how to check that an input is ready to collect data, before waiting
+// for the documents it ingests.
+type ReadinessProbe struct {
+	// Type is the type of probe, one of "input_status", "tcp", "http" or "none".
+	Type string `config:"type"`
+
+	// Address is the address to connect to in "tcp" probes, or the URL to request in "http" probes.
+	Address string `config:"address"`
+
+	// Timeout is the maximum time to wait for the probe to succeed.
+	Timeout time.Duration `config:"timeout"`
 }
 
 func ReadGlobalTestConfig(packageRootPath string) (*globalTestConfig, error) {
@@ -46,6 +126,9 @@ func ReadGlobalTestConfig(packageRootPath string) (*globalTestConfig, error) {
 	if err := cfg.Unpack(&c); err != nil {
 		return nil, fmt.Errorf("unable to unpack global test configuration file: %s: %w", configFilePath, err)
 	}
+	if c.Coverage.Type != "" && !slices.Contains(CoverageFormatsList(), c.Coverage.Type) {
+		return nil, fmt.Errorf("invalid coverage type %q in global test configuration file %s, expected one of: %s", c.Coverage.Type, configFilePath, strings.Join(CoverageFormatsList(), ", "))
+	}
 
 	return &c, nil
 }
@@ -0,0 +1,37 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestReadGlobalTestConfigCoverageType(t *testing.T) {
+	t.Run("no config", func(t *testing.T) {
+		config, err := ReadGlobalTestConfig(t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, "generic", config.CoverageType("generic"))
+	})
+
+	t.Run("override", func(t *testing.T) {
+		packageRoot := t.TempDir()
+		filestest.WriteFile(t, packageRoot, "_dev/test/config.yml", "coverage:\n  type: cobertura\n")
+		config, err := ReadGlobalTestConfig(packageRoot)
+		require.NoError(t, err)
+		assert.Equal(t, "cobertura", config.CoverageType("generic"))
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		packageRoot := t.TempDir()
+		filestest.WriteFile(t, packageRoot, "_dev/test/config.yml", "coverage:\n  type: lcov\n")
+		_, err := ReadGlobalTestConfig(packageRoot)
+		assert.ErrorContains(t, err, `invalid coverage type "lcov"`)
+	})
+}
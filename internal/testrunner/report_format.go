@@ -11,8 +11,18 @@ import (
 // TestReportFormat represents a test report format
 type TestReportFormat string
 
+// ReportOptions contains additional settings for the test report formatters.
+type ReportOptions struct {
+	// SuiteName overrides the name of the test suite, in formats that support it.
+	SuiteName string
+
+	// Properties are additional key-value pairs to include in the report,
+	// in formats that support it.
+	Properties map[string]string
+}
+
 // ReportFormatFunc defines the report formatter function.
-type ReportFormatFunc func(results []TestResult) (string, error)
+type ReportFormatFunc func(results []TestResult, options ReportOptions) (string, error)
 
 var reportFormatters = map[TestReportFormat]ReportFormatFunc{}
 
@@ -22,11 +32,11 @@ func RegisterReporterFormat(name TestReportFormat, formatFunc ReportFormatFunc)
 }
 
 // FormatReport delegates formatting of test results to the registered test report formatter
-func FormatReport(name TestReportFormat, results []TestResult) (string, error) {
+func FormatReport(name TestReportFormat, results []TestResult, options ReportOptions) (string, error) {
 	reportFunc, defined := reportFormatters[name]
 	if !defined {
 		return "", fmt.Errorf("unregistered test report format: %s", name)
 	}
 
-	return reportFunc(results)
+	return reportFunc(results, options)
 }
@@ -22,7 +22,7 @@ const (
 	ReportFormatHuman testrunner.TestReportFormat = "human"
 )
 
-func reportHumanFormat(results []testrunner.TestResult) (string, error) {
+func reportHumanFormat(results []testrunner.TestResult, _ testrunner.ReportOptions) (string, error) {
 	if len(results) == 0 {
 		return "No test results", nil
 	}
@@ -7,17 +7,22 @@ package formats
 import (
 	"encoding/xml"
 	"fmt"
+	"sort"
 
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
 func init() {
 	testrunner.RegisterReporterFormat(ReportFormatXUnit, reportXUnitFormat)
+	testrunner.RegisterReporterFormat(ReportFormatJUnit, reportXUnitFormat)
 }
 
 const (
 	// ReportFormatXUnit reports test results in the xUnit format
 	ReportFormatXUnit testrunner.TestReportFormat = "xUnit"
+
+	// ReportFormatJUnit is an alias of ReportFormatXUnit, as the format is commonly known as JUnit XML
+	ReportFormatJUnit testrunner.TestReportFormat = "junit"
 )
 
 type testSuites struct {
@@ -33,9 +38,18 @@ type testSuite struct {
 	NumErrors   int    `xml:"errors,attr,omitempty"`
 	NumSkipped  int    `xml:"skipped,attr,omitempty"`
 
+	Properties *properties `xml:"properties,omitempty"`
+
 	Suites []testSuite `xml:"testsuite,omitempty"`
 	Cases  []testCase  `xml:"testcase,omitempty"`
 }
+type properties struct {
+	Properties []property `xml:"property"`
+}
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
 type testCase struct {
 	Name          string  `xml:"name,attr"`
 	ClassName     string  `xml:"classname,attr"`
@@ -50,7 +64,7 @@ type skipped struct {
 	Message string `xml:"message,attr"`
 }
 
-func reportXUnitFormat(results []testrunner.TestResult) (string, error) {
+func reportXUnitFormat(results []testrunner.TestResult, options testrunner.ReportOptions) (string, error) {
 	// test type => package => data stream => test cases
 	tests := map[string]map[string]map[string][]testCase{}
 
@@ -113,15 +127,21 @@ func reportXUnitFormat(results []testrunner.TestResult) (string, error) {
 	ts.Suites = make([]testSuite, 0)
 
 	for testType, packages := range tests {
+		name := testType
+		if options.SuiteName != "" {
+			name = options.SuiteName
+		}
 		testTypeSuite := testSuite{
 			Comment: fmt.Sprintf("test suite for %s tests", testType),
-			Name:    testType,
+			Name:    name,
 
 			NumTests:    numTests,
 			NumFailures: numFailures,
 			NumErrors:   numErrors,
 			NumSkipped:  numSkipped,
 
+			Properties: xUnitProperties(options.Properties),
+
 			Cases: make([]testCase, 0),
 		}
 
@@ -141,3 +161,17 @@ func reportXUnitFormat(results []testrunner.TestResult) (string, error) {
 
 	return xml.Header + string(out), nil
 }
+
+func xUnitProperties(values map[string]string) *properties {
+	if len(values) == 0 {
+		return nil
+	}
+	var props properties
+	for name, value := range values {
+		props.Properties = append(props.Properties, property{Name: name, Value: value})
+	}
+	sort.Slice(props.Properties, func(i, j int) bool {
+		return props.Properties[i].Name < props.Properties[j].Name
+	})
+	return &props
+}
@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package formats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestReportXUnitFormatSuiteOptions(t *testing.T) {
+	results := []testrunner.TestResult{
+		{TestType: "system", Package: "nginx", DataStream: "access", Name: "default"},
+	}
+
+	report, err := reportXUnitFormat(results, testrunner.ReportOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, report, `<testsuite name="system" tests="1">`)
+	assert.NotContains(t, report, "<properties>")
+
+	report, err = reportXUnitFormat(results, testrunner.ReportOptions{
+		SuiteName: "nginx-ci",
+		Properties: map[string]string{
+			"stack.version": "8.17.0",
+			"profile":       "default",
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, report, `<testsuite name="nginx-ci" tests="1">`)
+	assert.Contains(t, report, `<properties>
+      <property name="profile" value="default"></property>
+      <property name="stack.version" value="8.17.0"></property>
+    </properties>`)
+}
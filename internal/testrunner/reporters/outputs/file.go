@@ -39,7 +39,7 @@ func reportToFile(pkg, report string, testType testrunner.TestType, format testr
 	}
 
 	ext := "txt"
-	if format == formats.ReportFormatXUnit {
+	if format == formats.ReportFormatXUnit || format == formats.ReportFormatJUnit {
 		ext = "xml"
 	}
 
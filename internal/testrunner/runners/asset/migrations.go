@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package asset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/elastic/elastic-package/internal/builder"
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+// savedObjectAssetTypes are the types of assets that are installed as saved objects that can be
+// imported with the saved objects API.
+var savedObjectAssetTypes = []packages.AssetType{"dashboard", "visualization", "search", "map", "lens"}
+
+// verifySavedObjectsMigration imports the saved objects of the package in Kibana. Kibana migrates
+// imported objects to its version, so objects that can't be migrated fail to be imported. The
+// objects installed with the package are overwritten, they are removed when the package is
+// uninstalled.
+func (r *tester) verifySavedObjectsMigration(ctx context.Context, packageName string, assets []packages.Asset) []testrunner.TestResult {
+	kibanaVersion, err := r.kibanaClient.Version()
+	if err != nil {
+		rc := testrunner.NewResultComposer(testrunner.TestResult{
+			Name:     "saved objects can be migrated",
+			Package:  packageName,
+			TestType: TestType,
+		})
+		results, _ := rc.WithError(fmt.Errorf("can't get version of Kibana: %w", err))
+		return results
+	}
+
+	var savedObjects []packages.Asset
+	var objects []map[string]any
+	var readErrors []error
+	for _, asset := range assets {
+		if !slices.Contains(savedObjectAssetTypes, asset.Type) || asset.SourcePath == "" {
+			continue
+		}
+		object, err := readSavedObject(asset.SourcePath)
+		savedObjects = append(savedObjects, asset)
+		readErrors = append(readErrors, err)
+		if err == nil {
+			objects = append(objects, object)
+		}
+	}
+	if len(savedObjects) == 0 {
+		return nil
+	}
+
+	var importErrors []kibana.ImportResult
+	var importErr error
+	if len(objects) > 0 {
+		logger.Debugf("importing %d saved objects in Kibana %s...", len(objects), kibanaVersion.Version())
+		resp, err := r.kibanaClient.ImportSavedObjects(ctx, kibana.ImportSavedObjectsRequest{
+			Overwrite: true,
+			Objects:   objects,
+		})
+		if err != nil {
+			importErr = err
+		} else {
+			importErrors = resp.Errors
+		}
+	}
+
+	var results []testrunner.TestResult
+	for i, asset := range savedObjects {
+		rc := testrunner.NewResultComposer(testrunner.TestResult{
+			Name:       fmt.Sprintf("%s %s can be migrated to Kibana %s", asset.Type, asset.ID, kibanaVersion.Version()),
+			Package:    packageName,
+			DataStream: asset.DataStream,
+			TestType:   TestType,
+		})
+
+		var tr []testrunner.TestResult
+		switch {
+		case readErrors[i] != nil:
+			tr, _ = rc.WithError(readErrors[i])
+		case importErr != nil:
+			tr, _ = rc.WithError(fmt.Errorf("can't import saved objects: %w", importErr))
+		default:
+			if failure, found := findImportError(importErrors, asset); found {
+				tr, _ = rc.WithError(testrunner.ErrTestCaseFailed{
+					Reason:  fmt.Sprintf("saved object can't be migrated to Kibana %s", kibanaVersion.Version()),
+					Details: fmt.Sprintf("%s %s (path: %s): %s", asset.Type, asset.ID, asset.SourcePath, failure),
+				})
+			} else {
+				tr, _ = rc.WithSuccess()
+			}
+		}
+		results = append(results, tr...)
+	}
+	return results
+}
+
+// readSavedObject reads a saved object from the package, encoding its fields as they are in built packages.
+func readSavedObject(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read saved object (path: %s): %w", path, err)
+	}
+	encoded, err := builder.EncodeSavedObject(content)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode saved object (path: %s): %w", path, err)
+	}
+	var object map[string]any
+	err = json.Unmarshal(encoded, &object)
+	if err != nil {
+		return nil, fmt.Errorf("can't unmarshal saved object (path: %s): %w", path, err)
+	}
+	return object, nil
+}
+
+// findImportError returns a description of the error found when importing the saved object of the
+// given asset, if any.
+func findImportError(importErrors []kibana.ImportResult, asset packages.Asset) (string, bool) {
+	for _, importError := range importErrors {
+		if importError.ID != asset.ID || importError.Type != string(asset.Type) {
+			continue
+		}
+		description, err := json.Marshal(importError.Error)
+		if err != nil {
+			description = []byte(fmt.Sprint(importError.Error))
+		}
+		return string(description), true
+	}
+	return "", false
+}
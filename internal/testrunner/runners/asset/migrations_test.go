@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package asset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestReadSavedObject(t *testing.T) {
+	path := filestest.WriteFile(t, t.TempDir(), "dashboard.json", `{
+  "id": "test-dashboard",
+  "type": "dashboard",
+  "attributes": {
+    "title": "Test",
+    "panelsJSON": [{"panelIndex": "1"}]
+  }
+}`)
+
+	object, err := readSavedObject(path)
+	require.NoError(t, err)
+	assert.Equal(t, "test-dashboard", object["id"])
+
+	// Fields are encoded as in built packages.
+	attributes, ok := object["attributes"].(map[string]any)
+	require.True(t, ok)
+	assert.IsType(t, "", attributes["panelsJSON"])
+}
+
+func TestFindImportError(t *testing.T) {
+	importErrors := []kibana.ImportResult{
+		{
+			ID:    "test-dashboard",
+			Type:  "dashboard",
+			Error: map[string]any{"type": "unknown", "message": "migration function failed"},
+		},
+	}
+
+	failure, found := findImportError(importErrors, packages.Asset{ID: "test-dashboard", Type: "dashboard"})
+	assert.True(t, found)
+	assert.Equal(t, `{"message":"migration function failed","type":"unknown"}`, failure)
+
+	_, found = findImportError(importErrors, packages.Asset{ID: "test-dashboard", Type: "lens"})
+	assert.False(t, found)
+
+	_, found = findImportError(importErrors, packages.Asset{ID: "other", Type: "dashboard"})
+	assert.False(t, found)
+}
@@ -7,6 +7,7 @@ package asset
 import (
 	"context"
 
+	"github.com/elastic/elastic-package/internal/elasticsearch"
 	"github.com/elastic/elastic-package/internal/kibana"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
@@ -22,6 +23,10 @@ type runner struct {
 	globalTestConfig testrunner.GlobalRunnerTestConfig
 	withCoverage     bool
 	coverageType     string
+	checkIdempotency bool
+	checkMigrations  bool
+	esClient         *elasticsearch.Client
+	upgradeFrom      string
 }
 
 type AssetTestRunnerOptions struct {
@@ -30,6 +35,10 @@ type AssetTestRunnerOptions struct {
 	GlobalTestConfig testrunner.GlobalRunnerTestConfig
 	WithCoverage     bool
 	CoverageType     string
+	CheckIdempotency bool
+	CheckMigrations  bool
+	ESClient         *elasticsearch.Client
+	UpgradeFrom      string
 }
 
 func NewAssetTestRunner(options AssetTestRunnerOptions) *runner {
@@ -39,6 +48,10 @@ func NewAssetTestRunner(options AssetTestRunnerOptions) *runner {
 		globalTestConfig: options.GlobalTestConfig,
 		withCoverage:     options.WithCoverage,
 		coverageType:     options.CoverageType,
+		checkIdempotency: options.CheckIdempotency,
+		checkMigrations:  options.CheckMigrations,
+		esClient:         options.ESClient,
+		upgradeFrom:      options.UpgradeFrom,
 	}
 	return &runner
 }
@@ -68,6 +81,10 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 			GlobalTestConfig: r.globalTestConfig,
 			WithCoverage:     r.withCoverage,
 			CoverageType:     r.coverageType,
+			CheckIdempotency: r.checkIdempotency,
+			CheckMigrations:  r.checkMigrations,
+			ESClient:         r.esClient,
+			UpgradeFrom:      r.upgradeFrom,
 		}),
 	}
 	return testers, nil
@@ -8,8 +8,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/elastic/elastic-package/internal/elasticsearch"
 	"github.com/elastic/elastic-package/internal/kibana"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages"
@@ -25,6 +27,14 @@ type tester struct {
 	globalTestConfig testrunner.GlobalRunnerTestConfig
 	withCoverage     bool
 	coverageType     string
+	checkIdempotency bool
+	checkMigrations  bool
+
+	esClient    *elasticsearch.Client
+	upgradeFrom string
+
+	// upgradedDataStreams are the data streams created to test the upgrade of the package.
+	upgradedDataStreams []upgradeDataStream
 }
 
 type AssetTesterOptions struct {
@@ -34,6 +44,10 @@ type AssetTesterOptions struct {
 	GlobalTestConfig testrunner.GlobalRunnerTestConfig
 	WithCoverage     bool
 	CoverageType     string
+	CheckIdempotency bool
+	CheckMigrations  bool
+	ESClient         *elasticsearch.Client
+	UpgradeFrom      string
 }
 
 func NewAssetTester(options AssetTesterOptions) *tester {
@@ -44,6 +58,10 @@ func NewAssetTester(options AssetTesterOptions) *tester {
 		globalTestConfig: options.GlobalTestConfig,
 		withCoverage:     options.WithCoverage,
 		coverageType:     options.CoverageType,
+		checkIdempotency: options.CheckIdempotency,
+		checkMigrations:  options.CheckMigrations,
+		esClient:         options.ESClient,
+		upgradeFrom:      options.UpgradeFrom,
 	}
 
 	manager := resources.NewManager()
@@ -114,15 +132,25 @@ func (r *tester) run(ctx context.Context) ([]testrunner.TestResult, error) {
 		return result.WithSkip(skip)
 	}
 
-	logger.Debug("installing package...")
-	_, err = r.resourcesManager.ApplyCtx(ctx, r.resources(true))
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(r.packageRootPath)
 	if err != nil {
-		return result.WithError(fmt.Errorf("can't install the package: %w", err))
+		return result.WithError(fmt.Errorf("cannot read the package manifest from %s: %w", r.packageRootPath, err))
 	}
 
-	manifest, err := packages.ReadPackageManifestFromPackageRoot(r.packageRootPath)
+	if r.upgradeFrom != "" {
+		if r.esClient == nil {
+			return result.WithError(errors.New("missing Elasticsearch client"))
+		}
+		err = r.prepareUpgrade(ctx, manifest)
+		if err != nil {
+			return result.WithError(fmt.Errorf("can't prepare the upgrade test: %w", err))
+		}
+	}
+
+	logger.Debug("installing package...")
+	_, err = r.resourcesManager.ApplyCtx(ctx, r.resources(true))
 	if err != nil {
-		return result.WithError(fmt.Errorf("cannot read the package manifest from %s: %w", r.packageRootPath, err))
+		return result.WithError(fmt.Errorf("can't install the package: %w", err))
 	}
 	installedPackage, err := r.kibanaClient.GetPackage(ctx, manifest.Name)
 	if err != nil {
@@ -175,13 +203,99 @@ func (r *tester) run(ctx context.Context) ([]testrunner.TestResult, error) {
 		results = append(results, result)
 	}
 
+	if r.checkIdempotency {
+		results = append(results, r.verifyIdempotentInstallation(ctx, manifest.Name, installedAssets)...)
+	}
+
+	if r.checkMigrations {
+		results = append(results, r.verifySavedObjectsMigration(ctx, manifest.Name, expectedAssets)...)
+	}
+
+	if r.upgradeFrom != "" {
+		results = append(results, r.verifyUpgrade(ctx, manifest.Name, r.upgradedDataStreams)...)
+	}
+
 	return results, nil
 }
 
+// verifyIdempotentInstallation installs the package again and checks that the installed assets
+// are the same as in the first installation, so reinstallations don't duplicate or leave orphaned assets.
+func (r *tester) verifyIdempotentInstallation(ctx context.Context, packageName string, installedAssets []packages.Asset) []testrunner.TestResult {
+	rc := testrunner.NewResultComposer(testrunner.TestResult{
+		Name:     "assets are not modified when reinstalling the package",
+		Package:  packageName,
+		TestType: TestType,
+	})
+
+	logger.Debug("reinstalling package...")
+	_, err := r.resourcesManager.ApplyCtx(ctx, r.resources(true))
+	if err != nil {
+		results, _ := rc.WithError(fmt.Errorf("can't reinstall the package: %w", err))
+		return results
+	}
+
+	reinstalledPackage, err := r.kibanaClient.GetPackage(ctx, packageName)
+	if err != nil {
+		results, _ := rc.WithError(fmt.Errorf("cannot get reinstalled package %q: %w", packageName, err))
+		return results
+	}
+
+	changes := compareInstalledAssets(installedAssets, reinstalledPackage.Assets())
+	if len(changes) > 0 {
+		results, _ := rc.WithError(testrunner.ErrTestCaseFailed{
+			Reason:  "installed assets changed after reinstalling the package",
+			Details: strings.Join(changes, "\n"),
+		})
+		return results
+	}
+
+	results, _ := rc.WithSuccess()
+	return results
+}
+
+// compareInstalledAssets returns a description of the assets whose count changed between installations,
+// or that are installed more than once.
+func compareInstalledAssets(before, after []packages.Asset) []string {
+	countAssets := func(assets []packages.Asset) map[packages.Asset]int {
+		counts := make(map[packages.Asset]int)
+		for _, asset := range assets {
+			counts[packages.Asset{ID: asset.ID, Type: asset.Type}]++
+		}
+		return counts
+	}
+	beforeCounts := countAssets(before)
+	afterCounts := countAssets(after)
+
+	var changes []string
+	for asset, count := range afterCounts {
+		switch {
+		case beforeCounts[asset] != count:
+			changes = append(changes, fmt.Sprintf("- %s: %d before, %d after reinstalling", asset.String(), beforeCounts[asset], count))
+		case count > 1:
+			changes = append(changes, fmt.Sprintf("- %s: installed %d times", asset.String(), count))
+		}
+	}
+	for asset, count := range beforeCounts {
+		if _, found := afterCounts[asset]; !found {
+			changes = append(changes, fmt.Sprintf("- %s: %d before, 0 after reinstalling", asset.String(), count))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
 func (r *tester) TearDown(ctx context.Context) error {
 	// Avoid cancellations during cleanup.
 	cleanupCtx := context.WithoutCancel(ctx)
 
+	if len(r.upgradedDataStreams) > 0 {
+		logger.Debug("deleting data streams used to test the upgrade...")
+		err := r.cleanupUpgrade(cleanupCtx)
+		if err != nil {
+			return err
+		}
+	}
+
 	logger.Debug("removing package...")
 	_, err := r.resourcesManager.ApplyCtx(cleanupCtx, r.resources(false))
 	if err != nil {
@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package asset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+func TestCompareInstalledAssets(t *testing.T) {
+	dashboard := packages.Asset{ID: "dashboard-1", Type: "dashboard"}
+	template := packages.Asset{ID: "logs-test.foo", Type: "index_template"}
+	pipeline := packages.Asset{ID: "logs-test.foo-1.0.0", Type: "ingest_pipeline"}
+
+	cases := []struct {
+		title    string
+		before   []packages.Asset
+		after    []packages.Asset
+		expected []string
+	}{
+		{
+			title:  "same assets",
+			before: []packages.Asset{dashboard, template},
+			after:  []packages.Asset{template, dashboard},
+		},
+		{
+			title:  "data stream and source path are not compared",
+			before: []packages.Asset{dashboard},
+			after:  []packages.Asset{{ID: dashboard.ID, Type: dashboard.Type, DataStream: "foo", SourcePath: "/tmp"}},
+		},
+		{
+			title:  "duplicated and orphaned assets",
+			before: []packages.Asset{dashboard, template},
+			after:  []packages.Asset{dashboard, dashboard, pipeline},
+			expected: []string{
+				"- dashboard-1 (type: dashboard): 1 before, 2 after reinstalling",
+				"- logs-test.foo (type: index_template): 1 before, 0 after reinstalling",
+				"- logs-test.foo-1.0.0 (type: ingest_pipeline): 0 before, 1 after reinstalling",
+			},
+		},
+		{
+			title:    "assets installed more than once",
+			before:   []packages.Asset{dashboard, dashboard},
+			after:    []packages.Asset{dashboard, dashboard},
+			expected: []string{"- dashboard-1 (type: dashboard): installed 2 times"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, compareInstalledAssets(c.before, c.after))
+		})
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package asset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+// upgradeTestNamespace is the namespace of the data streams used to test upgrades.
+const upgradeTestNamespace = "upgradetest"
+
+// upgradeDataStream is a data stream used to test the upgrade of the package, with the sample
+// event ingested in it.
+type upgradeDataStream struct {
+	name        string
+	dataStream  string
+	sampleEvent common.MapStr
+}
+
+// prepareUpgrade installs the version of the package to upgrade from, and ingests the sample events
+// of the package in its data streams, so there is data to check after the upgrade. Data streams are
+// recorded in the tester as soon as they are created, so they are cleaned up even if this fails.
+func (r *tester) prepareUpgrade(ctx context.Context, manifest *packages.PackageManifest) error {
+	fromVersion, err := semver.NewVersion(r.upgradeFrom)
+	if err != nil {
+		return fmt.Errorf("invalid version to upgrade from %q: %w", r.upgradeFrom, err)
+	}
+	currentVersion, err := semver.NewVersion(manifest.Version)
+	if err != nil {
+		return fmt.Errorf("invalid version of the package %q: %w", manifest.Version, err)
+	}
+	if !fromVersion.LessThan(currentVersion) {
+		return fmt.Errorf("version to upgrade from (%s) must be older than the version of the package (%s)", fromVersion, currentVersion)
+	}
+
+	dataStreams, err := r.upgradeDataStreams(manifest)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("installing version %s of package %s...", r.upgradeFrom, manifest.Name)
+	_, err = r.kibanaClient.InstallPackage(ctx, manifest.Name, r.upgradeFrom)
+	if err != nil {
+		return fmt.Errorf("can't install version %s of the package: %w", r.upgradeFrom, err)
+	}
+
+	for _, ds := range dataStreams {
+		// The sample event is the one of the new version, fields added since the version to upgrade
+		// from would be dynamically mapped and reported as conflicts, so only the fields mapped in
+		// the old version are ingested before the upgrade.
+		mappedFields, err := r.mappedFields(ctx, ds.dataStream)
+		if err != nil {
+			return fmt.Errorf("can't get mappings of version %s of the package: %w", r.upgradeFrom, err)
+		}
+		event, err := filterMappedFields(ds.sampleEvent, mappedFields)
+		if err != nil {
+			return fmt.Errorf("can't build sample event for version %s of the package: %w", r.upgradeFrom, err)
+		}
+
+		r.upgradedDataStreams = append(r.upgradedDataStreams, ds)
+		err = r.indexEvent(ctx, ds.dataStream, event)
+		if err != nil {
+			return fmt.Errorf("can't ingest documents with version %s of the package: %w", r.upgradeFrom, err)
+		}
+	}
+	return nil
+}
+
+// upgradeDataStreams returns the data streams of the package that have a sample event.
+func (r *tester) upgradeDataStreams(manifest *packages.PackageManifest) ([]upgradeDataStream, error) {
+	manifestPaths, err := filepath.Glob(filepath.Join(r.packageRootPath, "data_stream", "*", packages.DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data stream manifests: %w", err)
+	}
+
+	var dataStreams []upgradeDataStream
+	for _, manifestPath := range manifestPaths {
+		dsManifest, err := packages.ReadDataStreamManifest(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("can't read data stream manifest: %w", err)
+		}
+
+		name := filepath.Base(filepath.Dir(manifestPath))
+		sampleEventPath := filepath.Join(filepath.Dir(manifestPath), "sample_event.json")
+		content, err := os.ReadFile(sampleEventPath)
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Debugf("data stream %s has no sample event, it is not included in the upgrade test", name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read sample event (path: %s): %w", sampleEventPath, err)
+		}
+
+		var event common.MapStr
+		err = json.Unmarshal(content, &event)
+		if err != nil {
+			return nil, fmt.Errorf("can't unmarshal sample event (path: %s): %w", sampleEventPath, err)
+		}
+
+		dataset := dsManifest.Dataset
+		if dataset == "" {
+			dataset = manifest.Name + "." + name
+		}
+		for key, value := range map[string]string{
+			"data_stream.type":      dsManifest.Type,
+			"data_stream.dataset":   dataset,
+			"data_stream.namespace": upgradeTestNamespace,
+		} {
+			_, err := event.Put(key, value)
+			if err != nil {
+				return nil, fmt.Errorf("can't set %s in sample event: %w", key, err)
+			}
+		}
+
+		dataStreams = append(dataStreams, upgradeDataStream{
+			name:        name,
+			dataStream:  fmt.Sprintf("%s-%s-%s", dsManifest.Type, dataset, upgradeTestNamespace),
+			sampleEvent: event,
+		})
+	}
+	return dataStreams, nil
+}
+
+// mappedFields returns the paths of the leaf fields mapped by the index template of the data stream.
+func (r *tester) mappedFields(ctx context.Context, dataStream string) ([]string, error) {
+	// A suffix is appended so the simulation doesn't use the existing data stream, if any.
+	resp, err := r.esClient.Indices.SimulateIndexTemplate(dataStream+"simulated",
+		r.esClient.Indices.SimulateIndexTemplate.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't simulate index template for %s: %w", dataStream, err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return nil, fmt.Errorf("can't simulate index template for %s: %s", dataStream, resp.String())
+	}
+
+	var simulated struct {
+		Template struct {
+			Mappings struct {
+				Properties map[string]mappingProperty `json:"properties"`
+			} `json:"mappings"`
+		} `json:"template"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&simulated)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode index template simulation for %s: %w", dataStream, err)
+	}
+	return leafFields("", simulated.Template.Mappings.Properties), nil
+}
+
+type mappingProperty struct {
+	Type       string                     `json:"type"`
+	Properties map[string]mappingProperty `json:"properties"`
+}
+
+// leafFields returns the sorted paths of the mapped fields that are not objects.
+func leafFields(prefix string, properties map[string]mappingProperty) []string {
+	var fields []string
+	for name, property := range properties {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if len(property.Properties) > 0 {
+			fields = append(fields, leafFields(name, property.Properties)...)
+			continue
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// filterMappedFields returns a copy of the event with only the fields that are mapped, or that
+// are below a mapped field, as happens with flattened fields.
+func filterMappedFields(event common.MapStr, mappedFields []string) (common.MapStr, error) {
+	filtered := make(common.MapStr)
+	for key, value := range event.Flatten() {
+		if !isMappedField(key, mappedFields) {
+			logger.Debugf("field %q is not mapped before the upgrade, it is not ingested", key)
+			continue
+		}
+		_, err := filtered.Put(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("can't set %s in event: %w", key, err)
+		}
+	}
+	return filtered, nil
+}
+
+func isMappedField(key string, mappedFields []string) bool {
+	for {
+		if _, found := slices.BinarySearch(mappedFields, key); found {
+			return true
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			return false
+		}
+		key = key[:i]
+	}
+}
+
+// indexEvent indexes the event in the data stream. Ingest pipelines are not executed, as sample
+// events are the result of their processing.
+func (r *tester) indexEvent(ctx context.Context, dataStream string, event common.MapStr) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("can't marshal sample event: %w", err)
+	}
+
+	resp, err := r.esClient.Index(dataStream, bytes.NewReader(body),
+		r.esClient.Index.WithContext(ctx),
+		r.esClient.Index.WithOpType("create"),
+		r.esClient.Index.WithPipeline("_none"),
+		r.esClient.Index.WithRefresh("true"),
+	)
+	if err != nil {
+		return fmt.Errorf("can't index sample event in data stream %s: %w", dataStream, err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return fmt.Errorf("can't index sample event in data stream %s: %s", dataStream, resp.String())
+	}
+	return nil
+}
+
+// verifyUpgrade checks that the data streams ingested before the upgrade can be rolled over to the
+// mappings of the installed package, that they accept documents after the upgrade, and that their
+// backing indices don't have conflicting mappings.
+func (r *tester) verifyUpgrade(ctx context.Context, packageName string, dataStreams []upgradeDataStream) []testrunner.TestResult {
+	var results []testrunner.TestResult
+	for _, ds := range dataStreams {
+		rc := testrunner.NewResultComposer(testrunner.TestResult{
+			Name:       fmt.Sprintf("data stream can be upgraded from version %s", r.upgradeFrom),
+			Package:    packageName,
+			DataStream: ds.name,
+			TestType:   TestType,
+		})
+
+		var tr []testrunner.TestResult
+		conflicts, err := r.verifyDataStreamUpgrade(ctx, ds)
+		switch {
+		case err != nil:
+			tr, _ = rc.WithError(err)
+		case len(conflicts) > 0:
+			tr, _ = rc.WithError(testrunner.ErrTestCaseFailed{
+				Reason:  fmt.Sprintf("mapping conflicts found after upgrading from version %s", r.upgradeFrom),
+				Details: strings.Join(conflicts, "\n"),
+			})
+		default:
+			tr, _ = rc.WithSuccess()
+		}
+		results = append(results, tr...)
+	}
+	return results
+}
+
+func (r *tester) verifyDataStreamUpgrade(ctx context.Context, ds upgradeDataStream) ([]string, error) {
+	// Fleet updates the mappings of the write index only when they are compatible, the new mappings
+	// are applied to all the data ingested after the next rollover.
+	resp, err := r.esClient.Indices.Rollover(ds.dataStream,
+		r.esClient.Indices.Rollover.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't rollover data stream %s: %w", ds.dataStream, err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return nil, fmt.Errorf("can't rollover data stream %s: %s", ds.dataStream, resp.String())
+	}
+
+	err = r.indexEvent(ctx, ds.dataStream, ds.sampleEvent)
+	if err != nil {
+		return nil, fmt.Errorf("can't ingest documents after upgrading: %w", err)
+	}
+
+	resp, err = r.esClient.FieldCaps(
+		r.esClient.FieldCaps.WithContext(ctx),
+		r.esClient.FieldCaps.WithIndex(ds.dataStream),
+		r.esClient.FieldCaps.WithFields("*"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't get field capabilities of data stream %s: %w", ds.dataStream, err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return nil, fmt.Errorf("can't get field capabilities of data stream %s: %s", ds.dataStream, resp.String())
+	}
+
+	var fieldCaps fieldCapsResponse
+	err = json.NewDecoder(resp.Body).Decode(&fieldCaps)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode field capabilities of data stream %s: %w", ds.dataStream, err)
+	}
+	return mappingConflicts(fieldCaps), nil
+}
+
+type fieldCapsResponse struct {
+	Fields map[string]map[string]struct {
+		Indices []string `json:"indices"`
+	} `json:"fields"`
+}
+
+// mappingConflicts returns a description of the fields mapped with different types in the backing
+// indices of a data stream.
+func mappingConflicts(fieldCaps fieldCapsResponse) []string {
+	var conflicts []string
+	for field, types := range fieldCaps.Fields {
+		if len(types) < 2 {
+			continue
+		}
+		var descriptions []string
+		for fieldType, caps := range types {
+			indices := slices.Clone(caps.Indices)
+			slices.Sort(indices)
+			descriptions = append(descriptions, fmt.Sprintf("%s (%s)", fieldType, strings.Join(indices, ", ")))
+		}
+		sort.Strings(descriptions)
+		conflicts = append(conflicts, fmt.Sprintf("- field %q is mapped with different types: %s", field, strings.Join(descriptions, ", ")))
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// cleanupUpgrade deletes the data streams created to test the upgrade.
+func (r *tester) cleanupUpgrade(ctx context.Context) error {
+	for _, ds := range r.upgradedDataStreams {
+		err := r.esClient.DeleteDataStream(ctx, ds.dataStream)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package asset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+func TestMappingConflicts(t *testing.T) {
+	response := `{
+  "indices": [".ds-logs-test.foo-upgradetest-2024.01.01-000001", ".ds-logs-test.foo-upgradetest-2024.01.01-000002"],
+  "fields": {
+    "message": {
+      "match_only_text": {"type": "match_only_text", "searchable": true, "aggregatable": false}
+    },
+    "source.port": {
+      "keyword": {"type": "keyword", "searchable": true, "aggregatable": true, "indices": [".ds-logs-test.foo-upgradetest-2024.01.01-000002"]},
+      "long": {"type": "long", "searchable": true, "aggregatable": true, "indices": [".ds-logs-test.foo-upgradetest-2024.01.01-000001"]}
+    }
+  }
+}`
+	var fieldCaps fieldCapsResponse
+	require.NoError(t, json.Unmarshal([]byte(response), &fieldCaps))
+
+	expected := []string{
+		`- field "source.port" is mapped with different types: keyword (.ds-logs-test.foo-upgradetest-2024.01.01-000002), long (.ds-logs-test.foo-upgradetest-2024.01.01-000001)`,
+	}
+	assert.Equal(t, expected, mappingConflicts(fieldCaps))
+}
+
+func TestFilterMappedFields(t *testing.T) {
+	mappings := `{
+  "@timestamp": {"type": "date"},
+  "data_stream": {
+    "properties": {
+      "dataset": {"type": "constant_keyword"},
+      "namespace": {"type": "constant_keyword"},
+      "type": {"type": "constant_keyword"}
+    }
+  },
+  "foo": {
+    "properties": {
+      "labels": {"type": "flattened"},
+      "message": {"type": "keyword", "fields": {"text": {"type": "match_only_text"}}}
+    }
+  }
+}`
+	var properties map[string]mappingProperty
+	require.NoError(t, json.Unmarshal([]byte(mappings), &properties))
+
+	mappedFields := leafFields("", properties)
+	assert.Equal(t, []string{
+		"@timestamp",
+		"data_stream.dataset",
+		"data_stream.namespace",
+		"data_stream.type",
+		"foo.labels",
+		"foo.message",
+	}, mappedFields)
+
+	event := common.MapStr{
+		"@timestamp": "2024-01-01T00:00:00.000Z",
+		"data_stream": common.MapStr{
+			"dataset":   "test.foo",
+			"namespace": "upgradetest",
+			"type":      "logs",
+		},
+		"foo": common.MapStr{
+			"labels":  common.MapStr{"env": "test"},
+			"message": "hello",
+			"status":  "added in the new version",
+		},
+	}
+	expected := common.MapStr{
+		"@timestamp": "2024-01-01T00:00:00.000Z",
+		"data_stream": common.MapStr{
+			"dataset":   "test.foo",
+			"namespace": "upgradetest",
+			"type":      "logs",
+		},
+		"foo": common.MapStr{
+			"labels":  common.MapStr{"env": "test"},
+			"message": "hello",
+		},
+	}
+	filtered, err := filterMappedFields(event, mappedFields)
+	require.NoError(t, err)
+	assert.Equal(t, expected, filtered)
+}
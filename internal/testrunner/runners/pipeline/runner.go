@@ -30,6 +30,8 @@ type runner struct {
 
 	failOnMissingTests bool
 	generateTestResult bool
+	explain            bool
+	nextECSReference   string
 
 	withCoverage     bool
 	coverageType     string
@@ -44,6 +46,8 @@ type PipelineTestRunnerOptions struct {
 	DataStreams        []string
 	FailOnMissingTests bool
 	GenerateTestResult bool
+	Explain            bool
+	NextECSReference   string
 	WithCoverage       bool
 	CoverageType       string
 	DeferCleanup       time.Duration
@@ -58,6 +62,8 @@ func NewPipelineTestRunner(options PipelineTestRunnerOptions) *runner {
 		dataStreams:        options.DataStreams,
 		failOnMissingTests: options.FailOnMissingTests,
 		generateTestResult: options.GenerateTestResult,
+		explain:            options.Explain,
+		nextECSReference:   options.NextECSReference,
 		withCoverage:       options.WithCoverage,
 		coverageType:       options.CoverageType,
 		deferCleanup:       options.DeferCleanup,
@@ -131,6 +137,8 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 				TestFolder:         folder,
 				PackageRootPath:    r.packageRootPath,
 				GenerateTestResult: r.generateTestResult,
+				Explain:            r.explain,
+				NextECSReference:   r.nextECSReference,
 				WithCoverage:       r.withCoverage,
 				CoverageType:       r.coverageType,
 				DeferCleanup:       r.deferCleanup,
@@ -27,6 +27,10 @@ type testConfig struct {
 	Fields        map[string]interface{} `config:"fields"`
 	DynamicFields map[string]string      `config:"dynamic_fields"`
 
+	// InputFiles holds a list of glob patterns, relative to the test case file,
+	// of additional input files whose entries are appended to the test case.
+	InputFiles []string `config:"input_files"`
+
 	// NumericKeywordFields holds a list of fields that have keyword
 	// type but can be ingested as numeric type.
 	NumericKeywordFields []string `config:"numeric_keyword_fields"`
@@ -34,6 +38,10 @@ type testConfig struct {
 	// StringNumberFields holds a list of fields that have numeric
 	// types but can be ingested as strings.
 	StringNumberFields []string `config:"string_number_fields"`
+
+	// SyntheticSource enables validation of the documents as they would be returned
+	// by Elasticsearch when synthetic source is enabled.
+	SyntheticSource bool `config:"synthetic_source"`
 }
 
 type multiline struct {
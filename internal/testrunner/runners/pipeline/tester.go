@@ -43,6 +43,8 @@ type tester struct {
 	packageRootPath    string
 	testFolder         testrunner.TestFolder
 	generateTestResult bool
+	explain            bool
+	nextECSReference   string
 	withCoverage       bool
 	coverageType       string
 	globalTestConfig   testrunner.GlobalRunnerTestConfig
@@ -63,6 +65,8 @@ type PipelineTesterOptions struct {
 	PackageRootPath    string
 	TestFolder         testrunner.TestFolder
 	GenerateTestResult bool
+	Explain            bool
+	NextECSReference   string
 	WithCoverage       bool
 	CoverageType       string
 	TestCaseFile       string
@@ -78,6 +82,8 @@ func NewPipelineTester(options PipelineTesterOptions) (*tester, error) {
 		testFolder:         options.TestFolder,
 		testCaseFile:       options.TestCaseFile,
 		generateTestResult: options.GenerateTestResult,
+		explain:            options.Explain,
+		nextECSReference:   options.NextECSReference,
 		withCoverage:       options.WithCoverage,
 		coverageType:       options.CoverageType,
 		globalTestConfig:   options.GlobalTestConfig,
@@ -213,8 +219,13 @@ func (r *tester) run(ctx context.Context) ([]testrunner.TestResult, error) {
 		// since system tests can have dynamic public IPs
 		fields.WithEnabledAllowedIPCheck(),
 		fields.WithExpectedDatasets(expectedDatasets),
+		fields.WithDatasetMismatchAsWarning(r.globalTestConfig.AllowDatasetMismatch),
+		fields.WithStrictExpectedDatasets(r.globalTestConfig.StrictExpectedDatasets),
 		fields.WithEnabledImportAllECSSChema(true),
 	}
+	if r.nextECSReference != "" {
+		validatorOptions = append(validatorOptions, fields.WithNextECSReference(r.nextECSReference))
+	}
 	result, err := r.runTestCase(ctx, r.testCaseFile, dataStreamPath, dsManifest.Type, entryPipeline, validatorOptions)
 	if err != nil {
 		return nil, err
@@ -334,6 +345,8 @@ func (r *tester) runTestCase(ctx context.Context, testCaseFile string, dsPath st
 	validatorOptions = append(slices.Clone(validatorOptions),
 		fields.WithNumericKeywordFields(tc.config.NumericKeywordFields),
 		fields.WithStringNumberFields(tc.config.StringNumberFields),
+		fields.WithDisableNormalization(tc.config.SyntheticSource),
+		fields.WithExplanations(r.explain),
 	)
 	fieldsValidator, err := fields.CreateValidatorForDirectory(dsPath, validatorOptions...)
 	if err != nil {
@@ -382,28 +395,75 @@ func loadTestCaseFile(testFolderPath, testCaseFile string) (*testCase, error) {
 	}
 
 	ext := filepath.Ext(testCaseFile)
+	entries, err := readTestCaseEntries(testCasePath, ext, testCaseData, config)
+	if err != nil {
+		return nil, err
+	}
 
-	var entries []json.RawMessage
+	inputFiles, err := findTestCaseInputFiles(testFolderPath, config.InputFiles)
+	if err != nil {
+		return nil, fmt.Errorf("can't find input files of test case (testCasePath: %s): %w", testCasePath, err)
+	}
+	for _, inputFile := range inputFiles {
+		if filepath.Ext(inputFile) != ext {
+			return nil, fmt.Errorf("input file %s must have the same extension as the test case file (ext: %s)", inputFile, ext)
+		}
+		inputData, err := os.ReadFile(inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading input file failed (path: %s): %w", inputFile, err)
+		}
+		inputEntries, err := readTestCaseEntries(inputFile, ext, inputData, config)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, inputEntries...)
+	}
+
+	tc, err := createTestCase(testCaseFile, entries, config)
+	if err != nil {
+		return nil, fmt.Errorf("can't create test case (testCasePath: %s): %w", testCasePath, err)
+	}
+	return tc, nil
+}
+
+func readTestCaseEntries(path string, ext string, data []byte, config *testConfig) ([]json.RawMessage, error) {
 	switch ext {
 	case ".json":
-		entries, err = readTestCaseEntriesForEvents(testCaseData)
+		entries, err := readTestCaseEntriesForEvents(data)
 		if err != nil {
-			return nil, fmt.Errorf("reading test case entries for events failed (testCasePath: %s): %w", testCasePath, err)
+			return nil, fmt.Errorf("reading test case entries for events failed (testCasePath: %s): %w", path, err)
 		}
+		return entries, nil
 	case ".log":
-		entries, err = readTestCaseEntriesForRawInput(testCaseData, config)
+		entries, err := readTestCaseEntriesForRawInput(data, config)
 		if err != nil {
-			return nil, fmt.Errorf("creating test case entries for raw input failed (testCasePath: %s): %w", testCasePath, err)
+			return nil, fmt.Errorf("creating test case entries for raw input failed (testCasePath: %s): %w", path, err)
 		}
+		return entries, nil
 	default:
 		return nil, fmt.Errorf("unsupported extension for test case file (ext: %s)", ext)
 	}
+}
 
-	tc, err := createTestCase(testCaseFile, entries, config)
-	if err != nil {
-		return nil, fmt.Errorf("can't create test case (testCasePath: %s): %w", testCasePath, err)
+// findTestCaseInputFiles returns the files matching the given patterns. Files are returned in the order
+// of the patterns, and files matching the same pattern are sorted by name.
+func findTestCaseInputFiles(testFolderPath string, patterns []string) ([]string, error) {
+	var inputFiles []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(testFolderPath, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no input files found for pattern %q", pattern)
+		}
+		for _, match := range matches {
+			if !slices.Contains(inputFiles, match) {
+				inputFiles = append(inputFiles, match)
+			}
+		}
 	}
-	return tc, nil
+	return inputFiles, nil
 }
 
 func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *testResult, fieldsValidator *fields.Validator) error {
@@ -436,6 +496,13 @@ func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *
 		}
 	}
 
+	if r.globalTestConfig.NormalizedExpectedResults {
+		err = checkExpectedTestResultNormalized(testCasePath, *specVersion)
+		if err != nil {
+			return err
+		}
+	}
+
 	result = stripEmptyTestResults(result)
 
 	err = verifyDynamicFields(result, config)
@@ -443,6 +510,13 @@ func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *
 		return err
 	}
 
+	if config.SyntheticSource {
+		result, err = syntheticSourceTestResult(result, fieldsValidator)
+		if err != nil {
+			return fmt.Errorf("failed to normalize documents for synthetic source: %w", err)
+		}
+	}
+
 	err = verifyFieldsInTestResult(result, fieldsValidator)
 	if err != nil {
 		return err
@@ -450,6 +524,35 @@ func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *
 	return nil
 }
 
+// syntheticSourceTestResult converts the documents to the form Elasticsearch returns when synthetic
+// source is enabled, using the same normalization used to validate documents in system tests.
+func syntheticSourceTestResult(result *testResult, fieldsValidator *fields.Validator) (*testResult, error) {
+	var docs []common.MapStr
+	for _, event := range result.events {
+		var m common.MapStr
+		err := formatter.JSONUnmarshalUsingNumber(event, &m)
+		if err != nil {
+			return nil, fmt.Errorf("can't unmarshal event: %w", err)
+		}
+		docs = append(docs, m.Flatten())
+	}
+
+	docs, err := fieldsValidator.SanitizeSyntheticSourceDocs(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	var tr testResult
+	for _, doc := range docs {
+		event, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("can't marshal event: %w", err)
+		}
+		tr.events = append(tr.events, event)
+	}
+	return &tr, nil
+}
+
 // stripEmptyTestResults function removes events which are nils. These nils can represent
 // documents processed by a pipeline which potentially used a "drop" processor (to drop the event at all).
 func stripEmptyTestResults(result *testResult) *testResult {
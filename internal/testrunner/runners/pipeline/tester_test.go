@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestLoadTestCaseFileWithInputFiles(t *testing.T) {
+	testFolder := t.TempDir()
+	filestest.WriteFile(t, testFolder, "test-sample.log", "first\n")
+	filestest.WriteFile(t, testFolder, "test-sample.log-config.yml", "input_files:\n  - corpus/part-*.log\n  - extra.log\n")
+	filestest.WriteFile(t, testFolder, "corpus/part-2.log", "third\n")
+	filestest.WriteFile(t, testFolder, "corpus/part-1.log", "second\n")
+	filestest.WriteFile(t, testFolder, "extra.log", "fourth\nfifth\n")
+
+	tc, err := loadTestCaseFile(testFolder, "test-sample.log")
+	require.NoError(t, err)
+
+	var messages []string
+	for _, event := range tc.events {
+		var m struct {
+			Message string `json:"message"`
+		}
+		require.NoError(t, json.Unmarshal(event, &m))
+		messages = append(messages, m.Message)
+	}
+	assert.Equal(t, []string{"first", "second", "third", "fourth", "fifth"}, messages)
+}
+
+func TestLoadTestCaseFileWithInputFilesErrors(t *testing.T) {
+	cases := []struct {
+		title  string
+		config string
+		files  map[string]string
+	}{
+		{
+			title:  "no matches",
+			config: "input_files:\n  - missing-*.log\n",
+		},
+		{
+			title:  "different extension",
+			config: "input_files:\n  - events.json\n",
+			files:  map[string]string{"events.json": `{"events": []}`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			testFolder := t.TempDir()
+			filestest.WriteFile(t, testFolder, "test-sample.log", "first\n")
+			filestest.WriteFile(t, testFolder, "test-sample.log-config.yml", c.config)
+			for name, content := range c.files {
+				filestest.WriteFile(t, testFolder, name, content)
+			}
+
+			_, err := loadTestCaseFile(testFolder, "test-sample.log")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -80,6 +80,44 @@ func compareResults(testCasePath string, config *testConfig, result *testResult,
 	return nil
 }
 
+// checkExpectedTestResultNormalized checks that the expected test result file has the same content
+// as when it is generated, so regenerating it doesn't introduce unrelated changes.
+func checkExpectedTestResultNormalized(testCasePath string, specVersion semver.Version) error {
+	path := filepath.Join(filepath.Dir(testCasePath), expectedTestResultFile(filepath.Base(testCasePath)))
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading test result file failed: %w", err)
+	}
+
+	normalized, err := isTestResultNormalized(content, specVersion)
+	if err != nil {
+		return err
+	}
+	if !normalized {
+		return testrunner.ErrTestCaseFailed{
+			Reason:  "Expected results file is not normalized",
+			Details: fmt.Sprintf("file %s differs from the output of the formatter, regenerate it with --generate or format it", path),
+		}
+	}
+	return nil
+}
+
+// isTestResultNormalized checks if the content of an expected test result file is the same as the
+// one written when test results are generated.
+func isTestResultNormalized(content []byte, specVersion semver.Version) (bool, error) {
+	result, err := unmarshalTestResult(content)
+	if err != nil {
+		return false, err
+	}
+
+	normalized, err := marshalTestResultDefinition(result, specVersion)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(content, append(normalized, '\n')), nil
+}
+
 func compareJsonNumbers(a, b json.Number) bool {
 	if a == b {
 		// Equal literals, so they are the same.
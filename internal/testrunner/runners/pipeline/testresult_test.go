@@ -6,9 +6,15 @@ package pipeline
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
 func TestCompareJsonNumber(t *testing.T) {
@@ -43,3 +49,20 @@ func TestCompareJsonNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckExpectedTestResultNormalized(t *testing.T) {
+	specVersion := *semver.MustParse("3.0.0")
+	testCasePath := filepath.Join(t.TempDir(), "test-access.log")
+	result := &testResult{events: []json.RawMessage{
+		json.RawMessage(`{"message":"GET /","@timestamp":"2024-01-01T00:00:00.000Z","http":{"response":{"status_code":200}}}`),
+	}}
+
+	err := writeTestResult(testCasePath, result, specVersion)
+	require.NoError(t, err)
+	assert.NoError(t, checkExpectedTestResultNormalized(testCasePath, specVersion))
+
+	err = os.WriteFile(testCasePath+expectedTestResultSuffix, []byte(`{"expected":[{"message":"GET /"}]}`), 0644)
+	require.NoError(t, err)
+	err = checkExpectedTestResultNormalized(testCasePath, specVersion)
+	assert.ErrorAs(t, err, &testrunner.ErrTestCaseFailed{})
+}
@@ -105,6 +105,14 @@ func (r *tester) runTest(ctx context.Context, manager *resources.Manager, testPa
 		logger.Warnf("skipping %s test for %s/%s: %s (details: %s)",
 			TestType, r.testFolder.Package, r.testFolder.DataStream,
 			skip.Reason, skip.Link)
+		if r.withCoverage {
+			// Report the files of skipped tests as not covered, so they are still accounted for in coverage totals.
+			coverage, err := generateCoverageReport(result.CoveragePackageName(), r.packageRootPath, r.testFolder.DataStream, r.coverageType, false)
+			if err != nil {
+				return result.WithErrorf("coverage report generation failed: %w", err)
+			}
+			result = result.WithCoverage(coverage)
+		}
 		return result.WithSkip(skip)
 	}
 
@@ -143,7 +151,7 @@ func (r *tester) runTest(ctx context.Context, manager *resources.Manager, testPa
 	}
 
 	if r.withCoverage {
-		coverage, err := generateCoverageReport(result.CoveragePackageName(), r.packageRootPath, r.testFolder.DataStream, r.coverageType)
+		coverage, err := generateCoverageReport(result.CoveragePackageName(), r.packageRootPath, r.testFolder.DataStream, r.coverageType, true)
 		if err != nil {
 			return result.WithErrorf("coverage report generation failed: %w", err)
 		}
@@ -156,10 +164,11 @@ func (r *tester) runTest(ctx context.Context, manager *resources.Manager, testPa
 	return result.WithSuccess()
 }
 
-// generateCoverageReport generates a coverage report that includes the manifests and template files in the package or data stream.
+// generateCoverageReport generates a coverage report that includes the manifests and template files in the package or data stream,
+// marked as covered or uncovered depending on the given value.
 // TODO: For manifests, mark as covered only the variables used.
 // TODO: For templates, mark as covered only the parts used, but this requires introspection in handlebars.
-func generateCoverageReport(pkgName, rootPath, dataStream, coverageType string) (testrunner.CoverageReport, error) {
+func generateCoverageReport(pkgName, rootPath, dataStream, coverageType string, covered bool) (testrunner.CoverageReport, error) {
 	dsPattern := "*"
 	if dataStream != "" {
 		dsPattern = dataStream
@@ -173,7 +182,7 @@ func generateCoverageReport(pkgName, rootPath, dataStream, coverageType string)
 		filepath.Join(rootPath, "data_stream", dsPattern, "agent", "stream", "*.yml.hbs"),
 	}
 
-	return testrunner.GenerateBaseFileCoverageReportGlob(pkgName, patterns, coverageType, true)
+	return testrunner.GenerateBaseFileCoverageReportGlob(pkgName, patterns, coverageType, covered)
 }
 
 func testNameFromPath(path string) string {
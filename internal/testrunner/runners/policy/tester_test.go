@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package policy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestGenerateCoverageReport(t *testing.T) {
+	rootPath, err := filepath.Abs(filepath.Join("..", "..", "..", "..", "test", "packages", "parallel", "nginx"))
+	require.NoError(t, err)
+
+	for _, covered := range []bool{true, false} {
+		coverage, err := generateCoverageReport("nginx.access", rootPath, "access", "generic", covered)
+		require.NoError(t, err)
+
+		report, ok := coverage.(*testrunner.GenericCoverage)
+		require.True(t, ok)
+		require.NotEmpty(t, report.Files)
+		for _, file := range report.Files {
+			require.NotEmpty(t, file.Lines, file.Path)
+			for _, line := range file.Lines {
+				assert.Equal(t, covered, line.Covered, "%s:%d", file.Path, line.LineNumber)
+			}
+		}
+	}
+}
@@ -5,14 +5,19 @@
 package static
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/Masterminds/semver/v3"
+
 	"github.com/elastic/elastic-package/internal/benchrunner/runners/stream"
+	"github.com/elastic/elastic-package/internal/common"
 	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/formatter"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/signal"
@@ -97,7 +102,11 @@ func (r tester) run(ctx context.Context) ([]testrunner.TestResult, error) {
 	}
 
 	// join together results from verifyStreamConfig and verifySampleEvent
-	return append(r.verifyStreamConfig(ctx, r.packageRootPath), r.verifySampleEvent(pkgManifest)...), nil
+	results := append(r.verifyStreamConfig(ctx, r.packageRootPath), r.verifySampleEvent(pkgManifest)...)
+	if r.globalTestConfig.NormalizedSampleEvent {
+		results = append(results, r.verifySampleEventNormalized(pkgManifest)...)
+	}
+	return results, nil
 }
 
 func (r tester) verifyStreamConfig(ctx context.Context, packageRootPath string) []testrunner.TestResult {
@@ -165,6 +174,8 @@ func (r tester) verifySampleEvent(pkgManifest *packages.PackageManifest) []testr
 		fields.WithSpecVersion(pkgManifest.SpecVersion),
 		fields.WithDefaultNumericConversion(),
 		fields.WithExpectedDatasets(expectedDatasets),
+		fields.WithDatasetMismatchAsWarning(r.globalTestConfig.AllowDatasetMismatch),
+		fields.WithStrictExpectedDatasets(r.globalTestConfig.StrictExpectedDatasets),
 		fields.WithEnabledImportAllECSSChema(true),
 	)
 	if err != nil {
@@ -191,6 +202,71 @@ func (r tester) verifySampleEvent(pkgManifest *packages.PackageManifest) []testr
 	return results
 }
 
+func (r tester) verifySampleEventNormalized(pkgManifest *packages.PackageManifest) []testrunner.TestResult {
+	resultComposer := testrunner.NewResultComposer(testrunner.TestResult{
+		Name:       "Verify " + sampleEventJSON + " is normalized",
+		TestType:   TestType,
+		Package:    r.testFolder.Package,
+		DataStream: r.testFolder.DataStream,
+	})
+
+	sampleEventPath, found, err := r.getSampleEventPath()
+	if err != nil {
+		results, _ := resultComposer.WithError(err)
+		return results
+	}
+	if !found {
+		// Nothing to do.
+		return []testrunner.TestResult{}
+	}
+
+	specVersion, err := semver.NewVersion(pkgManifest.SpecVersion)
+	if err != nil {
+		results, _ := resultComposer.WithErrorf("failed to parse format version %q: %w", pkgManifest.SpecVersion, err)
+		return results
+	}
+
+	content, err := os.ReadFile(sampleEventPath)
+	if err != nil {
+		results, _ := resultComposer.WithError(fmt.Errorf("can't read file: %w", err))
+		return results
+	}
+
+	normalized, err := isSampleEventNormalized(content, *specVersion)
+	if err != nil {
+		results, _ := resultComposer.WithError(err)
+		return results
+	}
+	if !normalized {
+		results, _ := resultComposer.WithError(testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("%s is not normalized", sampleEventJSON),
+			Details: fmt.Sprintf("file %s differs from the output of the formatter, regenerate it with system tests or format it", sampleEventPath),
+		})
+		return results
+	}
+
+	results, _ := resultComposer.WithSuccess()
+	return results
+}
+
+// isSampleEventNormalized checks if the content of the sample event is the same as the one written
+// when the sample event is generated.
+func isSampleEventNormalized(content []byte, specVersion semver.Version) (bool, error) {
+	var doc common.MapStr
+	err := formatter.JSONUnmarshalUsingNumber(content, &doc)
+	if err != nil {
+		return false, fmt.Errorf("can't unmarshal sample event: %w", err)
+	}
+
+	jsonFormatter := formatter.JSONFormatterBuilder(specVersion)
+	normalized, err := jsonFormatter.Encode(doc)
+	if err != nil {
+		return false, fmt.Errorf("can't encode sample event: %w", err)
+	}
+
+	return bytes.Equal(content, append(normalized, '\n')), nil
+}
+
 func (r tester) getSampleEventPath() (string, bool, error) {
 	var sampleEventPath string
 	if r.testFolder.DataStream != "" {
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package static
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSampleEventNormalized(t *testing.T) {
+	cases := []struct {
+		title       string
+		content     string
+		specVersion string
+		expected    bool
+	}{
+		{
+			title:       "normalized",
+			content:     "{\n    \"a\": 1,\n    \"b\": {\n        \"c\": \"<d>\"\n    }\n}\n",
+			specVersion: "3.0.0",
+			expected:    true,
+		},
+		{
+			title:       "unsorted keys",
+			content:     "{\n    \"b\": {\n        \"c\": \"<d>\"\n    },\n    \"a\": 1\n}\n",
+			specVersion: "3.0.0",
+			expected:    false,
+		},
+		{
+			title:       "missing trailing new line",
+			content:     "{\n    \"a\": 1\n}",
+			specVersion: "3.0.0",
+			expected:    false,
+		},
+		{
+			title:       "html characters not escaped in old spec versions",
+			content:     "{\n    \"c\": \"<d>\"\n}\n",
+			specVersion: "2.11.0",
+			expected:    false,
+		},
+		{
+			title:       "html characters escaped in old spec versions",
+			content:     "{\n    \"c\": \"\\u003cd\\u003e\"\n}\n",
+			specVersion: "2.11.0",
+			expected:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			normalized, err := isSampleEventNormalized([]byte(c.content), *semver.MustParse(c.specVersion))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, normalized)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+// logLevelWarning is the level of the warning messages in the Elastic Agent logs.
+const logLevelWarning = "warn"
+
+// agentLogPatterns returns the patterns of the log messages that make tests fail. Warning messages
+// of the Elastic Agent are only included when the tester is configured to fail on them.
+func (r *tester) agentLogPatterns() ([]logsByContainer, error) {
+	if !r.failOnAgentWarnings {
+		return errorPatterns, nil
+	}
+
+	warningPatterns, err := agentWarningPatterns(r.globalTestConfig.AgentWarnings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent warnings configuration: %w", err)
+	}
+
+	patterns := slices.Clone(errorPatterns)
+	for i, container := range patterns {
+		if container.containerName != "elastic-agent" {
+			continue
+		}
+		patterns[i].patterns = append(slices.Clone(container.patterns), warningPatterns...)
+	}
+	return patterns, nil
+}
+
+// agentWarningPatterns builds the patterns to look for warning messages in logs. All warning messages
+// are matched if no includes are configured.
+func agentWarningPatterns(config testrunner.AgentWarningsConfig) ([]logsRegexp, error) {
+	var excludes []*regexp.Regexp
+	for _, exclude := range config.Excludes {
+		re, err := regexp.Compile(exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", exclude, err)
+		}
+		excludes = append(excludes, re)
+	}
+
+	includes := config.Includes
+	if len(includes) == 0 {
+		includes = []string{".*"}
+	}
+
+	var patterns []logsRegexp
+	for _, include := range includes {
+		re, err := regexp.Compile(include)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", include, err)
+		}
+		patterns = append(patterns, logsRegexp{
+			includes: re,
+			excludes: excludes,
+			level:    logLevelWarning,
+		})
+	}
+	return patterns, nil
+}
@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestAgentLogPatterns(t *testing.T) {
+	t.Run("errors only by default", func(t *testing.T) {
+		r := tester{}
+		patterns, err := r.agentLogPatterns()
+		require.NoError(t, err)
+		assert.Equal(t, errorPatterns, patterns)
+	})
+
+	t.Run("all warnings", func(t *testing.T) {
+		r := tester{failOnAgentWarnings: true}
+		patterns, err := r.agentLogPatterns()
+		require.NoError(t, err)
+		require.Len(t, patterns, 1)
+
+		agentPatterns := patterns[0].patterns
+		require.Len(t, agentPatterns, len(errorPatterns[0].patterns)+1)
+		warnings := agentPatterns[len(agentPatterns)-1]
+		assert.Equal(t, logLevelWarning, warnings.level)
+		assert.Equal(t, ".*", warnings.includes.String())
+		assert.Empty(t, warnings.excludes)
+
+		// Default error patterns are not modified.
+		assert.Len(t, errorPatterns[0].patterns, len(agentPatterns)-1)
+	})
+
+	t.Run("configured warnings", func(t *testing.T) {
+		r := tester{
+			failOnAgentWarnings: true,
+			globalTestConfig: testrunner.GlobalRunnerTestConfig{
+				AgentWarnings: testrunner.AgentWarningsConfig{
+					Includes: []string{"^Failed", "retrying"},
+					Excludes: []string{"connection refused"},
+				},
+			},
+		}
+		patterns, err := r.agentLogPatterns()
+		require.NoError(t, err)
+
+		agentPatterns := patterns[0].patterns
+		warnings := agentPatterns[len(errorPatterns[0].patterns):]
+		require.Len(t, warnings, 2)
+		assert.Equal(t, "^Failed", warnings[0].includes.String())
+		assert.Equal(t, "retrying", warnings[1].includes.String())
+		for _, warning := range warnings {
+			assert.Equal(t, logLevelWarning, warning.level)
+			require.Len(t, warning.excludes, 1)
+			assert.Equal(t, "connection refused", warning.excludes[0].String())
+		}
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		r := tester{
+			failOnAgentWarnings: true,
+			globalTestConfig: testrunner.GlobalRunnerTestConfig{
+				AgentWarnings: testrunner.AgentWarningsConfig{
+					Excludes: []string{"("},
+				},
+			},
+		}
+		_, err := r.agentLogPatterns()
+		assert.ErrorContains(t, err, `invalid exclude pattern "("`)
+	})
+}
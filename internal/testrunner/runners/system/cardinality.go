@@ -0,0 +1,127 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// cardinalityMaxBuckets is the maximum number of unique values retrieved for each field when
+// checking its cardinality.
+const cardinalityMaxBuckets = 10000
+
+// cardinalityLimits returns the maximum number of unique values expected for each field, as
+// configured in assert.cardinality. Field names can be dotted or nested in the configuration.
+func cardinalityLimits(config common.MapStr) (map[string]int, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+	limits := make(map[string]int)
+	for field, value := range config.Flatten() {
+		var limit float64
+		switch value := value.(type) {
+		case int:
+			limit = float64(value)
+		case int64:
+			limit = float64(value)
+		case uint64:
+			limit = float64(value)
+		case float64:
+			limit = value
+		default:
+			return nil, fmt.Errorf("expected number of unique values for field %q must be a number, found %v", field, value)
+		}
+		if limit < 1 || limit != math.Trunc(limit) || limit > cardinalityMaxBuckets {
+			return nil, fmt.Errorf("expected number of unique values for field %q must be an integer between 1 and %d, found %v", field, cardinalityMaxBuckets, value)
+		}
+		limits[field] = int(limit)
+	}
+	return limits, nil
+}
+
+// assertCardinality checks that the fields don't have more unique values than expected in the
+// documents of the data stream.
+func (r *tester) assertCardinality(ctx context.Context, dataStream string, limits map[string]int) (pass bool, message string, err error) {
+	fields := make([]string, 0, len(limits))
+	for field := range limits {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	var failures []string
+	for _, field := range fields {
+		observed, complete, err := r.countUniqueValues(ctx, dataStream, field)
+		if err != nil {
+			return false, "", err
+		}
+		limit := limits[field]
+		logger.Debugf("assert cardinality of field %q, observed %d unique values (expected at most %d)", field, observed, limit)
+		switch {
+		case !complete:
+			failures = append(failures, fmt.Sprintf("observed more than %d unique values in field %q, expected at most %d", observed, field, limit))
+		case observed > limit:
+			failures = append(failures, fmt.Sprintf("observed %d unique values in field %q, expected at most %d", observed, field, limit))
+		}
+	}
+	if len(failures) > 0 {
+		return false, strings.Join(failures, "; "), nil
+	}
+	return true, "", nil
+}
+
+// countUniqueValues counts the unique values of the field in the data stream with a terms
+// aggregation. If there are more values than the ones retrieved, complete is false.
+func (r *tester) countUniqueValues(ctx context.Context, dataStream, field string) (count int, complete bool, err error) {
+	body, err := json.Marshal(map[string]any{
+		"size": 0,
+		"aggs": map[string]any{
+			"values": map[string]any{
+				"terms": map[string]any{
+					"field": field,
+					"size":  cardinalityMaxBuckets,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to encode terms aggregation: %w", err)
+	}
+	resp, err := r.esAPI.Search(
+		r.esAPI.Search.WithContext(ctx),
+		r.esAPI.Search.WithIndex(dataStream),
+		r.esAPI.Search.WithBody(strings.NewReader(string(body))),
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("could not aggregate values of field %s in data stream %s: %w", field, dataStream, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return 0, false, fmt.Errorf("could not aggregate values of field %s in data stream %s: %s", field, dataStream, resp.String())
+	}
+
+	var result struct {
+		Aggregations struct {
+			Values struct {
+				SumOtherDocCount int               `json:"sum_other_doc_count"`
+				Buckets          []json.RawMessage `json:"buckets"`
+			} `json:"values"`
+		} `json:"aggregations"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return 0, false, fmt.Errorf("could not decode terms aggregation response: %w", err)
+	}
+	values := result.Aggregations.Values
+	return len(values.Buckets), values.SumOtherDocCount == 0, nil
+}
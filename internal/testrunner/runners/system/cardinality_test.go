@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+)
+
+func TestNewConfigCardinality(t *testing.T) {
+	cases := []struct {
+		title       string
+		assert      string
+		expected    map[string]int
+		expectError bool
+	}{
+		{
+			title:    "dotted fields",
+			assert:   "{event.outcome: 3, http.response.status_code: 20}",
+			expected: map[string]int{"event.outcome": 3, "http.response.status_code": 20},
+		},
+		{
+			title:    "nested fields",
+			assert:   "{event: {outcome: 3, type: 5}}",
+			expected: map[string]int{"event.outcome": 3, "event.type": 5},
+		},
+		{title: "not a number", assert: "{event.outcome: many}", expectError: true},
+		{title: "zero", assert: "{event.outcome: 0}", expectError: true},
+		{title: "not an integer", assert: "{event.outcome: 2.5}", expectError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", "assert:\n  cardinality: "+c.assert+"\n")
+
+			config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, config.Assert.CardinalityLimits)
+		})
+	}
+}
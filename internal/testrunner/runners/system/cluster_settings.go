@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+)
+
+// getPersistentClusterSettings returns the current persistent values of the given settings. Settings
+// that are not set are included with a nil value, so they are removed when these values are restored.
+func getPersistentClusterSettings(ctx context.Context, api *elasticsearch.API, settings common.MapStr) (common.MapStr, error) {
+	resp, err := api.Cluster.GetSettings(
+		api.Cluster.GetSettings.WithContext(ctx),
+		api.Cluster.GetSettings.WithFlatSettings(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get cluster settings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("could not get cluster settings: %s", resp.String())
+	}
+
+	var current struct {
+		Persistent map[string]any `json:"persistent"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&current)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode cluster settings: %w", err)
+	}
+
+	values := make(common.MapStr)
+	for key := range settings.Flatten() {
+		values[key] = current.Persistent[key]
+	}
+	return values, nil
+}
+
+// putPersistentClusterSettings updates the given persistent settings, nil values reset settings
+// to their defaults.
+func putPersistentClusterSettings(ctx context.Context, api *elasticsearch.API, settings common.MapStr) error {
+	body, err := json.Marshal(map[string]any{
+		"persistent": settings.Flatten(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not encode cluster settings: %w", err)
+	}
+
+	resp, err := api.Cluster.PutSettings(bytes.NewReader(body),
+		api.Cluster.PutSettings.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("could not update cluster settings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("could not update cluster settings: %s", resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/formatter"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// exportScenarioDocs writes the documents ingested during the test in the file configured to
+// export them, so they can be reused as fixtures in other tests.
+func (r *tester) exportScenarioDocs(docs []common.MapStr) error {
+	specVersion, err := semver.NewVersion(r.pkgManifest.SpecVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse format version %q: %w", r.pkgManifest.SpecVersion, err)
+	}
+
+	content, err := encodeNDJSON(docs, *specVersion)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(r.exportDocs, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write documents to %s: %w", r.exportDocs, err)
+	}
+	logger.Infof("Exported %d documents to %s", len(docs), r.exportDocs)
+	return nil
+}
+
+// encodeNDJSON encodes the documents as newline-delimited JSON. Documents are encoded with the
+// same JSON formatter used for other files of the package, so values are escaped in the same way.
+func encodeNDJSON(docs []common.MapStr, specVersion semver.Version) ([]byte, error) {
+	jsonFormatter := formatter.JSONFormatterBuilder(specVersion)
+
+	var buf bytes.Buffer
+	for i, doc := range docs {
+		encoded, err := jsonFormatter.Encode(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode document %d: %w", i, err)
+		}
+		err = json.Compact(&buf, encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compact document %d: %w", i, err)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+func TestEncodeNDJSON(t *testing.T) {
+	docs := []common.MapStr{
+		{"message": "<b>hello</b>", "source": common.MapStr{"ip": "10.0.0.1"}},
+		{"message": "bye"},
+	}
+
+	content, err := encodeNDJSON(docs, *semver.MustParse("3.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"message\":\"<b>hello</b>\",\"source\":{\"ip\":\"10.0.0.1\"}}\n{\"message\":\"bye\"}\n", string(content))
+
+	content, err = encodeNDJSON(docs, *semver.MustParse("2.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"message\":\"\\u003cb\\u003ehello\\u003c/b\\u003e\",\"source\":{\"ip\":\"10.0.0.1\"}}\n{\"message\":\"bye\"}\n", string(content))
+
+	content, err = encodeNDJSON(nil, *semver.MustParse("3.0.0"))
+	require.NoError(t, err)
+	assert.Empty(t, content)
+}
@@ -0,0 +1,191 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+	"github.com/elastic/elastic-package/internal/testrunner"
+	"github.com/elastic/elastic-package/internal/wait"
+)
+
+const (
+	beforeTestHook = "before_test"
+	afterTestHook  = "after_test"
+	waitForHook    = "wait_for"
+)
+
+// runTestHook executes a script configured as hook in the global test configuration. Scripts
+// are resolved relative to the package root, and they receive information about the service
+// as environment variables. If the script fails, the test case fails with its output.
+func runTestHook(ctx context.Context, packageRootPath, hook, script string, svcInfo servicedeployer.ServiceInfo) error {
+	if script == "" {
+		return nil
+	}
+
+	cmd := newTestHookCommand(ctx, packageRootPath, script, svcInfo)
+	output := new(bytes.Buffer)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	logger.Debugf("running %s hook: %s", hook, cmd)
+	err := cmd.Run()
+	if err != nil {
+		return testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("%s hook failed: %v", hook, err),
+			Details: output.String(),
+		}
+	}
+	return nil
+}
+
+// testHooks runs the before_test and after_test hooks around a test. The after_test hook is
+// run whatever the result of the test, so it can clean up, but only if before_test was run.
+type testHooks struct {
+	packageRootPath string
+	beforeTest      string
+	afterTest       string
+
+	// svcInfo is the service information passed to before_test, it is nil until it is run.
+	svcInfo *servicedeployer.ServiceInfo
+}
+
+func newTestHooks(packageRootPath string, config testrunner.GlobalRunnerTestConfig) *testHooks {
+	return &testHooks{
+		packageRootPath: packageRootPath,
+		beforeTest:      config.BeforeTest,
+		afterTest:       config.AfterTest,
+	}
+}
+
+// runBeforeTest runs the before_test hook, and keeps the service information for after_test.
+func (h *testHooks) runBeforeTest(ctx context.Context, svcInfo servicedeployer.ServiceInfo) error {
+	err := runTestHook(ctx, h.packageRootPath, beforeTestHook, h.beforeTest, svcInfo)
+	if err != nil {
+		return err
+	}
+	h.svcInfo = &svcInfo
+	return nil
+}
+
+// runAfterTest runs the after_test hook if before_test was run, and adds its error to the
+// results of the test.
+func (h *testHooks) runAfterTest(ctx context.Context, results []testrunner.TestResult, err error) ([]testrunner.TestResult, error) {
+	if h.svcInfo == nil {
+		return results, err
+	}
+	hookErr := runTestHook(ctx, h.packageRootPath, afterTestHook, h.afterTest, *h.svcInfo)
+	if hookErr == nil {
+		return results, err
+	}
+	if err != nil {
+		return results, errors.Join(err, hookErr)
+	}
+	return withHookError(results, hookErr)
+}
+
+// withHookError adds the error of a hook to the results of a test, keeping the failures and
+// errors already reported in them.
+func withHookError(results []testrunner.TestResult, hookErr error) ([]testrunner.TestResult, error) {
+	if len(results) == 0 {
+		return results, hookErr
+	}
+	result := &results[0]
+	var tcf testrunner.ErrTestCaseFailed
+	if errors.As(hookErr, &tcf) {
+		result.FailureMsg = joinHookMessage(result.FailureMsg, tcf.Error())
+		result.FailureDetails = joinHookMessage(result.FailureDetails, tcf.Details)
+		return results, nil
+	}
+	result.ErrorMsg = joinHookMessage(result.ErrorMsg, hookErr.Error())
+	return results, hookErr
+}
+
+func joinHookMessage(msg, hookMsg string) string {
+	if msg == "" || hookMsg == "" {
+		return msg + hookMsg
+	}
+	return msg + "\n" + hookMsg
+}
+
+// waitForTestHook polls the script configured as wait_for hook until it exits successfully.
+// If it doesn't succeed before the timeout, the test case fails with the output of the last execution.
+func waitForTestHook(ctx context.Context, packageRootPath, script string, svcInfo servicedeployer.ServiceInfo, timeout time.Duration) error {
+	if script == "" {
+		return nil
+	}
+
+	logger.Debugf("waiting for %s hook to succeed (%s)...", waitForHook, timeout)
+	var output bytes.Buffer
+	passed, err := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
+		output.Reset()
+		cmd := newTestHookCommand(ctx, packageRootPath, script, svcInfo)
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		err := cmd.Run()
+		logger.Debugf("%s hook output: %s", waitForHook, output.String())
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("%s hook failed: %w", waitForHook, err)
+		}
+		return true, nil
+	}, 1*time.Second, timeout)
+	if err != nil {
+		return err
+	}
+	if !passed {
+		return testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("%s hook didn't succeed in %s", waitForHook, timeout),
+			Details: output.String(),
+		}
+	}
+	return nil
+}
+
+func newTestHookCommand(ctx context.Context, packageRootPath, script string, svcInfo servicedeployer.ServiceInfo) *exec.Cmd {
+	if !filepath.IsAbs(script) {
+		script = filepath.Join(packageRootPath, script)
+	}
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Dir = packageRootPath
+	cmd.Env = append(os.Environ(), testHookEnv(svcInfo)...)
+	return cmd
+}
+
+func testHookEnv(svcInfo servicedeployer.ServiceInfo) []string {
+	ports := make([]string, len(svcInfo.Ports))
+	for i, port := range svcInfo.Ports {
+		ports[i] = strconv.Itoa(port)
+	}
+
+	env := []string{
+		"SERVICE_NAME=" + svcInfo.Name,
+		"SERVICE_HOSTNAME=" + svcInfo.Hostname,
+		"SERVICE_PORT=" + strconv.Itoa(svcInfo.Port),
+		"SERVICE_PORTS=" + strings.Join(ports, ","),
+		"SERVICE_LOGS_DIR=" + svcInfo.Logs.Folder.Local,
+		"TEST_RUN_ID=" + svcInfo.Test.RunID,
+	}
+	for name, value := range svcInfo.CustomProperties {
+		env = append(env, fmt.Sprintf("%s=%v", name, value))
+	}
+	return env
+}
@@ -0,0 +1,181 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestRunTestHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts not supported on windows")
+	}
+
+	packageRoot := t.TempDir()
+	writeScript := func(name, content string) {
+		err := os.WriteFile(filepath.Join(packageRoot, name), []byte("#!/bin/sh\n"+content), 0o755)
+		require.NoError(t, err)
+	}
+	writeScript("ok.sh", `echo "$SERVICE_HOSTNAME:$SERVICE_PORTS" > hook.out`)
+	writeScript("fail.sh", "echo something went wrong\nexit 1")
+
+	var svcInfo servicedeployer.ServiceInfo
+	svcInfo.Hostname = "svc-host"
+	svcInfo.Ports = []int{8080, 9090}
+
+	t.Run("not configured", func(t *testing.T) {
+		err := runTestHook(context.Background(), packageRoot, beforeTestHook, "", svcInfo)
+		assert.NoError(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		err := runTestHook(context.Background(), packageRoot, beforeTestHook, "ok.sh", svcInfo)
+		require.NoError(t, err)
+
+		out, err := os.ReadFile(filepath.Join(packageRoot, "hook.out"))
+		require.NoError(t, err)
+		assert.Equal(t, "svc-host:8080,9090\n", string(out))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		err := runTestHook(context.Background(), packageRoot, afterTestHook, "fail.sh", svcInfo)
+		var failed testrunner.ErrTestCaseFailed
+		require.ErrorAs(t, err, &failed)
+		assert.Contains(t, failed.Reason, "after_test hook failed")
+		assert.Equal(t, "something went wrong\n", failed.Details)
+	})
+}
+
+func TestWaitForTestHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts not supported on windows")
+	}
+
+	packageRoot := t.TempDir()
+	err := os.WriteFile(filepath.Join(packageRoot, "wait.sh"), []byte("#!/bin/sh\n"+
+		"echo attempt >> attempts\n"+
+		"[ $(wc -l < attempts) -ge 2 ]\n"), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(packageRoot, "never.sh"), []byte("#!/bin/sh\necho not ready\nexit 1\n"), 0o755)
+	require.NoError(t, err)
+
+	var svcInfo servicedeployer.ServiceInfo
+
+	t.Run("succeeds after retrying", func(t *testing.T) {
+		err := waitForTestHook(context.Background(), packageRoot, "wait.sh", svcInfo, 10*time.Second)
+		assert.NoError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := waitForTestHook(context.Background(), packageRoot, "never.sh", svcInfo, 100*time.Millisecond)
+		var failed testrunner.ErrTestCaseFailed
+		require.ErrorAs(t, err, &failed)
+		assert.Equal(t, "not ready\n", failed.Details)
+	})
+}
+
+func TestTestHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts not supported on windows")
+	}
+
+	packageRoot := t.TempDir()
+	writeScript := func(name, content string) {
+		err := os.WriteFile(filepath.Join(packageRoot, name), []byte("#!/bin/sh\n"+content), 0o755)
+		require.NoError(t, err)
+	}
+	writeScript("before.sh", "echo before >> hooks.out")
+	writeScript("after.sh", `echo "after $SERVICE_HOSTNAME" >> hooks.out`)
+	writeScript("fail.sh", "exit 1")
+
+	var svcInfo servicedeployer.ServiceInfo
+	svcInfo.Hostname = "svc-host"
+
+	readHooksOutput := func(t *testing.T) string {
+		out, err := os.ReadFile(filepath.Join(packageRoot, "hooks.out"))
+		if errors.Is(err, os.ErrNotExist) {
+			return ""
+		}
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(filepath.Join(packageRoot, "hooks.out")))
+		return string(out)
+	}
+
+	t.Run("scenario fails after before_test", func(t *testing.T) {
+		hooks := newTestHooks(packageRoot, testrunner.GlobalRunnerTestConfig{BeforeTest: "before.sh", AfterTest: "after.sh"})
+		require.NoError(t, hooks.runBeforeTest(context.Background(), svcInfo))
+
+		scenarioErr := testrunner.ErrTestCaseFailed{Reason: "could not find hits in logs-test-default data stream"}
+		results := []testrunner.TestResult{{Name: "test", FailureMsg: scenarioErr.Error()}}
+		results, err := hooks.runAfterTest(context.Background(), results, nil)
+		require.NoError(t, err)
+		assert.Equal(t, scenarioErr.Error(), results[0].FailureMsg)
+		assert.Equal(t, "before\nafter svc-host\n", readHooksOutput(t))
+	})
+
+	t.Run("before_test not run", func(t *testing.T) {
+		hooks := newTestHooks(packageRoot, testrunner.GlobalRunnerTestConfig{BeforeTest: "before.sh", AfterTest: "after.sh"})
+		_, err := hooks.runAfterTest(context.Background(), nil, errors.New("can't set up agent"))
+		require.Error(t, err)
+		assert.Empty(t, readHooksOutput(t))
+	})
+
+	t.Run("before_test fails", func(t *testing.T) {
+		hooks := newTestHooks(packageRoot, testrunner.GlobalRunnerTestConfig{BeforeTest: "fail.sh", AfterTest: "after.sh"})
+		err := hooks.runBeforeTest(context.Background(), svcInfo)
+		require.Error(t, err)
+		_, err = hooks.runAfterTest(context.Background(), nil, err)
+		require.Error(t, err)
+		assert.Empty(t, readHooksOutput(t))
+	})
+
+	t.Run("after_test fails", func(t *testing.T) {
+		hooks := newTestHooks(packageRoot, testrunner.GlobalRunnerTestConfig{AfterTest: "fail.sh"})
+		require.NoError(t, hooks.runBeforeTest(context.Background(), svcInfo))
+		results, err := hooks.runAfterTest(context.Background(), []testrunner.TestResult{{Name: "test"}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "test case failed: after_test hook failed: exit status 1", results[0].FailureMsg)
+	})
+}
+
+func TestWithHookError(t *testing.T) {
+	hookErr := testrunner.ErrTestCaseFailed{Reason: "after_test hook failed: exit status 1", Details: "cleanup failed\n"}
+
+	t.Run("keeps test failure", func(t *testing.T) {
+		results := []testrunner.TestResult{{Name: "test", FailureMsg: "test case failed: found 1 validation error", FailureDetails: "field error"}}
+		results, err := withHookError(results, hookErr)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "test case failed: found 1 validation error\ntest case failed: after_test hook failed: exit status 1", results[0].FailureMsg)
+		assert.Equal(t, "field error\ncleanup failed\n", results[0].FailureDetails)
+	})
+
+	t.Run("successful test", func(t *testing.T) {
+		results, err := withHookError([]testrunner.TestResult{{Name: "test"}}, hookErr)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "test case failed: after_test hook failed: exit status 1", results[0].FailureMsg)
+	})
+
+	t.Run("hook error", func(t *testing.T) {
+		results := []testrunner.TestResult{{Name: "test", FailureMsg: "test case failed"}}
+		results, err := withHookError(results, errors.New("can't run hook"))
+		require.Error(t, err)
+		assert.Equal(t, "test case failed", results[0].FailureMsg)
+		assert.Equal(t, "can't run hook", results[0].ErrorMsg)
+	})
+}
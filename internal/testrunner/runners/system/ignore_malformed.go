@@ -0,0 +1,157 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+// ignoreMalformedTypes are the field types that support the ignore_malformed mapping parameter.
+var ignoreMalformedTypes = []string{
+	"byte", "double", "float", "half_float", "integer", "long", "scaled_float", "short", "unsigned_long",
+	"date", "date_nanos",
+	"geo_point", "geo_shape",
+	"ip",
+}
+
+// validateIgnoreMalformedFields looks for fields with ignore_malformed enabled that ignored values
+// during the test. Values of these fields are silently dropped, so they are reported even if the
+// fields are listed in skip_ignored_fields. They are reported as warnings, unless the tester is
+// configured to fail on them.
+func (r *tester) validateIgnoreMalformedFields(ctx context.Context, scenario *scenarioTest) error {
+	if len(scenario.ignoredFields) == 0 {
+		return nil
+	}
+
+	_, properties, err := r.esClient.DataStreamMappings(ctx, scenario.dataStream)
+	if err != nil {
+		return fmt.Errorf("failed to get mappings of data stream %s: %w", scenario.dataStream, err)
+	}
+	var mappings map[string]any
+	err = json.Unmarshal(properties, &mappings)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal mappings of data stream %s: %w", scenario.dataStream, err)
+	}
+
+	indexDefault, err := getIndexIgnoreMalformed(ctx, r.esAPI, scenario.dataStream)
+	if err != nil {
+		return err
+	}
+
+	malformed := findIgnoreMalformedFields(mappings, indexDefault, scenario.ignoredFields)
+	if len(malformed) == 0 {
+		return nil
+	}
+
+	var details []string
+	for _, field := range malformed {
+		details = append(details, fmt.Sprintf("- %s: malformed values ignored in %d documents", field, scenario.ignoredFieldsCount[field]))
+	}
+	if !r.failOnMalformed {
+		logger.Warnf("Found fields with ignore_malformed enabled that ignored values in data stream %s:\n%s", scenario.dataStream, strings.Join(details, "\n"))
+		return nil
+	}
+	return testrunner.ErrTestCaseFailed{
+		Reason:  "found fields with ignore_malformed enabled that ignored values",
+		Details: fmt.Sprintf("found fields with ignore_malformed enabled that ignored values in data stream %s:\n%s", scenario.dataStream, strings.Join(details, "\n")),
+	}
+}
+
+// getIndexIgnoreMalformed checks if ignore_malformed is enabled by default in any of the backing indices
+// of the data stream.
+func getIndexIgnoreMalformed(ctx context.Context, api *elasticsearch.API, dataStream string) (bool, error) {
+	resp, err := api.Indices.GetSettings(
+		api.Indices.GetSettings.WithContext(ctx),
+		api.Indices.GetSettings.WithIndex(dataStream),
+		api.Indices.GetSettings.WithName("index.mapping.ignore_malformed"),
+		api.Indices.GetSettings.WithFlatSettings(true),
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not get settings of data stream %s: %w", dataStream, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return false, fmt.Errorf("could not get settings of data stream %s: %s", dataStream, resp.String())
+	}
+
+	var indices map[string]struct {
+		Settings struct {
+			IgnoreMalformed string `json:"index.mapping.ignore_malformed"`
+		} `json:"settings"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&indices)
+	if err != nil {
+		return false, fmt.Errorf("could not decode settings of data stream %s: %w", dataStream, err)
+	}
+
+	for _, settings := range indices {
+		if settings.Settings.IgnoreMalformed == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findIgnoreMalformedFields returns the ignored fields that have ignore_malformed enabled in the given
+// mappings, explicitly or by default in the index.
+func findIgnoreMalformedFields(properties map[string]any, indexDefault bool, ignoredFields []string) []string {
+	var fields []string
+	for _, field := range ignoredFields {
+		mapping := findFieldMapping(properties, field)
+		if mapping == nil {
+			continue
+		}
+		fieldType, _ := mapping["type"].(string)
+		if !slices.Contains(ignoreMalformedTypes, fieldType) {
+			continue
+		}
+		enabled, found := mapping["ignore_malformed"].(bool)
+		if !found {
+			enabled = indexDefault
+		}
+		if enabled {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// findFieldMapping looks for the mapping of a field in the given properties. Field names can contain
+// dots when subobjects are disabled, so all the prefixes of the path are checked. Multi-fields are
+// also considered.
+func findFieldMapping(properties map[string]any, path string) map[string]any {
+	parts := strings.Split(path, ".")
+	for i := 1; i <= len(parts); i++ {
+		mapping, ok := properties[strings.Join(parts[:i], ".")].(map[string]any)
+		if !ok {
+			continue
+		}
+		if i == len(parts) {
+			return mapping
+		}
+		rest := strings.Join(parts[i:], ".")
+		if children, ok := mapping["properties"].(map[string]any); ok {
+			if found := findFieldMapping(children, rest); found != nil {
+				return found
+			}
+		}
+		if multiFields, ok := mapping["fields"].(map[string]any); ok {
+			if found, ok := multiFields[rest].(map[string]any); ok {
+				return found
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindIgnoreMalformedFields(t *testing.T) {
+	const mappings = `{
+  "source": {
+    "properties": {
+      "ip": {"type": "ip", "ignore_malformed": true},
+      "port": {"type": "long"},
+      "geo": {
+        "properties": {
+          "location": {"type": "geo_point", "ignore_malformed": false}
+        }
+      }
+    }
+  },
+  "event.duration": {"type": "long"},
+  "message": {
+    "type": "keyword",
+    "ignore_above": 1024,
+    "fields": {
+      "number": {"type": "long", "ignore_malformed": true}
+    }
+  }
+}`
+	var properties map[string]any
+	require.NoError(t, json.Unmarshal([]byte(mappings), &properties))
+
+	ignoredFields := []string{"source.ip", "source.port", "source.geo.location", "event.duration", "message", "message.number", "undefined"}
+
+	cases := []struct {
+		title        string
+		indexDefault bool
+		expected     []string
+	}{
+		{
+			title:    "ignore_malformed disabled by default",
+			expected: []string{"message.number", "source.ip"},
+		},
+		{
+			title:        "ignore_malformed enabled by default",
+			indexDefault: true,
+			expected:     []string{"event.duration", "message.number", "source.ip", "source.port"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, findIgnoreMalformedFields(properties, c.indexDefault, ignoredFields))
+		})
+	}
+}
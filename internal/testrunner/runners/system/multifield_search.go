@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// multiFieldSearchAssertion checks that a match query on a multi-field finds the ingested
+// documents, to validate the analyzer configured for the multi-field.
+type multiFieldSearchAssertion struct {
+	// Field is the full name of the multi-field, e.g. "message.text".
+	Field string `config:"field"`
+
+	// Query is the text to search for. If empty, the value of the parent field in the
+	// ingested documents is used.
+	Query string `config:"query"`
+}
+
+func (a multiFieldSearchAssertion) validate() error {
+	if a.Field == "" {
+		return errors.New("field is required")
+	}
+	if !strings.Contains(a.Field, ".") {
+		return fmt.Errorf("field %q is not a multi-field, expected a name like <parent>.<multi-field>", a.Field)
+	}
+	return nil
+}
+
+// parentField returns the name of the field that contains the multi-field.
+func (a multiFieldSearchAssertion) parentField() string {
+	return a.Field[:strings.LastIndexByte(a.Field, '.')]
+}
+
+// query returns the text to search for in the multi-field. If not configured, the first string
+// value found in the parent field of the documents is used.
+func (a multiFieldSearchAssertion) query(docs []common.MapStr) (string, bool) {
+	if a.Query != "" {
+		return a.Query, true
+	}
+	for _, doc := range docs {
+		value, err := doc.GetValue(a.parentField())
+		if err != nil {
+			continue
+		}
+		switch value := value.(type) {
+		case string:
+			if value != "" {
+				return value, true
+			}
+		case []any:
+			for _, elem := range value {
+				if s, ok := elem.(string); ok && s != "" {
+					return s, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// assertMultiFieldSearches runs a match query for each one of the assertions, and reports the
+// multi-fields whose searches don't find any document in the data stream.
+func (r *tester) assertMultiFieldSearches(ctx context.Context, dataStream string, assertions []multiFieldSearchAssertion, docs []common.MapStr) (pass bool, message string, err error) {
+	var failures []string
+	for _, assertion := range assertions {
+		query, found := assertion.query(docs)
+		if !found {
+			failures = append(failures, fmt.Sprintf("no values found in field %q to search in multi-field %q", assertion.parentField(), assertion.Field))
+			continue
+		}
+		count, err := r.countMatches(ctx, dataStream, assertion.Field, query)
+		if err != nil {
+			return false, "", err
+		}
+		logger.Debugf("assert search in multi-field %s for %q, observed %d hits", assertion.Field, query, count)
+		if count == 0 {
+			failures = append(failures, fmt.Sprintf("match query for %q in multi-field %q returned no hits", query, assertion.Field))
+		}
+	}
+	if len(failures) > 0 {
+		return false, strings.Join(failures, "; "), nil
+	}
+	return true, "", nil
+}
+
+// countMatches counts the documents of the data stream that match a match query on the field.
+func (r *tester) countMatches(ctx context.Context, dataStream, field, query string) (int, error) {
+	body, err := json.Marshal(map[string]any{
+		"query": map[string]any{
+			"match": map[string]any{
+				field: query,
+			},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode match query: %w", err)
+	}
+	resp, err := r.esAPI.Count(
+		r.esAPI.Count.WithContext(ctx),
+		r.esAPI.Count.WithIndex(dataStream),
+		r.esAPI.Count.WithBody(strings.NewReader(string(body))),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not search in field %s of data stream %s: %w", field, dataStream, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return 0, fmt.Errorf("could not search in field %s of data stream %s: %s", field, dataStream, resp.String())
+	}
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return 0, fmt.Errorf("could not decode count response: %w", err)
+	}
+	return result.Count, nil
+}
@@ -0,0 +1,96 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+)
+
+func TestNewConfigMultiFieldSearches(t *testing.T) {
+	cases := []struct {
+		title       string
+		assert      string
+		expectError bool
+	}{
+		{title: "valid", assert: "{field: message.text}"},
+		{title: "with query", assert: "{field: message.text, query: error}"},
+		{title: "missing field", assert: "{query: error}", expectError: true},
+		{title: "not a multi-field", assert: "{field: message}", expectError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", "assert:\n  multifield_searches:\n    - "+c.assert+"\n")
+
+			config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, config.Assert.MultiFieldSearches, 1)
+		})
+	}
+}
+
+func TestMultiFieldSearchQuery(t *testing.T) {
+	docs := []common.MapStr{
+		{"source": common.MapStr{"ip": "10.0.0.1"}},
+		{"message": "", "user": common.MapStr{"name": []any{"", "alice"}}},
+		{"message": "connection refused"},
+	}
+
+	cases := []struct {
+		title     string
+		assertion multiFieldSearchAssertion
+		expected  string
+		found     bool
+	}{
+		{
+			title:     "configured query",
+			assertion: multiFieldSearchAssertion{Field: "message.text", Query: "refused"},
+			expected:  "refused",
+			found:     true,
+		},
+		{
+			title:     "value of parent field",
+			assertion: multiFieldSearchAssertion{Field: "message.text"},
+			expected:  "connection refused",
+			found:     true,
+		},
+		{
+			title:     "value in array",
+			assertion: multiFieldSearchAssertion{Field: "user.name.text"},
+			expected:  "alice",
+			found:     true,
+		},
+		{
+			title:     "parent field not found",
+			assertion: multiFieldSearchAssertion{Field: "error.message.text"},
+			found:     false,
+		},
+		{
+			title:     "parent field is not a string",
+			assertion: multiFieldSearchAssertion{Field: "source.ip.text"},
+			expected:  "10.0.0.1",
+			found:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			query, found := c.assertion.query(docs)
+			assert.Equal(t, c.found, found)
+			assert.Equal(t, c.expected, query)
+		})
+	}
+}
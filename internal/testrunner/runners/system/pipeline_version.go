@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// getPipelineIngestCounts returns the number of documents processed by each ingest pipeline whose
+// name starts with the given prefix, summed for all the nodes of the cluster.
+func getPipelineIngestCounts(ctx context.Context, api *elasticsearch.API, prefix string) (map[string]int, error) {
+	resp, err := api.Nodes.Stats(
+		api.Nodes.Stats.WithContext(ctx),
+		api.Nodes.Stats.WithMetric("ingest"),
+		api.Nodes.Stats.WithFilterPath("nodes.*.ingest.pipelines.*.count"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get ingest stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("could not get ingest stats: %s", resp.String())
+	}
+
+	var stats struct {
+		Nodes map[string]struct {
+			Ingest struct {
+				Pipelines map[string]struct {
+					Count int `json:"count"`
+				} `json:"pipelines"`
+			} `json:"ingest"`
+		} `json:"nodes"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&stats)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode ingest stats: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, node := range stats.Nodes {
+		for name, pipeline := range node.Ingest.Pipelines {
+			if strings.HasPrefix(name, prefix) {
+				counts[name] += pipeline.Count
+			}
+		}
+	}
+	return counts, nil
+}
+
+// assertPipelineVersion checks that the documents ingested during the test were processed by the
+// pipeline installed for the expected version of the package, and not by pipelines installed for
+// other versions. Fleet names the pipelines it installs as <type>-<dataset>-<version>, and the
+// ones of the same version can be told apart by the number of documents they processed since the
+// beginning of the test.
+func assertPipelineVersion(indexTemplateName string, expectedVersion string, before, after map[string]int) (pass bool, message string) {
+	prefix := indexTemplateName + "-"
+	expectedPipeline := prefix + expectedVersion
+
+	var pipelines []string
+	for name := range after {
+		pipelines = append(pipelines, name)
+	}
+	slices.Sort(pipelines)
+
+	var failures []string
+	var used bool
+	for _, name := range pipelines {
+		processed := after[name] - before[name]
+		logger.Debugf("assert pipeline version expected %s, pipeline %q processed %d documents", expectedVersion, name, processed)
+		if processed <= 0 {
+			continue
+		}
+		switch {
+		case name == expectedPipeline || strings.HasPrefix(name, expectedPipeline+"-"):
+			// The pipeline of the expected version, or any of its subpipelines.
+			used = true
+		default:
+			failures = append(failures, fmt.Sprintf("pipeline %q processed %d documents, expected pipeline of version %s", name, processed, expectedVersion))
+		}
+	}
+	if !used {
+		failures = append([]string{fmt.Sprintf("pipeline %q didn't process any document", expectedPipeline)}, failures...)
+	}
+	if len(failures) > 0 {
+		return false, strings.Join(failures, "; ")
+	}
+	return true, ""
+}
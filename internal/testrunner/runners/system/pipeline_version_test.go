@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertPipelineVersion(t *testing.T) {
+	cases := []struct {
+		title   string
+		before  map[string]int
+		after   map[string]int
+		pass    bool
+		message string
+	}{
+		{
+			title: "expected version",
+			before: map[string]int{
+				"logs-test.foo-1.1.0": 10,
+			},
+			after: map[string]int{
+				"logs-test.foo-1.1.0":       10,
+				"logs-test.foo-1.2.0":       5,
+				"logs-test.foo-1.2.0-extra": 5,
+			},
+			pass: true,
+		},
+		{
+			title: "stale version",
+			before: map[string]int{
+				"logs-test.foo-1.1.0": 10,
+			},
+			after: map[string]int{
+				"logs-test.foo-1.1.0": 15,
+			},
+			message: `pipeline "logs-test.foo-1.2.0" didn't process any document; pipeline "logs-test.foo-1.1.0" processed 5 documents, expected pipeline of version 1.2.0`,
+		},
+		{
+			title: "both versions",
+			after: map[string]int{
+				"logs-test.foo-1.1.0": 2,
+				"logs-test.foo-1.2.0": 3,
+			},
+			message: `pipeline "logs-test.foo-1.1.0" processed 2 documents, expected pipeline of version 1.2.0`,
+		},
+		{
+			title:   "no pipelines",
+			message: `pipeline "logs-test.foo-1.2.0" didn't process any document`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			pass, message := assertPipelineVersion("logs-test.foo", "1.2.0", c.before, c.after)
+			assert.Equal(t, c.pass, pass)
+			assert.Equal(t, c.message, message)
+		})
+	}
+}
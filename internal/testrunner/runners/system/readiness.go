@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/testrunner"
+	"github.com/elastic/elastic-package/internal/wait"
+)
+
+const (
+	readinessProbeInputStatus = "input_status"
+	readinessProbeTCP         = "tcp"
+	readinessProbeHTTP        = "http"
+	readinessProbeNone        = "none"
+
+	readinessProbeDefaultTimeout = 2 * time.Minute
+	readinessProbePeriod         = 2 * time.Second
+
+	agentUnitStatusHealthy  = "HEALTHY"
+	agentUnitStatusDegraded = "DEGRADED"
+	agentUnitStatusFailed   = "FAILED"
+)
+
+// readinessProbeForInput returns the readiness probe to use for an input. Readiness is not checked
+// by default, probes are configured for an input type in the global test configuration, or in the
+// test, that has precedence.
+func readinessProbeForInput(input string, testProbe *testrunner.ReadinessProbe, globalProbes map[string]testrunner.ReadinessProbe) testrunner.ReadinessProbe {
+	probe := testrunner.ReadinessProbe{Type: readinessProbeNone}
+	if globalProbe, found := globalProbes[input]; found {
+		probe = globalProbe
+	}
+	if testProbe != nil {
+		probe = *testProbe
+	}
+	if probe.Timeout == 0 {
+		probe.Timeout = readinessProbeDefaultTimeout
+	}
+	return probe
+}
+
+func validateReadinessProbe(probe testrunner.ReadinessProbe) error {
+	switch probe.Type {
+	case readinessProbeInputStatus, readinessProbeNone:
+		return nil
+	case readinessProbeTCP, readinessProbeHTTP:
+		if probe.Address == "" {
+			return fmt.Errorf("readiness probe of type %q requires an address", probe.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown readiness probe type %q", probe.Type)
+	}
+}
+
+// waitForInputReadiness runs the readiness probe of the input under test until it succeeds.
+func (r *tester) waitForInputReadiness(ctx context.Context, config *testConfig, agent kibana.Agent) error {
+	probe := readinessProbeForInput(config.Input, config.Readiness, r.globalTestConfig.Readiness)
+	if probe.Type == readinessProbeNone {
+		return nil
+	}
+	if err := validateReadinessProbe(probe); err != nil {
+		return fmt.Errorf("invalid readiness probe for input %s: %w", config.Input, err)
+	}
+
+	logger.Debugf("waiting for input %s to be ready (probe: %s, timeout: %s)...", config.Input, probe.Type, probe.Timeout)
+	var lastReason string
+	ready, err := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
+		var ready bool
+		var err error
+		switch probe.Type {
+		case readinessProbeInputStatus:
+			ready, lastReason, err = r.probeInputStatus(ctx, agent.ID, config.Input)
+		case readinessProbeTCP:
+			ready, lastReason = probeTCP(ctx, probe.Address)
+		case readinessProbeHTTP:
+			ready, lastReason = probeHTTP(ctx, probe.Address)
+		}
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			logger.Debugf("input %s is not ready yet: %s", config.Input, lastReason)
+		}
+		return ready, nil
+	}, readinessProbePeriod, probe.Timeout)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		return testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("input %s was not ready after %s", config.Input, probe.Timeout),
+			Details: fmt.Sprintf("%s readiness probe didn't succeed: %s", probe.Type, lastReason),
+		}
+	}
+	return nil
+}
+
+// probeInputStatus checks the status reported to Fleet by the units of the agent running the input.
+// It fails if any of these units has failed, as the input is not going to collect any data.
+func (r *tester) probeInputStatus(ctx context.Context, agentID string, input string) (bool, string, error) {
+	agent, err := r.kibanaClient.GetAgent(ctx, agentID)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get status of agent: %w", err)
+	}
+	if len(agent.Components) == 0 {
+		// Old versions of the agent don't report the status of their components.
+		return true, "", nil
+	}
+	ready, reason, failed := inputUnitsStatus(agent.Components, input)
+	if failed {
+		return false, "", testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("input %s failed to start", input),
+			Details: reason,
+		}
+	}
+	return ready, reason, nil
+}
+
+// inputUnitsStatus checks if all the input units of the components of the given input type are running.
+// Degraded units are considered ready, as they are running and may still collect data, for example
+// when an API polled by the input returns errors only for some requests. If any of them has failed,
+// it returns failed as true.
+func inputUnitsStatus(components []kibana.AgentComponent, input string) (ready bool, reason string, failed bool) {
+	var pending []string
+	found := false
+	for _, component := range components {
+		if component.Type != input {
+			continue
+		}
+		for _, unit := range component.Units {
+			if unit.Type != "input" {
+				continue
+			}
+			found = true
+			switch unit.Status {
+			case agentUnitStatusHealthy, agentUnitStatusDegraded:
+			case agentUnitStatusFailed:
+				return false, fmt.Sprintf("unit %s failed: %s", unit.ID, unit.Message), true
+			default:
+				pending = append(pending, fmt.Sprintf("unit %s is %s: %s", unit.ID, strings.ToLower(unit.Status), unit.Message))
+			}
+		}
+	}
+	if !found {
+		return false, fmt.Sprintf("no units found for input %s", input), false
+	}
+	if len(pending) > 0 {
+		return false, strings.Join(pending, "; "), false
+	}
+	return true, "", false
+}
+
+func probeTCP(ctx context.Context, address string) (bool, string) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return false, err.Error()
+	}
+	conn.Close()
+	return true, ""
+}
+
+func probeHTTP(ctx context.Context, address string) (bool, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return false, err.Error()
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err.Error()
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+	}
+	return true, ""
+}
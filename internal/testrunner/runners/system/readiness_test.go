@@ -0,0 +1,158 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestReadinessProbeForInput(t *testing.T) {
+	globalProbes := map[string]testrunner.ReadinessProbe{
+		"tcp":      {Type: readinessProbeInputStatus},
+		"logfile":  {Type: readinessProbeTCP, Address: "localhost:9000", Timeout: 30 * time.Second},
+		"httpjson": {Type: readinessProbeHTTP, Address: "http://localhost:8080"},
+	}
+
+	cases := []struct {
+		title     string
+		input     string
+		testProbe *testrunner.ReadinessProbe
+		expected  testrunner.ReadinessProbe
+	}{
+		{
+			title:    "input without probe",
+			input:    "filestream",
+			expected: testrunner.ReadinessProbe{Type: readinessProbeNone, Timeout: readinessProbeDefaultTimeout},
+		},
+		{
+			title:    "input that reports its status without probe",
+			input:    "cel",
+			expected: testrunner.ReadinessProbe{Type: readinessProbeNone, Timeout: readinessProbeDefaultTimeout},
+		},
+		{
+			title:    "input status probe configured in global config",
+			input:    "tcp",
+			expected: testrunner.ReadinessProbe{Type: readinessProbeInputStatus, Timeout: readinessProbeDefaultTimeout},
+		},
+		{
+			title:    "probe configured in global config",
+			input:    "logfile",
+			expected: testrunner.ReadinessProbe{Type: readinessProbeTCP, Address: "localhost:9000", Timeout: 30 * time.Second},
+		},
+		{
+			title:     "probe configured in test",
+			input:     "httpjson",
+			testProbe: &testrunner.ReadinessProbe{Type: readinessProbeInputStatus, Timeout: time.Minute},
+			expected:  testrunner.ReadinessProbe{Type: readinessProbeInputStatus, Timeout: time.Minute},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, readinessProbeForInput(c.input, c.testProbe, globalProbes))
+		})
+	}
+}
+
+func TestValidateReadinessProbe(t *testing.T) {
+	assert.NoError(t, validateReadinessProbe(testrunner.ReadinessProbe{Type: readinessProbeInputStatus}))
+	assert.NoError(t, validateReadinessProbe(testrunner.ReadinessProbe{Type: readinessProbeTCP, Address: "localhost:9000"}))
+	assert.Error(t, validateReadinessProbe(testrunner.ReadinessProbe{Type: readinessProbeHTTP}))
+	assert.Error(t, validateReadinessProbe(testrunner.ReadinessProbe{Type: "unknown"}))
+}
+
+func TestInputUnitsStatus(t *testing.T) {
+	component := func(inputType string, statuses ...string) kibana.AgentComponent {
+		c := kibana.AgentComponent{ID: inputType + "-default", Type: inputType}
+		c.Units = append(c.Units, kibana.AgentComponentUnit{ID: c.ID, Type: "output", Status: agentUnitStatusHealthy})
+		for _, status := range statuses {
+			c.Units = append(c.Units, kibana.AgentComponentUnit{ID: c.ID + "-unit", Type: "input", Status: status, Message: "some message"})
+		}
+		return c
+	}
+
+	cases := []struct {
+		title      string
+		components []kibana.AgentComponent
+		ready      bool
+		failed     bool
+	}{
+		{
+			title:      "healthy",
+			components: []kibana.AgentComponent{component("filestream", "STARTING"), component("tcp", "HEALTHY")},
+			ready:      true,
+		},
+		{
+			title:      "degraded",
+			components: []kibana.AgentComponent{component("tcp", "HEALTHY", "DEGRADED")},
+			ready:      true,
+		},
+		{
+			title:      "starting",
+			components: []kibana.AgentComponent{component("tcp", "HEALTHY", "STARTING")},
+		},
+		{
+			title:      "failed",
+			components: []kibana.AgentComponent{component("tcp", "HEALTHY", "FAILED")},
+			failed:     true,
+		},
+		{
+			title:      "not found",
+			components: []kibana.AgentComponent{component("filestream", "HEALTHY")},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			ready, reason, failed := inputUnitsStatus(c.components, "tcp")
+			assert.Equal(t, c.ready, ready)
+			assert.Equal(t, c.failed, failed)
+			if !ready {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	ready, _ := probeTCP(context.Background(), address)
+	assert.True(t, ready)
+
+	listener.Close()
+	ready, reason := probeTCP(context.Background(), address)
+	assert.False(t, ready)
+	assert.NotEmpty(t, reason)
+}
+
+func TestProbeHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ready" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ready, _ := probeHTTP(context.Background(), server.URL+"/ready")
+	assert.True(t, ready)
+
+	ready, reason := probeHTTP(context.Background(), server.URL+"/starting")
+	assert.False(t, ready)
+	assert.Equal(t, "unexpected status code 503", reason)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -34,15 +35,29 @@ type runner struct {
 	dataStreams    []string
 	serviceVariant string
 
-	globalTestConfig   testrunner.GlobalRunnerTestConfig
-	failOnMissingTests bool
-	checkFailureStore  bool
-	deferCleanup       time.Duration
-	generateTestResult bool
-	withCoverage       bool
-	coverageType       string
+	globalTestConfig     testrunner.GlobalRunnerTestConfig
+	failOnMissingTests   bool
+	checkFailureStore    bool
+	agentImage           string
+	strictIgnoredFields  bool
+	failOnMalformed      bool
+	failOnAgentWarnings  bool
+	maxLogSize           uint64
+	printPolicy          bool
+	validateOnly         string
+	exportDocs           string
+	diagnosticsOnFailure bool
+	explain              bool
+	mappingsReport       bool
+	keepAgent            bool
+	deferCleanup         time.Duration
+	generateTestResult   bool
+	withCoverage         bool
+	coverageType         string
 
 	configFilePath string
+	configFiles    []string
+	configName     string
 	runSetup       bool
 	runTearDown    bool
 	runTestsOnly   bool
@@ -71,36 +86,70 @@ type SystemTestRunnerOptions struct {
 	RunTestsOnly   bool
 	ConfigFilePath string
 
+	// ConfigFiles are the paths of the configuration files of the tests to run. All configuration
+	// files are used if empty.
+	ConfigFiles []string
+
+	// ConfigName is the name of the configuration of the test to run, as it is shown in the
+	// results. All configurations are used if empty.
+	ConfigName string
+
 	GlobalTestConfig testrunner.GlobalRunnerTestConfig
 
-	FailOnMissingTests bool
-	CheckFailureStore  bool
-	GenerateTestResult bool
-	DeferCleanup       time.Duration
-	WithCoverage       bool
-	CoverageType       string
+	FailOnMissingTests   bool
+	CheckFailureStore    bool
+	AgentImage           string
+	StrictIgnoredFields  bool
+	FailOnMalformed      bool
+	FailOnAgentWarnings  bool
+	MaxLogSize           uint64
+	PrintPolicy          bool
+	ValidateOnly         string
+	ExportDocs           string
+	DiagnosticsOnFailure bool
+	Explain              bool
+	MappingsReport       bool
+	KeepAgent            bool
+	GenerateTestResult   bool
+	DeferCleanup         time.Duration
+	WithCoverage         bool
+	CoverageType         string
 }
 
 func NewSystemTestRunner(options SystemTestRunnerOptions) *runner {
 	r := runner{
-		packageRootPath:    options.PackageRootPath,
-		kibanaClient:       options.KibanaClient,
-		esAPI:              options.API,
-		esClient:           options.ESClient,
-		profile:            options.Profile,
-		dataStreams:        options.DataStreams,
-		serviceVariant:     options.ServiceVariant,
-		configFilePath:     options.ConfigFilePath,
-		runSetup:           options.RunSetup,
-		runTestsOnly:       options.RunTestsOnly,
-		runTearDown:        options.RunTearDown,
-		failOnMissingTests: options.FailOnMissingTests,
-		checkFailureStore:  options.CheckFailureStore,
-		generateTestResult: options.GenerateTestResult,
-		deferCleanup:       options.DeferCleanup,
-		globalTestConfig:   options.GlobalTestConfig,
-		withCoverage:       options.WithCoverage,
-		coverageType:       options.CoverageType,
+		packageRootPath:      options.PackageRootPath,
+		kibanaClient:         options.KibanaClient,
+		esAPI:                options.API,
+		esClient:             options.ESClient,
+		profile:              options.Profile,
+		dataStreams:          options.DataStreams,
+		serviceVariant:       options.ServiceVariant,
+		configFilePath:       options.ConfigFilePath,
+		configFiles:          options.ConfigFiles,
+		configName:           options.ConfigName,
+		runSetup:             options.RunSetup,
+		runTestsOnly:         options.RunTestsOnly,
+		runTearDown:          options.RunTearDown,
+		failOnMissingTests:   options.FailOnMissingTests,
+		checkFailureStore:    options.CheckFailureStore,
+		agentImage:           options.AgentImage,
+		strictIgnoredFields:  options.StrictIgnoredFields,
+		failOnMalformed:      options.FailOnMalformed,
+		failOnAgentWarnings:  options.FailOnAgentWarnings,
+		maxLogSize:           options.MaxLogSize,
+		printPolicy:          options.PrintPolicy,
+		validateOnly:         options.ValidateOnly,
+		exportDocs:           options.ExportDocs,
+		diagnosticsOnFailure: options.DiagnosticsOnFailure,
+		explain:              options.Explain,
+		mappingsReport:       options.MappingsReport,
+		keepAgent:            options.KeepAgent,
+		generateTestResult:   options.GenerateTestResult,
+		deferCleanup:         options.DeferCleanup,
+		globalTestConfig:     options.GlobalTestConfig,
+		withCoverage:         options.WithCoverage,
+		coverageType:         options.CoverageType,
 	}
 
 	r.resourcesManager = resources.NewManager()
@@ -112,7 +161,7 @@ func NewSystemTestRunner(options SystemTestRunnerOptions) *runner {
 
 // SetupRunner prepares global resources required by the test runner.
 func (r *runner) SetupRunner(ctx context.Context) error {
-	if r.runTearDown {
+	if r.runTearDown || r.printPolicy || r.validateOnly != "" {
 		logger.Debug("Skip installing package")
 		return nil
 	}
@@ -163,6 +212,9 @@ func (r *runner) setupFailureStore(ctx context.Context) error {
 // TearDownRunner cleans up any global test runner resources. It must be called
 // after the test runner has finished executing all its tests.
 func (r *runner) TearDownRunner(ctx context.Context) error {
+	if r.printPolicy || r.validateOnly != "" {
+		return nil
+	}
 	logger.Debug("Uninstalling package...")
 	resourcesOptions := resourcesOptions{
 		// Keep it installed only if we were running setup, or tests only.
@@ -199,7 +251,7 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 		if r.runTearDown && os.IsNotExist(err) {
 			return nil, fmt.Errorf("failed to run --tear-down, setup not found")
 		}
-	} else {
+	} else if r.validateOnly == "" {
 		if _, err = os.Stat(r.serviceStateFilePath); !os.IsNotExist(err) {
 			return nil, fmt.Errorf("failed to run tests, required to tear down previous state run (path: %s)", r.serviceStateFilePath)
 		}
@@ -247,6 +299,13 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 		}
 	}
 
+	if r.validateOnly != "" && hasDataStreams {
+		folders, err = r.filterFoldersByDataStream(folders, manifest, r.validateOnly)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if r.runSetup || r.runTearDown || r.runTestsOnly {
 		// variant flag is not checked here since there are packages that do not have variants
 		if len(folders) != 1 {
@@ -274,27 +333,45 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 			}
 		}
 
+		if r.validateOnly != "" && len(variants) > 0 && len(cfgFiles) > 0 {
+			// Documents are validated only once, using the settings of the first configuration.
+			variants = variants[:1]
+			cfgFiles = cfgFiles[:1]
+		}
+
 		for _, variant := range variants {
 			for _, config := range cfgFiles {
 				logger.Debugf("System runner: data stream %q config file %q variant %q", t.DataStream, config, variant)
 				tester, err := NewSystemTester(SystemTesterOptions{
-					Profile:            r.profile,
-					PackageRootPath:    r.packageRootPath,
-					KibanaClient:       r.kibanaClient,
-					API:                r.esAPI,
-					ESClient:           r.esClient,
-					TestFolder:         t,
-					ServiceVariant:     variant,
-					GenerateTestResult: r.generateTestResult,
-					DeferCleanup:       r.deferCleanup,
-					RunSetup:           r.runSetup,
-					RunTestsOnly:       r.runTestsOnly,
-					RunTearDown:        r.runTearDown,
-					ConfigFileName:     config,
-					GlobalTestConfig:   r.globalTestConfig,
-					WithCoverage:       r.withCoverage,
-					CoverageType:       r.coverageType,
-					CheckFailureStore:  r.checkFailureStore,
+					Profile:              r.profile,
+					PackageRootPath:      r.packageRootPath,
+					KibanaClient:         r.kibanaClient,
+					API:                  r.esAPI,
+					ESClient:             r.esClient,
+					TestFolder:           t,
+					ServiceVariant:       variant,
+					GenerateTestResult:   r.generateTestResult,
+					DeferCleanup:         r.deferCleanup,
+					RunSetup:             r.runSetup,
+					RunTestsOnly:         r.runTestsOnly,
+					RunTearDown:          r.runTearDown,
+					ConfigFileName:       config,
+					GlobalTestConfig:     r.globalTestConfig,
+					WithCoverage:         r.withCoverage,
+					CoverageType:         r.coverageType,
+					CheckFailureStore:    r.checkFailureStore,
+					AgentImage:           r.agentImage,
+					StrictIgnoredFields:  r.strictIgnoredFields,
+					FailOnMalformed:      r.failOnMalformed,
+					FailOnAgentWarnings:  r.failOnAgentWarnings,
+					MaxLogSize:           r.maxLogSize,
+					PrintPolicy:          r.printPolicy,
+					ValidateOnly:         r.validateOnly,
+					ExportDocs:           r.exportDocs,
+					DiagnosticsOnFailure: r.diagnosticsOnFailure,
+					Explain:              r.explain,
+					MappingsReport:       r.mappingsReport,
+					KeepAgent:            r.keepAgent,
 				})
 				if err != nil {
 					return nil, fmt.Errorf(
@@ -305,9 +382,39 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 			}
 		}
 	}
+	if r.configName != "" && len(testers) == 0 {
+		return nil, fmt.Errorf("no %s test configuration found with name %q", r.Type(), r.configName)
+	}
+	if r.exportDocs != "" && len(testers) > 1 {
+		return nil, fmt.Errorf("documents can be exported only when running a single test, found %d tests, select one of them with the data streams and configuration flags", len(testers))
+	}
 	return testers, nil
 }
 
+// filterFoldersByDataStream returns the test folders of the package data stream whose dataset
+// matches the one of the given data stream.
+func (r *runner) filterFoldersByDataStream(folders []testrunner.TestFolder, manifest *packages.PackageManifest, dataStream string) ([]testrunner.TestFolder, error) {
+	_, dataset, _, err := parseDataStreamName(dataStream)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []testrunner.TestFolder
+	for _, folder := range folders {
+		dsManifest, err := packages.ReadDataStreamManifestFromPackageRoot(r.packageRootPath, folder.DataStream)
+		if err != nil {
+			return nil, fmt.Errorf("reading data stream manifest failed (data stream: %s): %w", folder.DataStream, err)
+		}
+		if getDataStreamDataset(*manifest, *dsManifest) == dataset {
+			selected = append(selected, folder)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no %s tests found for a data stream with dataset %q", r.Type(), dataset)
+	}
+	return selected, nil
+}
+
 // Type returns the type of test that can be run by this test runner.
 func (r *runner) Type() testrunner.TestType {
 	return TestType
@@ -387,7 +494,7 @@ func (r *runner) getAllConfigFiles(folder testrunner.TestFolder) ([]string, erro
 	var cfgFiles []string
 	var err error
 	if r.configFilePath != "" {
-		allCfgFiles, err := listConfigFiles(filepath.Dir(r.configFilePath))
+		allCfgFiles, err := ListConfigFiles(filepath.Dir(r.configFilePath))
 		if err != nil {
 			return nil, fmt.Errorf("failed listing test case config cfgFiles: %w", err)
 		}
@@ -398,10 +505,20 @@ func (r *runner) getAllConfigFiles(folder testrunner.TestFolder) ([]string, erro
 			}
 		}
 	} else {
-		cfgFiles, err = listConfigFiles(folder.Path)
+		cfgFiles, err = ListConfigFiles(folder.Path)
 		if err != nil {
 			return nil, fmt.Errorf("failed listing test case config cfgFiles: %w", err)
 		}
+		if len(r.configFiles) > 0 {
+			cfgFiles = slices.DeleteFunc(cfgFiles, func(cfg string) bool {
+				return !slices.Contains(r.configFiles, filepath.Join(folder.Path, cfg))
+			})
+		}
+		if r.configName != "" {
+			cfgFiles = slices.DeleteFunc(cfgFiles, func(cfg string) bool {
+				return configNameFromPath(cfg) != r.configName
+			})
+		}
 	}
 	return cfgFiles, nil
 }
@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestGetAllConfigFiles(t *testing.T) {
+	folder := testrunner.TestFolder{Path: t.TempDir(), DataStream: "test"}
+	for _, name := range []string{"test-default-config.yml", "test-other-config.yml", "README.md"} {
+		filestest.WriteFile(t, folder.Path, name, "")
+	}
+
+	cases := []struct {
+		title       string
+		configFiles []string
+		configName  string
+		expected    []string
+	}{
+		{
+			title:    "all config files",
+			expected: []string{"test-default-config.yml", "test-other-config.yml"},
+		},
+		{
+			title:       "selected config files",
+			configFiles: []string{filepath.Join(folder.Path, "test-other-config.yml")},
+			expected:    []string{"test-other-config.yml"},
+		},
+		{
+			title:       "config files of other folders",
+			configFiles: []string{filepath.Join("other", "test-other-config.yml")},
+			expected:    []string{},
+		},
+		{
+			title:      "selected config name",
+			configName: "other",
+			expected:   []string{"test-other-config.yml"},
+		},
+		{
+			title:      "unknown config name",
+			configName: "unknown",
+			expected:   []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			r := runner{configFiles: c.configFiles, configName: c.configName}
+			cfgFiles, err := r.getAllConfigFiles(folder)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, c.expected, cfgFiles)
+		})
+	}
+}
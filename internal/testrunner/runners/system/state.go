@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/elastic/elastic-package/internal/agentdeployer"
+	"github.com/elastic/elastic-package/internal/common"
 	"github.com/elastic/elastic-package/internal/kibana"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/servicedeployer"
@@ -32,6 +33,8 @@ type ServiceState struct {
 	ServiceRunID     string        `json:"service_info_run_id"`
 	AgentRunID       string        `json:"agent_info_run_id"`
 	ServiceOutputDir string        `json:"service_output_dir"`
+
+	OrigClusterSettings common.MapStr `json:"orig_cluster_settings,omitempty"`
 }
 
 // stateFolderPath returns the folder where the state data is stored
@@ -58,9 +61,11 @@ type scenarioStateOpts struct {
 	enrollPolicy  *kibana.Policy
 	origPolicy    *kibana.Policy
 	config        *testConfig
-	agent         kibana.Agent
-	agentInfo     agentdeployer.AgentInfo
-	svcInfo       servicedeployer.ServiceInfo
+
+	origClusterSettings common.MapStr
+	agent               kibana.Agent
+	agentInfo           agentdeployer.AgentInfo
+	svcInfo             servicedeployer.ServiceInfo
 }
 
 func writeScenarioState(opts scenarioStateOpts, target string) error {
@@ -74,6 +79,8 @@ func writeScenarioState(opts scenarioStateOpts, target string) error {
 		ServiceRunID:     opts.svcInfo.Test.RunID,
 		AgentRunID:       opts.agentInfo.Test.RunID,
 		ServiceOutputDir: opts.svcInfo.OutputDir,
+
+		OrigClusterSettings: opts.origClusterSettings,
 	}
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
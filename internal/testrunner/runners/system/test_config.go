@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +27,16 @@ import (
 
 var systemTestConfigFilePattern = regexp.MustCompile(`^test-([a-z0-9_.-]+)-config.yml$`)
 
+// variableHelperName is the name of the helper used in test configuration files to
+// reference the variables defined in the global test configuration.
+const variableHelperName = "var"
+
+// Datasets cannot contain dashes, and the full data stream name has a limited length,
+// so dataset suffixes are restricted to a short set of safe characters.
+const maxDatasetSuffixLength = 32
+
+var datasetSuffixPattern = regexp.MustCompile(fmt.Sprintf(`^[a-z0-9_]{1,%d}$`, maxDatasetSuffixLength))
+
 type testConfig struct {
 	testrunner.SkippableConfig `config:",inline"`
 
@@ -37,14 +48,71 @@ type testConfig struct {
 	WaitForDataTimeout  time.Duration `config:"wait_for_data_timeout"`
 	SkipIgnoredFields   []string      `config:"skip_ignored_fields"`
 
+	// Readiness configures the readiness probe of the input, used to check that it is ready
+	// before waiting for data. It overrides the probe configured for the input type.
+	Readiness *testrunner.ReadinessProbe `config:"readiness"`
+
+	// WaitForDataPollInterval is the time between checks for data in Elasticsearch.
+	WaitForDataPollInterval time.Duration `config:"wait_for_data_poll_interval"`
+
+	// WaitForTransformTimeout is the time to wait for transforms to be installed by Fleet.
+	WaitForTransformTimeout time.Duration `config:"wait_for_transform_timeout"`
+
+	// ServiceNetworks are additional Docker networks the service is connected to. They must
+	// exist before the service is started.
+	ServiceNetworks []string `config:"service_networks"`
+
+	// DatasetSuffix is appended to the default dataset of input packages, so tests running in
+	// parallel in shared clusters don't collide.
+	DatasetSuffix string `config:"dataset_suffix"`
+
+	// SyntheticSource forces the source mode used to validate documents, skipping
+	// its detection from the index template when set.
+	SyntheticSource *bool `config:"synthetic_source"`
+
+	// ClusterSettings are persistent Elasticsearch cluster settings applied during the test.
+	// Previous values are restored when the test is torn down.
+	ClusterSettings common.MapStr `config:"cluster_settings"`
+
 	Vars       common.MapStr `config:"vars"`
 	DataStream struct {
 		Vars common.MapStr `config:"vars"`
 	} `config:"data_stream"`
 
+	// Terraform contains settings for the Terraform service deployer.
+	Terraform struct {
+		// Regions where the Terraform definitions are applied, each one in its own workspace.
+		Regions []string `config:"regions"`
+	} `config:"terraform"`
+
 	Assert struct {
 		// Expected number of hits for a given test
 		HitCount int `config:"hit_count"`
+
+		// Expected number of documents that failed ingestion, with error.message or
+		// in the failure store. When set, these documents are not reported as errors.
+		FailedCount int `config:"failed_count"`
+
+		// Aggregations contains the expected bounds of aggregated values of fields.
+		Aggregations []aggregationAssertion `config:"aggregations"`
+
+		// MultiFieldSearches contains multi-fields where a match query is expected to find
+		// the ingested documents.
+		MultiFieldSearches []multiFieldSearchAssertion `config:"multifield_searches"`
+
+		// Cardinality contains the maximum number of unique values expected for fields.
+		Cardinality common.MapStr `config:"cardinality"`
+
+		// CardinalityLimits contains the limits in Cardinality, by field name.
+		CardinalityLimits map[string]int `config:",ignore"`
+
+		// PipelineVersion enables checking that documents are ingested with the pipelines of the
+		// version of the package under test.
+		PipelineVersion bool `config:"pipeline_version"`
+
+		// FailureHint is an optional message included in the failure reason when the expected
+		// documents are not found, to help diagnosing the failure.
+		FailureHint string `config:"failure_hint"`
 	} `config:"assert"`
 
 	// NumericKeywordFields holds a list of fields that have keyword
@@ -55,33 +123,46 @@ type testConfig struct {
 	// types but can be ingested as strings.
 	StringNumberFields []string `config:"string_number_fields"`
 
+	// ExpectedDatasetsFile is the path, relative to the configuration file, of a file with
+	// additional values expected in dataset fields, one per line.
+	ExpectedDatasetsFile string `config:"expected_datasets_file"`
+
+	// ExpectedDatasets contains the datasets read from ExpectedDatasetsFile.
+	ExpectedDatasets []string `config:",ignore"`
+
 	Path               string `config:",ignore"` // Path of config file.
 	ServiceVariantName string `config:",ignore"` // Name of test variant when using variants.yml.
+	BaseImageName      string `config:",ignore"` // Name of the agent base image when testing multiple base images.
 
 	// Agent related properties
 	Agent struct {
 		agentdeployer.AgentSettings `config:",inline"`
+
+		// BaseImages are the base images of the Elastic Agent to test with, the test is run
+		// once with each of them.
+		BaseImages []string `config:"base_images"`
 	} `config:"agent"`
 }
 
 func (t testConfig) Name() string {
-	name := filepath.Base(t.Path)
-	if matches := systemTestConfigFilePattern.FindStringSubmatch(name); len(matches) > 1 {
-		name = matches[1]
-	}
-
 	var sb strings.Builder
-	sb.WriteString(name)
+	sb.WriteString(configNameFromPath(t.Path))
 
 	if t.ServiceVariantName != "" {
 		sb.WriteString(" (variant: ")
 		sb.WriteString(t.ServiceVariantName)
 		sb.WriteString(")")
 	}
+
+	if t.BaseImageName != "" {
+		sb.WriteString(" (base image: ")
+		sb.WriteString(t.BaseImageName)
+		sb.WriteString(")")
+	}
 	return sb.String()
 }
 
-func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, serviceVariantName string) (*testConfig, error) {
+func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, serviceVariantName string, variables map[string]string) (*testConfig, error) {
 	data, err := os.ReadFile(configFilePath)
 	if err != nil && errors.Is(err, os.ErrNotExist) {
 		return nil, fmt.Errorf("unable to find system test configuration file: %s: %w", configFilePath, err)
@@ -91,7 +172,7 @@ func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, servi
 		return nil, fmt.Errorf("could not load system test configuration file: %s: %w", configFilePath, err)
 	}
 
-	data, err = applyServiceInfo(data, svcInfo)
+	data, err = applyServiceInfo(data, svcInfo, variables)
 	if err != nil {
 		return nil, fmt.Errorf("could not apply context to test configuration file: %s: %w", configFilePath, err)
 	}
@@ -104,6 +185,42 @@ func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, servi
 	if err := cfg.Unpack(&c); err != nil {
 		return nil, fmt.Errorf("unable to unpack system test configuration file: %s: %w", configFilePath, err)
 	}
+	if c.DatasetSuffix != "" && !datasetSuffixPattern.MatchString(c.DatasetSuffix) {
+		return nil, fmt.Errorf("invalid dataset_suffix %q in system test configuration file %s: it can only contain lowercase letters, numbers and underscores, and be up to %d characters long", c.DatasetSuffix, configFilePath, maxDatasetSuffixLength)
+	}
+
+	for _, aggregation := range c.Assert.Aggregations {
+		if err := aggregation.validate(); err != nil {
+			return nil, fmt.Errorf("invalid aggregation assertion in system test configuration file %s: %w", configFilePath, err)
+		}
+	}
+
+	for _, search := range c.Assert.MultiFieldSearches {
+		if err := search.validate(); err != nil {
+			return nil, fmt.Errorf("invalid multifield search assertion in system test configuration file %s: %w", configFilePath, err)
+		}
+	}
+
+	c.Assert.CardinalityLimits, err = cardinalityLimits(c.Assert.Cardinality)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cardinality assertion in system test configuration file %s: %w", configFilePath, err)
+	}
+
+	if c.ExpectedDatasetsFile != "" {
+		path := c.ExpectedDatasetsFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(configFilePath), path)
+		}
+		c.ExpectedDatasets, err = readExpectedDatasetsFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read expected datasets for system test configuration file %s: %w", configFilePath, err)
+		}
+	}
+
+	if len(c.Agent.BaseImages) > 0 && (c.Agent.BaseImage != "" || c.Agent.Image != "") {
+		return nil, fmt.Errorf("agent.base_images cannot be used with agent.base_image or agent.image in system test configuration file %s", configFilePath)
+	}
+
 	// Save path
 	c.Path = configFilePath
 	c.ServiceVariantName = serviceVariantName
@@ -124,7 +241,30 @@ func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, servi
 	return &c, nil
 }
 
-func listConfigFiles(systemTestFolderPath string) (files []string, err error) {
+// readExpectedDatasetsFile reads a file with one dataset per line. Empty lines and lines
+// starting with # are ignored.
+func readExpectedDatasetsFile(path string) ([]string, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var datasets []string
+	for _, line := range strings.Split(string(d), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		datasets = append(datasets, line)
+	}
+	if len(datasets) == 0 {
+		return nil, fmt.Errorf("no datasets found in %s", path)
+	}
+	return datasets, nil
+}
+
+// ListConfigFiles returns the names of the system test configuration files in the given test folder.
+func ListConfigFiles(systemTestFolderPath string) (files []string, err error) {
 	fHandle, err := os.Open(systemTestFolderPath)
 	if err != nil {
 		return nil, err
@@ -145,12 +285,20 @@ func listConfigFiles(systemTestFolderPath string) (files []string, err error) {
 // applyServiceInfo takes the given system test configuration (data) and replaces any placeholder variables in
 // it with values from the given service information. The context may be populated from various sources but usually the
 // most interesting context values will be set by a ServiceDeployer in its SetUp method.
-func applyServiceInfo(data []byte, serviceInfo servicedeployer.ServiceInfo) ([]byte, error) {
+func applyServiceInfo(data []byte, serviceInfo servicedeployer.ServiceInfo, variables map[string]string) ([]byte, error) {
 	tmpl, err := raymond.Parse(string(data))
 	if err != nil {
 		return data, fmt.Errorf("parsing template body failed: %w", err)
 	}
 	tmpl.RegisterHelpers(serviceInfo.Aliases())
+	tmpl.RegisterHelper(variableHelperName, func(name string) string {
+		value, found := variables[name]
+		if !found {
+			// Raymond returns errors raised by helpers as errors of the template execution.
+			panic(fmt.Errorf("undefined variable %q", name))
+		}
+		return value
+	})
 
 	result, err := tmpl.Exec(serviceInfo)
 	if err != nil {
@@ -158,3 +306,58 @@ func applyServiceInfo(data []byte, serviceInfo servicedeployer.ServiceInfo) ([]b
 	}
 	return []byte(result), nil
 }
+
+const (
+	aggregationStatMin = "min"
+	aggregationStatMax = "max"
+	aggregationStatAvg = "avg"
+)
+
+var aggregationStats = []string{aggregationStatMin, aggregationStatMax, aggregationStatAvg}
+
+// aggregationAssertion checks that a statistic computed over the values of a field in all the
+// ingested documents is within the given bounds.
+type aggregationAssertion struct {
+	Field string   `config:"field"`
+	Stat  string   `config:"stat"`
+	GTE   *float64 `config:"gte"`
+	LTE   *float64 `config:"lte"`
+}
+
+func (a aggregationAssertion) validate() error {
+	if a.Field == "" {
+		return errors.New("field is required")
+	}
+	if !slices.Contains(aggregationStats, a.Stat) {
+		return fmt.Errorf("unsupported stat %q for field %q, expected one of %s", a.Stat, a.Field, strings.Join(aggregationStats, ", "))
+	}
+	if a.GTE == nil && a.LTE == nil {
+		return fmt.Errorf("at least one of gte or lte is required for the %s of field %q", a.Stat, a.Field)
+	}
+	if a.GTE != nil && a.LTE != nil && *a.GTE > *a.LTE {
+		return fmt.Errorf("gte (%v) is greater than lte (%v) for the %s of field %q", *a.GTE, *a.LTE, a.Stat, a.Field)
+	}
+	return nil
+}
+
+// bounds returns a human-readable representation of the expected bounds.
+func (a aggregationAssertion) bounds() string {
+	switch {
+	case a.GTE != nil && a.LTE != nil:
+		return fmt.Sprintf("[%v, %v]", *a.GTE, *a.LTE)
+	case a.GTE != nil:
+		return fmt.Sprintf(">= %v", *a.GTE)
+	default:
+		return fmt.Sprintf("<= %v", *a.LTE)
+	}
+}
+
+// configNameFromPath returns the name of a test configuration from the name of its file,
+// e.g. "default" for "test-default-config.yml".
+func configNameFromPath(path string) string {
+	name := filepath.Base(path)
+	if matches := systemTestConfigFilePattern.FindStringSubmatch(name); len(matches) > 1 {
+		name = matches[1]
+	}
+	return name
+}
@@ -10,18 +10,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/dustin/go-humanize"
 	"gopkg.in/yaml.v3"
 
 	"github.com/elastic/elastic-package/internal/agentdeployer"
+	"github.com/elastic/elastic-package/internal/builder"
 	"github.com/elastic/elastic-package/internal/common"
 	"github.com/elastic/elastic-package/internal/configuration/locations"
 	"github.com/elastic/elastic-package/internal/elasticsearch"
@@ -83,16 +89,42 @@ const (
 	// Maximum number of events to query.
 	elasticsearchQuerySize = 500
 
+	// Minimum priority of index templates, built-in templates of Elasticsearch have priority 100.
+	minIndexTemplatePriority = 101
+
 	// ServiceLogsAgentDir is folder path where log files produced by the service
 	// are stored on the Agent container's filesystem.
 	ServiceLogsAgentDir = "/tmp/service_logs"
 
 	waitForDataDefaultTimeout = 10 * time.Minute
+
+	waitForDataDefaultPollInterval = 1 * time.Second
+
+	waitForTransformDefaultTimeout = 1 * time.Minute
 )
 
+var errTransformNotFound = errors.New("no transform found")
+
 type logsRegexp struct {
 	includes *regexp.Regexp
 	excludes []*regexp.Regexp
+
+	// level is the log level of the messages matching this pattern, messages of any level
+	// match if empty.
+	level string
+}
+
+// describeMatch explains why a log message matching this pattern is reported,
+// so the right exclusion can be added if needed.
+func (p logsRegexp) describeMatch() string {
+	if len(p.excludes) == 0 {
+		return fmt.Sprintf("matched pattern %q, no exclusions defined", p.includes)
+	}
+	excludes := make([]string, len(p.excludes))
+	for i, exclude := range p.excludes {
+		excludes[i] = strconv.Quote(exclude.String())
+	}
+	return fmt.Sprintf("matched pattern %q, not excluded by any of [%s]", p.includes, strings.Join(excludes, ", "))
 }
 
 type logsByContainer struct {
@@ -135,6 +167,7 @@ var (
 	}
 	enableIndependentAgentsEnv   = environment.WithElasticPackagePrefix("TEST_ENABLE_INDEPENDENT_AGENT")
 	dumpScenarioDocsEnv          = environment.WithElasticPackagePrefix("TEST_DUMP_SCENARIO_DOCS")
+	exportIndexTemplateEnv       = environment.WithElasticPackagePrefix("TEST_EXPORT_INDEX_TEMPLATE")
 	fieldValidationTestMethodEnv = environment.WithElasticPackagePrefix("FIELD_VALIDATION_TEST_METHOD")
 )
 
@@ -175,28 +208,46 @@ type tester struct {
 
 	pipelines []ingest.Pipeline
 
-	dataStreamPath     string
-	stackVersion       kibana.VersionInfo
-	locationManager    *locations.LocationManager
-	resourcesManager   *resources.Manager
-	pkgManifest        *packages.PackageManifest
-	dataStreamManifest *packages.DataStreamManifest
-	withCoverage       bool
-	coverageType       string
-	checkFailureStore  bool
+	dataStreamPath       string
+	stackVersion         kibana.VersionInfo
+	locationManager      *locations.LocationManager
+	resourcesManager     *resources.Manager
+	pkgManifest          *packages.PackageManifest
+	dataStreamManifest   *packages.DataStreamManifest
+	withCoverage         bool
+	coverageType         string
+	checkFailureStore    bool
+	agentImage           string
+	strictIgnoredFields  bool
+	failOnMalformed      bool
+	failOnAgentWarnings  bool
+	maxLogSize           uint64
+	printPolicy          bool
+	diagnosticsOnFailure bool
+	validateOnly         string
+	exportDocs           string
+	explain              bool
+	mappingsReport       bool
+
+	// keepAgent keeps the independent Elastic Agents running and enrolled after the tests.
+	keepAgent bool
+
+	// deployedAgent is the agent deployed for the current test, if any.
+	deployedAgent agentdeployer.DeployedAgent
 
 	serviceStateFilePath string
 
 	globalTestConfig testrunner.GlobalRunnerTestConfig
 
 	// Execution order of following handlers is defined in runner.TearDown() method.
-	removeAgentHandler        func(context.Context) error
-	deleteTestPolicyHandler   func(context.Context) error
-	cleanTestScenarioHandler  func(context.Context) error
-	resetAgentPolicyHandler   func(context.Context) error
-	resetAgentLogLevelHandler func(context.Context) error
-	shutdownServiceHandler    func(context.Context) error
-	shutdownAgentHandler      func(context.Context) error
+	removeAgentHandler          func(context.Context) error
+	resetClusterSettingsHandler func(context.Context) error
+	deleteTestPolicyHandler     func(context.Context) error
+	cleanTestScenarioHandler    func(context.Context) error
+	resetAgentPolicyHandler     func(context.Context) error
+	resetAgentLogLevelHandler   func(context.Context) error
+	shutdownServiceHandler      func(context.Context) error
+	shutdownAgentHandler        func(context.Context) error
 }
 
 type SystemTesterOptions struct {
@@ -210,13 +261,25 @@ type SystemTesterOptions struct {
 	// FIXME: Keeping Elasticsearch client to be able to do low-level requests for parameters not supported yet by the API.
 	ESClient *elasticsearch.Client
 
-	DeferCleanup      time.Duration
-	ServiceVariant    string
-	ConfigFileName    string
-	GlobalTestConfig  testrunner.GlobalRunnerTestConfig
-	WithCoverage      bool
-	CoverageType      string
-	CheckFailureStore bool
+	DeferCleanup         time.Duration
+	ServiceVariant       string
+	ConfigFileName       string
+	GlobalTestConfig     testrunner.GlobalRunnerTestConfig
+	WithCoverage         bool
+	CoverageType         string
+	CheckFailureStore    bool
+	AgentImage           string
+	StrictIgnoredFields  bool
+	FailOnMalformed      bool
+	FailOnAgentWarnings  bool
+	MaxLogSize           uint64
+	PrintPolicy          bool
+	DiagnosticsOnFailure bool
+	ValidateOnly         string
+	ExportDocs           string
+	Explain              bool
+	MappingsReport       bool
+	KeepAgent            bool
 
 	RunSetup     bool
 	RunTearDown  bool
@@ -242,6 +305,18 @@ func NewSystemTester(options SystemTesterOptions) (*tester, error) {
 		withCoverage:               options.WithCoverage,
 		coverageType:               options.CoverageType,
 		checkFailureStore:          options.CheckFailureStore,
+		agentImage:                 options.AgentImage,
+		strictIgnoredFields:        options.StrictIgnoredFields,
+		failOnMalformed:            options.FailOnMalformed,
+		failOnAgentWarnings:        options.FailOnAgentWarnings,
+		maxLogSize:                 options.MaxLogSize,
+		printPolicy:                options.PrintPolicy,
+		diagnosticsOnFailure:       options.DiagnosticsOnFailure,
+		validateOnly:               options.ValidateOnly,
+		exportDocs:                 options.ExportDocs,
+		explain:                    options.Explain,
+		mappingsReport:             options.MappingsReport,
+		keepAgent:                  options.KeepAgent,
 		runIndependentElasticAgent: true,
 	}
 	r.resourcesManager = resources.NewManager()
@@ -330,6 +405,14 @@ func (r *tester) Run(ctx context.Context) ([]testrunner.TestResult, error) {
 		return nil, err
 	}
 
+	if r.printPolicy {
+		return r.printPackagePolicy()
+	}
+
+	if r.validateOnly != "" {
+		return r.runValidateOnly(ctx)
+	}
+
 	if !r.runSetup && !r.runTearDown && !r.runTestsOnly {
 		return r.run(ctx, stackConfig)
 	}
@@ -342,11 +425,14 @@ func (r *tester) Run(ctx context.Context) ([]testrunner.TestResult, error) {
 	}
 
 	configFile := filepath.Join(r.testFolder.Path, r.configFileName)
-	testConfig, err := newConfig(configFile, svcInfo, r.serviceVariant)
+	testConfig, err := newConfig(configFile, svcInfo, r.serviceVariant, r.globalTestConfig.Variables)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load system test case file '%s': %w", configFile, err)
 	}
 	logger.Debugf("Using config: %q", testConfig.Name())
+	if len(testConfig.Agent.BaseImages) > 0 {
+		return result.WithErrorf("agent.base_images is not supported with --setup, --tear-down or --no-provision")
+	}
 
 	resultName := ""
 	switch {
@@ -359,7 +445,7 @@ func (r *tester) Run(ctx context.Context) ([]testrunner.TestResult, error) {
 	}
 	result = r.newResult(fmt.Sprintf("%s - %s", resultName, testConfig.Name()))
 
-	scenario, err := r.prepareScenario(ctx, testConfig, stackConfig, svcInfo)
+	scenario, err := r.prepareScenario(ctx, testConfig, stackConfig, svcInfo, newTestHooks(r.packageRootPath, r.globalTestConfig))
 	if r.runSetup && err != nil {
 		tdErr := r.tearDownTest(ctx)
 		if tdErr != nil {
@@ -404,6 +490,79 @@ func (r *tester) Run(ctx context.Context) ([]testrunner.TestResult, error) {
 	return result.WithSuccess()
 }
 
+// selectPolicyTemplate returns the policy template used by the test configuration.
+func (r *tester) selectPolicyTemplate(config *testConfig) (packages.PolicyTemplate, error) {
+	policyTemplateName := config.PolicyTemplate
+	if policyTemplateName == "" {
+		var err error
+		policyTemplateName, err = findPolicyTemplateForInput(*r.pkgManifest, *r.dataStreamManifest, config.Input)
+		if err != nil {
+			return packages.PolicyTemplate{}, fmt.Errorf("failed to determine the associated policy_template: %w", err)
+		}
+	}
+
+	policyTemplate, err := selectPolicyTemplateByName(r.pkgManifest.PolicyTemplates, policyTemplateName)
+	if err != nil {
+		return packages.PolicyTemplate{}, fmt.Errorf("failed to find the selected policy_template: %w", err)
+	}
+	return policyTemplate, nil
+}
+
+// printPackagePolicy prints the package policy that would be added to the test policy, without
+// deploying the service or enrolling any agent. Placeholders that depend on the deployed service
+// are rendered with empty values.
+func (r *tester) printPackagePolicy() ([]testrunner.TestResult, error) {
+	result := r.newResult("(print policy)")
+
+	svcInfo, err := r.createServiceInfo()
+	if err != nil {
+		return result.WithError(err)
+	}
+
+	configFile := filepath.Join(r.testFolder.Path, r.configFileName)
+	config, err := newConfig(configFile, svcInfo, r.serviceVariant, r.globalTestConfig.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load system test case file '%s': %w", configFile, err)
+	}
+	result = r.newResult(fmt.Sprintf("print policy - %s", config.Name()))
+
+	policyTemplate, err := r.selectPolicyTemplate(config)
+	if err != nil {
+		return result.WithError(err)
+	}
+
+	policy := kibana.Policy{
+		ID:        "ep-test-system-policy",
+		Namespace: "ep",
+	}
+	ds := createPackageDatastream(policy, *r.pkgManifest, policyTemplate, *r.dataStreamManifest, *config, policy.Namespace)
+	rendered, err := renderPackagePolicy(ds)
+	if err != nil {
+		return result.WithError(err)
+	}
+
+	fmt.Printf("--- Package policy for %s (config: %s)\n%s\n", r.testFolder.Path, config.Name(), rendered)
+	return result.WithSuccess()
+}
+
+// renderPackagePolicy renders the package policy as YAML.
+func renderPackagePolicy(ds kibana.PackageDataStream) (string, error) {
+	d, err := json.Marshal(ds)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode package policy: %w", err)
+	}
+	var policy common.MapStr
+	err = json.Unmarshal(d, &policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode package policy: %w", err)
+	}
+	d, err = yaml.Marshal(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode package policy as YAML: %w", err)
+	}
+	return string(d), nil
+}
+
 type resourcesOptions struct {
 	installedPackage bool
 }
@@ -425,13 +584,14 @@ func (r *tester) createAgentOptions(policyName string) agentdeployer.FactoryOpti
 	}
 }
 
-func (r *tester) createServiceOptions(variantName string) servicedeployer.FactoryOptions {
+func (r *tester) createServiceOptions(config *testConfig) servicedeployer.FactoryOptions {
 	return servicedeployer.FactoryOptions{
 		Profile:                r.profile,
 		PackageRootPath:        r.packageRootPath,
 		DataStreamRootPath:     r.dataStreamPath,
 		DevDeployDir:           DevDeployDir,
-		Variant:                variantName,
+		Variant:                config.ServiceVariantName,
+		TerraformRegions:       config.Terraform.Regions,
 		Type:                   servicedeployer.TypeTest,
 		StackVersion:           r.stackVersion.Version(),
 		RunTearDown:            r.runTearDown,
@@ -478,6 +638,14 @@ func (r *tester) createAgentInfo(policy *kibana.Policy, config *testConfig, runI
 		return agentdeployer.AgentInfo{}, fmt.Errorf("invalid value for agent.base_image: %q", info.Agent.BaseImage)
 	}
 
+	if r.agentImage != "" {
+		info.Agent.Image = r.agentImage
+		info.Agent.BaseImage = ""
+	}
+	if info.Agent.Image != "" && info.Agent.BaseImage != "" {
+		return agentdeployer.AgentInfo{}, errors.New("agent.image and agent.base_image cannot be used at the same time")
+	}
+
 	return info, nil
 }
 
@@ -488,7 +656,7 @@ func (r *tester) createServiceInfo() (servicedeployer.ServiceInfo, error) {
 	svcInfo.Logs.Folder.Agent = ServiceLogsAgentDir
 	svcInfo.Test.RunID = common.CreateTestRunID()
 
-	if r.runTearDown || r.runTestsOnly {
+	if r.runTearDown || r.runTestsOnly || r.printPolicy || r.validateOnly != "" {
 		logger.Debug("Skip creating output directory")
 	} else {
 		outputDir, err := servicedeployer.CreateOutputDir(r.locationManager, svcInfo.Test.RunID)
@@ -575,6 +743,13 @@ func (r *tester) tearDownTest(ctx context.Context) error {
 		r.deleteTestPolicyHandler = nil
 	}
 
+	if r.resetClusterSettingsHandler != nil {
+		if err := r.resetClusterSettingsHandler(cleanupCtx); err != nil {
+			return err
+		}
+		r.resetClusterSettingsHandler = nil
+	}
+
 	return nil
 }
 
@@ -625,7 +800,11 @@ func (r *tester) run(ctx context.Context, stackConfig stack.Config) (results []t
 		return nil, fmt.Errorf("dump failed: %w", err)
 	}
 
-	logResults, err := r.checkAgentLogs(dump, startTesting, errorPatterns)
+	patterns, err := r.agentLogPatterns()
+	if err != nil {
+		return result.WithError(err)
+	}
+	logResults, err := r.checkAgentLogs(dump, startTesting, patterns)
 	if err != nil {
 		return result.WithError(err)
 	}
@@ -641,12 +820,47 @@ func (r *tester) runTestPerVariant(ctx context.Context, stackConfig stack.Config
 	}
 
 	configFile := filepath.Join(r.testFolder.Path, cfgFile)
-	testConfig, err := newConfig(configFile, svcInfo, variantName)
+	testConfig, err := newConfig(configFile, svcInfo, variantName, r.globalTestConfig.Variables)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load system test case file '%s': %w", configFile, err)
 	}
 	logger.Debugf("Using config: %q", testConfig.Name())
 
+	if len(testConfig.Agent.BaseImages) == 0 {
+		return r.runTestAndTearDown(ctx, testConfig, stackConfig, svcInfo)
+	}
+	if !r.runIndependentElasticAgent {
+		return result.WithErrorf("agent.base_images requires independent Elastic Agents")
+	}
+
+	// Run the test once for each base image, each run with its own service.
+	var results []testrunner.TestResult
+	for i, baseImage := range testConfig.Agent.BaseImages {
+		if i > 0 {
+			svcInfo, err = r.createServiceInfo()
+			if err != nil {
+				partial, err := result.WithError(err)
+				return append(results, partial...), err
+			}
+			testConfig, err = newConfig(configFile, svcInfo, variantName, r.globalTestConfig.Variables)
+			if err != nil {
+				return results, fmt.Errorf("unable to load system test case file '%s': %w", configFile, err)
+			}
+		}
+		testConfig.Agent.BaseImage = baseImage
+		testConfig.BaseImageName = baseImage
+		logger.Debugf("Using config: %q", testConfig.Name())
+
+		partial, err := r.runTestAndTearDown(ctx, testConfig, stackConfig, svcInfo)
+		results = append(results, partial...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func (r *tester) runTestAndTearDown(ctx context.Context, testConfig *testConfig, stackConfig stack.Config, svcInfo servicedeployer.ServiceInfo) ([]testrunner.TestResult, error) {
 	partial, err := r.runTest(ctx, testConfig, stackConfig, svcInfo)
 
 	tdErr := r.tearDownTest(ctx)
@@ -659,19 +873,25 @@ func (r *tester) runTestPerVariant(ctx context.Context, stackConfig stack.Config
 	return partial, nil
 }
 
-func isSyntheticSourceModeEnabled(ctx context.Context, api *elasticsearch.API, dataStreamName string) (bool, error) {
+// dataStreamModes contains the modes of a data stream that affect how its documents are stored.
+type dataStreamModes struct {
+	indexMode       string
+	syntheticSource bool
+}
+
+func getDataStreamModes(ctx context.Context, api *elasticsearch.API, dataStreamName string) (dataStreamModes, error) {
 	// We append a suffix so we don't use an existing resource, what may cause conflicts in old versions of
 	// Elasticsearch, such as https://github.com/elastic/elasticsearch/issues/84256.
 	resp, err := api.Indices.SimulateIndexTemplate(dataStreamName+"simulated",
 		api.Indices.SimulateIndexTemplate.WithContext(ctx),
 	)
 	if err != nil {
-		return false, fmt.Errorf("could not simulate index template for %s: %w", dataStreamName, err)
+		return dataStreamModes{}, fmt.Errorf("could not simulate index template for %s: %w", dataStreamName, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.IsError() {
-		return false, fmt.Errorf("could not simulate index template for %s: %s", dataStreamName, resp.String())
+		return dataStreamModes{}, fmt.Errorf("could not simulate index template for %s: %s", dataStreamName, resp.String())
 	}
 
 	var results struct {
@@ -690,11 +910,15 @@ func isSyntheticSourceModeEnabled(ctx context.Context, api *elasticsearch.API, d
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return false, fmt.Errorf("could not decode index template simulation response: %w", err)
+		return dataStreamModes{}, fmt.Errorf("could not decode index template simulation response: %w", err)
 	}
 
+	modes := dataStreamModes{
+		indexMode: results.Template.Settings.Index.Mode,
+	}
 	if results.Template.Mappings.Source.Mode == "synthetic" {
-		return true, nil
+		modes.syntheticSource = true
+		return modes, nil
 	}
 
 	// It seems that some index modes enable synthetic source mode even when it is not explicitly mentioned
@@ -704,11 +928,11 @@ func isSyntheticSourceModeEnabled(ctx context.Context, api *elasticsearch.API, d
 		"logsdb",
 		"time_series",
 	}
-	if slices.Contains(syntheticsIndexModes, results.Template.Settings.Index.Mode) {
-		return true, nil
+	if slices.Contains(syntheticsIndexModes, modes.indexMode) {
+		modes.syntheticSource = true
 	}
 
-	return false, nil
+	return modes, nil
 }
 
 type hits struct {
@@ -716,6 +940,9 @@ type hits struct {
 	Fields        []common.MapStr `json:"fields"`
 	IgnoredFields []string
 	DegradedDocs  []common.MapStr
+
+	// IgnoredFieldsCount contains the number of documents where each ignored field was ignored.
+	IgnoredFieldsCount map[string]int
 }
 
 func (h hits) getDocs(syntheticsEnabled bool) []common.MapStr {
@@ -768,7 +995,8 @@ func (r *tester) getDocs(ctx context.Context, dataStream string) (*hits, error)
 				DocCount      int `json:"doc_count"`
 				IgnoredFields struct {
 					Buckets []struct {
-						Key string `json:"key"`
+						Key      string `json:"key"`
+						DocCount int    `json:"doc_count"`
 					} `json:"buckets"`
 				} `json:"ignored_fields"`
 				IgnoredDocs struct {
@@ -802,8 +1030,10 @@ func (r *tester) getDocs(ctx context.Context, dataStream string) (*hits, error)
 		hits.Source = append(hits.Source, hit.Source)
 		hits.Fields = append(hits.Fields, hit.Fields)
 	}
+	hits.IgnoredFieldsCount = make(map[string]int)
 	for _, bucket := range results.Aggregations.AllIgnored.IgnoredFields.Buckets {
 		hits.IgnoredFields = append(hits.IgnoredFields, bucket.Key)
+		hits.IgnoredFieldsCount[bucket.Key] = bucket.DocCount
 	}
 	hits.DegradedDocs = results.Aggregations.AllIgnored.IgnoredDocs.Hits.Hits
 
@@ -982,13 +1212,17 @@ type scenarioTest struct {
 	policyTemplateName  string
 	kibanaDataStream    kibana.PackageDataStream
 	syntheticEnabled    bool
+	indexMode           string
 	docs                []common.MapStr
 	failureStore        []failureStoreDocument
 	deprecationWarnings []deprecationWarning
 	ignoredFields       []string
+	ignoredFieldsCount  map[string]int
 	degradedDocs        []common.MapStr
 	agent               agentdeployer.DeployedAgent
+	svcInfo             servicedeployer.ServiceInfo
 	startTestTime       time.Time
+	pipelineCounts      map[string]int
 }
 
 type pipelineTrace []string
@@ -1023,26 +1257,8 @@ type failureStoreDocument struct {
 	} `json:"error"`
 }
 
-func (r *tester) deleteDataStream(ctx context.Context, dataStream string) error {
-	resp, err := r.esAPI.Indices.DeleteDataStream([]string{dataStream},
-		r.esAPI.Indices.DeleteDataStream.WithContext(ctx),
-	)
-	if err != nil {
-		return fmt.Errorf("delete request failed for data stream %s: %w", dataStream, err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusNotFound {
-		// Data stream doesn't exist, there was nothing to do.
-		return nil
-	}
-	if resp.IsError() {
-		return fmt.Errorf("delete request failed for data stream %s: %s", dataStream, resp.String())
-	}
-	return nil
-}
-
-func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackConfig stack.Config, svcInfo servicedeployer.ServiceInfo) (*scenarioTest, error) {
-	serviceOptions := r.createServiceOptions(config.ServiceVariantName)
+func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackConfig stack.Config, svcInfo servicedeployer.ServiceInfo, hooks *testHooks) (*scenarioTest, error) {
+	serviceOptions := r.createServiceOptions(config)
 
 	var err error
 	var serviceStateData ServiceState
@@ -1063,19 +1279,35 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 
 	serviceOptions.DeployIndependentAgent = r.runIndependentElasticAgent
 
-	policyTemplateName := config.PolicyTemplate
-	if policyTemplateName == "" {
-		policyTemplateName, err = findPolicyTemplateForInput(*r.pkgManifest, *r.dataStreamManifest, config.Input)
+	var origClusterSettings common.MapStr
+	if r.runTearDown || r.runTestsOnly {
+		origClusterSettings = serviceStateData.OrigClusterSettings
+	} else if len(config.ClusterSettings) > 0 {
+		origClusterSettings, err = getPersistentClusterSettings(ctx, r.esAPI, config.ClusterSettings)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("applying cluster settings...")
+		err = putPersistentClusterSettings(ctx, r.esAPI, config.ClusterSettings)
 		if err != nil {
-			return nil, fmt.Errorf("failed to determine the associated policy_template: %w", err)
+			return nil, err
+		}
+	}
+
+	r.resetClusterSettingsHandler = func(ctx context.Context) error {
+		// Settings are kept till the tear down stage when running stages separately.
+		if r.runSetup || r.runTestsOnly || len(origClusterSettings) == 0 {
+			return nil
 		}
+		logger.Debug("restoring cluster settings...")
+		return putPersistentClusterSettings(ctx, r.esAPI, origClusterSettings)
 	}
-	scenario.policyTemplateName = policyTemplateName
 
-	policyTemplate, err := selectPolicyTemplateByName(r.pkgManifest.PolicyTemplates, scenario.policyTemplateName)
+	policyTemplate, err := r.selectPolicyTemplate(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find the selected policy_template: %w", err)
+		return nil, err
 	}
+	scenario.policyTemplateName = policyTemplate.Name
 
 	// Configure package (single data stream) via Fleet APIs.
 	testTime := time.Now().Format("20060102T15:04:05Z")
@@ -1139,6 +1371,10 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 		if stackConfig.OutputID != "" {
 			policy.DataOutputID = stackConfig.OutputID
 		}
+		// The Kafka output is created with the stack, use it if no other output is selected.
+		if policy.DataOutputID == "" {
+			policy.DataOutputID = stack.KafkaFleetOutputID(r.profile)
+		}
 		policyToTest, err = r.kibanaClient.CreatePolicy(ctx, policy)
 		if err != nil {
 			return nil, fmt.Errorf("could not create test policy: %w", err)
@@ -1146,17 +1382,7 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 	}
 
 	r.deleteTestPolicyHandler = func(ctx context.Context) error {
-		logger.Debug("deleting test policies...")
-		if err := r.kibanaClient.DeletePolicy(ctx, policyToTest.ID); err != nil {
-			return fmt.Errorf("error cleaning up test policy: %w", err)
-		}
-		if r.runTestsOnly {
-			return nil
-		}
-		if err := r.kibanaClient.DeletePolicy(ctx, policyToEnroll.ID); err != nil {
-			return fmt.Errorf("error cleaning up test policy: %w", err)
-		}
-		return nil
+		return r.deleteTestPolicies(ctx, policyToTest, policyToEnroll)
 	}
 
 	// policyToEnroll is used in both independent agents and agents created by servicedeployer (custom or kubernetes agents)
@@ -1182,10 +1408,18 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 	}
 
 	// Reload test config with ctx variable substitution.
-	config, err = newConfig(config.Path, svcInfo, serviceOptions.Variant)
+	config, err = newConfig(config.Path, svcInfo, serviceOptions.Variant, r.globalTestConfig.Variables)
 	if err != nil {
 		return nil, fmt.Errorf("unable to reload system test case configuration: %w", err)
 	}
+	scenario.svcInfo = svcInfo
+
+	if !r.runTearDown {
+		err = hooks.runBeforeTest(ctx, svcInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// store the time just before adding the Test Policy, this time will be used to check
 	// the agent logs from that time onwards to avoid possible previous errors present in logs
@@ -1224,9 +1458,17 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 		ds.Namespace,
 	)
 
+	if config.Assert.PipelineVersion {
+		// Documents processed by each pipeline before the test, to check later the pipelines used in the test.
+		scenario.pipelineCounts, err = getPipelineIngestCounts(ctx, r.esAPI, scenario.indexTemplateName+"-")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	r.cleanTestScenarioHandler = func(ctx context.Context) error {
 		logger.Debugf("Deleting data stream for testing %s", scenario.dataStream)
-		err := r.deleteDataStream(ctx, scenario.dataStream)
+		err := r.esClient.DeleteDataStream(ctx, scenario.dataStream)
 		if err != nil {
 			return fmt.Errorf("failed to delete data stream %s: %w", scenario.dataStream, err)
 		}
@@ -1252,6 +1494,10 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 		if !r.runIndependentElasticAgent && !svcInfo.Agent.Independent {
 			return nil
 		}
+		if r.keepAgent {
+			r.printKeptAgent(agent)
+			return nil
+		}
 		logger.Debug("removing agent...")
 		err := r.kibanaClient.RemoveAgent(ctx, agent)
 		if err != nil {
@@ -1272,24 +1518,7 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 	}
 
 	r.resetAgentPolicyHandler = func(ctx context.Context) error {
-		if r.runSetup {
-			// it should be kept the same policy just when system tests are
-			// triggered with the flags for running spolicyToAssignDatastreamTestsetup stage (--setup)
-			return nil
-		}
-
-		// RunTestOnly step (--no-provision) should also reassign back the previous (original) policy
-		// even with with independent Elastic Agents, since this step creates a new test policy each execution
-		// Moreover, ensure there is no agent service deployer (deprecated) being used
-		if scenario.agent != nil && r.runIndependentElasticAgent && !r.runTestsOnly {
-			return nil
-		}
-
-		logger.Debug("reassigning original policy back to agent...")
-		if err := r.kibanaClient.AssignPolicyToAgent(ctx, agent, origPolicy); err != nil {
-			return fmt.Errorf("error reassigning original policy to agent: %w", err)
-		}
-		return nil
+		return r.resetAgentPolicy(ctx, agent, origPolicy, scenario.agent != nil)
 	}
 
 	origAgent := agent
@@ -1350,11 +1579,25 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 		return &scenario, nil
 	}
 
+	err = r.waitForInputReadiness(ctx, config, agent)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use custom timeout if the service can't collect data immediately.
 	waitForDataTimeout := waitForDataDefaultTimeout
 	if config.WaitForDataTimeout > 0 {
 		waitForDataTimeout = config.WaitForDataTimeout
 	}
+	waitForDataPollInterval := waitForDataDefaultPollInterval
+	if config.WaitForDataPollInterval > 0 {
+		waitForDataPollInterval = config.WaitForDataPollInterval
+	}
+
+	err = waitForTestHook(ctx, r.packageRootPath, r.globalTestConfig.WaitFor, scenario.svcInfo, waitForDataTimeout)
+	if err != nil {
+		return nil, err
+	}
 
 	// (TODO in future) Optionally exercise service to generate load.
 	logger.Debugf("checking for expected data in data stream (%s)...", waitForDataTimeout)
@@ -1362,6 +1605,7 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 	oldHits := 0
 	passed, waitErr := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
 		var err error
+		failureStoreCount := 0
 		hits, err = r.getDocs(ctx, scenario.dataStream)
 		if err != nil {
 			return false, err
@@ -1372,11 +1616,21 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 			if err != nil {
 				return false, fmt.Errorf("failed to check failure store: %w", err)
 			}
-			if n := len(failureStore); n > 0 {
+			if n := len(failureStore); n > 0 && config.Assert.FailedCount == 0 {
 				// Interrupt loop earlier if there are failures in the document store.
 				logger.Debugf("Found %d hits in the failure store for %s", len(failureStore), scenario.dataStream)
 				return true, nil
 			}
+			failureStoreCount = len(failureStore)
+		}
+
+		if config.Assert.FailedCount > 0 {
+			// Negative tests wait for the expected number of failed documents.
+			failedCount := countDocsWithErrorMessage(hits.Source) + failureStoreCount
+			if failedCount < config.Assert.FailedCount {
+				return false, nil
+			}
+			return config.Assert.HitCount == 0 || hits.size() >= config.Assert.HitCount, nil
 		}
 
 		if config.Assert.HitCount > 0 {
@@ -1394,7 +1648,7 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 		}
 
 		return hits.size() > 0, nil
-	}, 1*time.Second, waitForDataTimeout)
+	}, waitForDataPollInterval, waitForDataTimeout)
 
 	if service != nil && config.Service != "" && !config.IgnoreServiceError {
 		exited, code, err := service.ExitCode(ctx, config.Service)
@@ -1411,7 +1665,8 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 	}
 
 	if !passed {
-		return nil, testrunner.ErrTestCaseFailed{Reason: fmt.Sprintf("could not find hits in %s data stream", scenario.dataStream)}
+		reason := fmt.Sprintf("could not find hits in %s data stream", scenario.dataStream)
+		return nil, testrunner.ErrTestCaseFailed{Reason: withFailureHint(reason, config.Assert.FailureHint)}
 	}
 
 	// Get deprecation warnings after ensuring that there are ingested docs and thus the
@@ -1422,15 +1677,23 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 	}
 	logger.Debugf("Found %d deprecation warnings for data stream %s", len(scenario.deprecationWarnings), scenario.dataStream)
 
-	logger.Debugf("Check whether or not synthetic source mode is enabled (data stream %s)...", scenario.dataStream)
-	scenario.syntheticEnabled, err = isSyntheticSourceModeEnabled(ctx, r.esAPI, scenario.dataStream)
+	logger.Debugf("Check the index mode and whether or not synthetic source mode is enabled (data stream %s)...", scenario.dataStream)
+	modes, err := getDataStreamModes(ctx, r.esAPI, scenario.dataStream)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if synthetic source mode is enabled for data stream %s: %w", scenario.dataStream, err)
+		return nil, fmt.Errorf("failed to check the modes of data stream %s: %w", scenario.dataStream, err)
+	}
+	scenario.indexMode = modes.indexMode
+	if config.SyntheticSource != nil {
+		logger.Debugf("Skip synthetic source mode detection, configured in test (data stream %s)", scenario.dataStream)
+		scenario.syntheticEnabled = *config.SyntheticSource
+	} else {
+		scenario.syntheticEnabled = modes.syntheticSource
 	}
-	logger.Debugf("Data stream %s has synthetic source mode enabled: %t", scenario.dataStream, scenario.syntheticEnabled)
+	logger.Debugf("Data stream %s has index mode %q and synthetic source mode enabled: %t", scenario.dataStream, scenario.indexMode, scenario.syntheticEnabled)
 
 	scenario.docs = hits.getDocs(scenario.syntheticEnabled)
 	scenario.ignoredFields = hits.IgnoredFields
+	scenario.ignoredFieldsCount = hits.IgnoredFieldsCount
 	scenario.degradedDocs = hits.DegradedDocs
 	if r.checkFailureStore {
 		logger.Debugf("Checking failure store for data stream %s", scenario.dataStream)
@@ -1450,6 +1713,8 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, stackC
 			agent:         origAgent,
 			agentInfo:     agentInfo,
 			svcInfo:       svcInfo,
+
+			origClusterSettings: origClusterSettings,
 		}
 		err = writeScenarioState(opts, r.serviceStateFilePath)
 		if err != nil {
@@ -1472,6 +1737,7 @@ func (r *tester) setupService(ctx context.Context, config *testConfig, serviceOp
 	if agentDeployed != nil {
 		svcInfo.AgentNetworkName = agentInfo.NetworkName
 	}
+	svcInfo.AdditionalNetworkNames = config.ServiceNetworks
 
 	// Set the right folder for logs except for custom agents that are still deployed using "servicedeployer"
 	if r.runIndependentElasticAgent && agentDeployed != nil {
@@ -1542,8 +1808,9 @@ func (r *tester) setupAgent(ctx context.Context, config *testConfig, state Servi
 	if err != nil {
 		return nil, agentInfo, fmt.Errorf("could not setup agent: %w", err)
 	}
+	r.deployedAgent = agentDeployed
 	r.shutdownAgentHandler = func(ctx context.Context) error {
-		if r.runTestsOnly {
+		if r.runTestsOnly || r.keepAgent {
 			return nil
 		}
 		if agentDeployer == nil {
@@ -1559,6 +1826,59 @@ func (r *tester) setupAgent(ctx context.Context, config *testConfig, state Servi
 	return agentDeployed, agentDeployed.Info(), nil
 }
 
+// resetAgentPolicy reassigns the original policy back to the agent. independentAgent must be true
+// when the agent was deployed by the agent deployer.
+func (r *tester) resetAgentPolicy(ctx context.Context, agent kibana.Agent, origPolicy kibana.Policy, independentAgent bool) error {
+	if r.runSetup {
+		// it should be kept the same policy just when system tests are
+		// triggered with the flags for running spolicyToAssignDatastreamTestsetup stage (--setup)
+		return nil
+	}
+
+	// RunTestOnly step (--no-provision) should also reassign back the previous (original) policy
+	// even with with independent Elastic Agents, since this step creates a new test policy each execution
+	// Moreover, ensure there is no agent service deployer (deprecated) being used
+	// Kept agents are also moved back to their enroll policy, so the test policy and the package
+	// are not in use anymore when they are deleted.
+	if independentAgent && r.runIndependentElasticAgent && !r.runTestsOnly && !r.keepAgent {
+		return nil
+	}
+
+	logger.Debug("reassigning original policy back to agent...")
+	if err := r.kibanaClient.AssignPolicyToAgent(ctx, agent, origPolicy); err != nil {
+		return fmt.Errorf("error reassigning original policy to agent: %w", err)
+	}
+	return nil
+}
+
+// deleteTestPolicies deletes the policies created for the test. The enroll policy is kept when
+// the agent is kept, as the agent is still enrolled with it.
+func (r *tester) deleteTestPolicies(ctx context.Context, policyToTest, policyToEnroll *kibana.Policy) error {
+	logger.Debug("deleting test policies...")
+	if err := r.kibanaClient.DeletePolicy(ctx, policyToTest.ID); err != nil {
+		return fmt.Errorf("error cleaning up test policy: %w", err)
+	}
+	if r.runTestsOnly || r.keepAgent {
+		return nil
+	}
+	if err := r.kibanaClient.DeletePolicy(ctx, policyToEnroll.ID); err != nil {
+		return fmt.Errorf("error cleaning up test policy: %w", err)
+	}
+	return nil
+}
+
+// printKeptAgent prints the details needed to access an agent kept running after the tests.
+func (r *tester) printKeptAgent(agent kibana.Agent) {
+	containerName := "unknown"
+	if r.deployedAgent != nil && r.deployedAgent.Info().ContainerName != "" {
+		containerName = r.deployedAgent.Info().ContainerName
+	}
+	logger.Infof("Keeping Elastic Agent running (container: %s, Fleet agent ID: %s)", containerName, agent.ID)
+	if containerName != "unknown" {
+		logger.Infof("Access it with: docker exec -it %s bash", containerName)
+	}
+}
+
 func (r *tester) removeServiceStateFile() error {
 	err := os.Remove(r.serviceStateFilePath)
 	if err != nil {
@@ -1577,63 +1897,37 @@ func (r *tester) createServiceStateDir() error {
 }
 
 func (r *tester) validateTestScenario(ctx context.Context, result *testrunner.ResultComposer, scenario *scenarioTest, config *testConfig) ([]testrunner.TestResult, error) {
-	if err := validateFailureStore(scenario.failureStore); err != nil {
-		return result.WithError(err)
-	}
-
-	// Validate fields in docs
-	// when reroute processors are used, expectedDatasets should be set depends on the processor config
-	var expectedDatasets []string
-	for _, pipeline := range r.pipelines {
-		var esIngestPipeline map[string]any
-		err := yaml.Unmarshal(pipeline.Content, &esIngestPipeline)
+	if dir, ok := os.LookupEnv(exportIndexTemplateEnv); ok && dir != "" {
+		err := r.exportIndexTemplate(ctx, scenario.indexTemplateName, dir)
 		if err != nil {
-			return nil, fmt.Errorf("unmarshalling ingest pipeline content failed: %w", err)
-		}
-		processors, _ := esIngestPipeline["processors"].([]any)
-		for _, p := range processors {
-			processor, ok := p.(map[string]any)
-			if !ok {
-				return nil, fmt.Errorf("unexpected processor %+v", p)
-			}
-			if reroute, ok := processor["reroute"]; ok {
-				if rerouteP, ok := reroute.(ingest.RerouteProcessor); ok {
-					expectedDatasets = append(expectedDatasets, rerouteP.Dataset...)
-				}
-			}
+			return nil, fmt.Errorf("failed to export index template: %w", err)
 		}
 	}
 
-	if expectedDatasets == nil {
-		var expectedDataset string
-		if ds := r.testFolder.DataStream; ds != "" {
-			expectedDataset = getDataStreamDataset(*r.pkgManifest, *r.dataStreamManifest)
-		} else {
-			expectedDataset = r.pkgManifest.Name + "." + scenario.policyTemplateName
+	// Negative tests expect failed documents, so they are not reported as errors.
+	expectFailures := config.Assert.FailedCount > 0
+	if !expectFailures {
+		if err := validateFailureStore(scenario.failureStore); err != nil {
+			return result.WithError(err)
 		}
-		expectedDatasets = []string{expectedDataset}
 	}
-	if r.pkgManifest.Type == "input" {
-		v, _ := config.Vars.GetValue("data_stream.dataset")
-		if dataset, ok := v.(string); ok && dataset != "" {
-			expectedDatasets = append(expectedDatasets, dataset)
-		}
+
+	indexTemplate, err := ingest.GetIndexTemplate(ctx, r.esAPI, scenario.indexTemplateName)
+	if err != nil {
+		return result.WithErrorf("failed to check priority of index template: %w", err)
+	}
+	if err := validateIndexTemplatePriority(indexTemplate); err != nil {
+		return result.WithError(err)
 	}
 
-	fieldsValidator, err := fields.CreateValidatorForDirectory(r.dataStreamPath,
-		fields.WithSpecVersion(r.pkgManifest.SpecVersion),
-		fields.WithNumericKeywordFields(config.NumericKeywordFields),
-		fields.WithStringNumberFields(config.StringNumberFields),
-		fields.WithExpectedDatasets(expectedDatasets),
-		fields.WithEnabledImportAllECSSChema(true),
-		fields.WithDisableNormalization(scenario.syntheticEnabled),
-	)
+	// Validate fields in docs
+	fieldsValidator, err := r.createFieldsValidator(scenario, config)
 	if err != nil {
-		return result.WithErrorf("creating fields validator for data stream failed (path: %s): %w", r.dataStreamPath, err)
+		return result.WithError(err)
 	}
 
 	if r.fieldValidationMethod == allMethods || r.fieldValidationMethod == fieldsMethod {
-		if errs := validateFields(scenario.docs, fieldsValidator); len(errs) > 0 {
+		if errs := validateFields(scenario.docs, fieldsValidator, expectFailures); len(errs) > 0 {
 			return result.WithError(testrunner.ErrTestCaseFailed{
 				Reason:  fmt.Sprintf("one or more errors found in documents stored in %s data stream", scenario.dataStream),
 				Details: errs.Error(),
@@ -1646,26 +1940,23 @@ func (r *tester) validateTestScenario(ctx context.Context, result *testrunner.Re
 		return result.WithErrorf("failed to parse stack version: %w", err)
 	}
 
-	err = validateIgnoredFields(stackVersion, scenario, config)
+	err = r.validateIgnoreMalformedFields(ctx, scenario)
+	if err != nil {
+		return result.WithError(err)
+	}
+
+	err = validateIgnoredFields(stackVersion, scenario, config, r.strictIgnoredFields)
 	if err != nil {
 		return result.WithError(err)
 	}
 
 	if r.fieldValidationMethod == allMethods || r.fieldValidationMethod == mappingsMethod {
 		logger.Warn("Validate mappings found (technical preview)")
-		exceptionFields := listExceptionFields(scenario.docs, fieldsValidator)
-
-		mappingsValidator, err := fields.CreateValidatorForMappings(r.esClient,
-			fields.WithMappingValidatorFallbackSchema(fieldsValidator.Schema),
-			fields.WithMappingValidatorIndexTemplate(scenario.indexTemplateName),
-			fields.WithMappingValidatorDataStream(scenario.dataStream),
-			fields.WithMappingValidatorExceptionFields(exceptionFields),
-		)
+		errs, err := r.validateScenarioMappings(ctx, scenario, fieldsValidator)
 		if err != nil {
-			return result.WithErrorf("creating mappings validator for data stream failed (data stream: %s): %w", scenario.dataStream, err)
+			return result.WithError(err)
 		}
-
-		if errs := validateMappings(ctx, mappingsValidator); len(errs) > 0 {
+		if len(errs) > 0 {
 			return result.WithError(testrunner.ErrTestCaseFailed{
 				Reason:  fmt.Sprintf("one or more errors found in mappings in %s index template", scenario.indexTemplateName),
 				Details: errs.Error(),
@@ -1694,31 +1985,78 @@ func (r *tester) validateTestScenario(ctx context.Context, result *testrunner.Re
 
 	// Check Hit Count within docs, if 0 then it has not been specified
 	if assertionPass, message := assertHitCount(config.Assert.HitCount, docs); !assertionPass {
-		result.FailureMsg = message
+		result.FailureMsg = withFailureHint(message, config.Assert.FailureHint)
 	}
 
-	// Check transforms if present
-	if err := r.checkTransforms(ctx, config, r.pkgManifest, scenario.kibanaDataStream, scenario.dataStream, scenario.syntheticEnabled); err != nil {
-		results, _ := result.WithError(err)
-		return results, nil
+	// Check aggregated values of fields within docs
+	if assertionPass, message := assertAggregations(config.Assert.Aggregations, docs); !assertionPass {
+		addFailureMessage(result, message)
 	}
 
-	if scenario.agent != nil {
-		logResults, err := r.checkNewAgentLogs(ctx, scenario.agent, scenario.startTestTime, errorPatterns, config.Name())
+	// Check count of failed docs, if 0 then it has not been specified
+	if assertionPass, message := assertFailedCount(config.Assert.FailedCount, scenario.docs, scenario.failureStore); !assertionPass {
+		addFailureMessage(result, message)
+	}
+
+	// Check that searches in multi-fields find the docs
+	if len(config.Assert.MultiFieldSearches) > 0 {
+		assertionPass, message, err := r.assertMultiFieldSearches(ctx, scenario.dataStream, config.Assert.MultiFieldSearches, docs)
 		if err != nil {
 			return result.WithError(err)
 		}
-		if len(logResults) > 0 {
-			return logResults, nil
+		if !assertionPass {
+			addFailureMessage(result, message)
 		}
 	}
 
-	if results := r.checkDeprecationWarnings(stackVersion, scenario.dataStream, scenario.deprecationWarnings, config.Name()); len(results) > 0 {
-		return results, nil
+	// Check that fields don't have more unique values than expected
+	if len(config.Assert.CardinalityLimits) > 0 {
+		assertionPass, message, err := r.assertCardinality(ctx, scenario.dataStream, config.Assert.CardinalityLimits)
+		if err != nil {
+			return result.WithError(err)
+		}
+		if !assertionPass {
+			addFailureMessage(result, message)
+		}
 	}
 
-	if r.withCoverage {
-		coverage, err := r.generateCoverageReport(result.CoveragePackageName())
+	// Check version of the pipelines used to ingest the docs, if enabled
+	if config.Assert.PipelineVersion {
+		pipelineCounts, err := getPipelineIngestCounts(ctx, r.esAPI, scenario.indexTemplateName+"-")
+		if err != nil {
+			return result.WithError(err)
+		}
+		if assertionPass, message := assertPipelineVersion(scenario.indexTemplateName, r.pkgManifest.Version, scenario.pipelineCounts, pipelineCounts); !assertionPass {
+			addFailureMessage(result, message)
+		}
+	}
+
+	// Check transforms if present
+	if err := r.checkTransforms(ctx, config, r.pkgManifest, scenario.kibanaDataStream, scenario.dataStream, scenario.syntheticEnabled); err != nil {
+		results, _ := result.WithError(err)
+		return results, nil
+	}
+
+	if scenario.agent != nil {
+		patterns, err := r.agentLogPatterns()
+		if err != nil {
+			return result.WithError(err)
+		}
+		logResults, err := r.checkNewAgentLogs(ctx, scenario.agent, scenario.startTestTime, patterns, config.Name())
+		if err != nil {
+			return result.WithError(err)
+		}
+		if len(logResults) > 0 {
+			return logResults, nil
+		}
+	}
+
+	if results := r.checkDeprecationWarnings(stackVersion, scenario.dataStream, scenario.deprecationWarnings, config.Name()); len(results) > 0 {
+		return results, nil
+	}
+
+	if r.withCoverage {
+		coverage, err := r.generateCoverageReport(result.CoveragePackageName(), true)
 		if err != nil {
 			return result.WithErrorf("coverage report generation failed: %w", err)
 		}
@@ -1728,34 +2066,359 @@ func (r *tester) validateTestScenario(ctx context.Context, result *testrunner.Re
 	return result.WithSuccess()
 }
 
-func (r *tester) runTest(ctx context.Context, config *testConfig, stackConfig stack.Config, svcInfo servicedeployer.ServiceInfo) ([]testrunner.TestResult, error) {
+// runValidateOnly validates the fields and mappings of the documents already ingested in an
+// existing data stream, without deploying services or agents.
+func (r *tester) runValidateOnly(ctx context.Context) ([]testrunner.TestResult, error) {
+	result := r.newResult(fmt.Sprintf("validate only - %s", r.validateOnly))
+
+	svcInfo, err := r.createServiceInfo()
+	if err != nil {
+		return result.WithError(err)
+	}
+
+	configFile := filepath.Join(r.testFolder.Path, r.configFileName)
+	config, err := newConfig(configFile, svcInfo, r.serviceVariant, r.globalTestConfig.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load system test case file '%s': %w", configFile, err)
+	}
+
+	dataStreamType, dataset, _, err := parseDataStreamName(r.validateOnly)
+	if err != nil {
+		return result.WithError(err)
+	}
+	scenario := scenarioTest{
+		dataStream:        r.validateOnly,
+		indexTemplateName: fmt.Sprintf("%s-%s", dataStreamType, dataset),
+	}
+	if r.pkgManifest.Type == "input" {
+		policyTemplate, err := r.selectPolicyTemplate(config)
+		if err != nil {
+			return result.WithError(err)
+		}
+		scenario.policyTemplateName = policyTemplate.Name
+	}
+
+	hits, err := r.getDocs(ctx, scenario.dataStream)
+	if err != nil {
+		return result.WithErrorf("failed to get documents from data stream %s: %w", scenario.dataStream, err)
+	}
+	if hits.size() == 0 {
+		return result.WithError(testrunner.ErrTestCaseFailed{
+			Reason: fmt.Sprintf("no documents found in data stream %s", scenario.dataStream),
+		})
+	}
+
+	modes, err := getDataStreamModes(ctx, r.esAPI, scenario.dataStream)
+	if err != nil {
+		return result.WithErrorf("failed to check the modes of the data stream: %w", err)
+	}
+	scenario.indexMode = modes.indexMode
+	if config.SyntheticSource != nil {
+		scenario.syntheticEnabled = *config.SyntheticSource
+	} else {
+		scenario.syntheticEnabled = modes.syntheticSource
+	}
+	scenario.docs = hits.getDocs(scenario.syntheticEnabled)
+	logger.Debugf("Validating %d documents from data stream %s", len(scenario.docs), scenario.dataStream)
+
+	fieldsValidator, err := r.createFieldsValidator(&scenario, config)
+	if err != nil {
+		return result.WithError(err)
+	}
+
+	if r.fieldValidationMethod == allMethods || r.fieldValidationMethod == fieldsMethod {
+		if errs := validateFields(scenario.docs, fieldsValidator, config.Assert.FailedCount > 0); len(errs) > 0 {
+			return result.WithError(testrunner.ErrTestCaseFailed{
+				Reason:  fmt.Sprintf("one or more errors found in documents stored in %s data stream", scenario.dataStream),
+				Details: errs.Error(),
+			})
+		}
+	}
+
+	if r.fieldValidationMethod == allMethods || r.fieldValidationMethod == mappingsMethod {
+		errs, err := r.validateScenarioMappings(ctx, &scenario, fieldsValidator)
+		if err != nil {
+			return result.WithError(err)
+		}
+		if len(errs) > 0 {
+			return result.WithError(testrunner.ErrTestCaseFailed{
+				Reason:  fmt.Sprintf("one or more errors found in mappings in %s index template", scenario.indexTemplateName),
+				Details: errs.Error(),
+			})
+		}
+	}
+
+	return result.WithSuccess()
+}
+
+// parseDataStreamName splits the name of a data stream in its type, dataset and namespace.
+func parseDataStreamName(name string) (dataStreamType, dataset, namespace string, err error) {
+	dataStreamType, rest, found := strings.Cut(name, "-")
+	if !found || dataStreamType == "" {
+		return "", "", "", fmt.Errorf("invalid data stream name %q, expected <type>-<dataset>-<namespace>", name)
+	}
+	i := strings.LastIndex(rest, "-")
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", "", fmt.Errorf("invalid data stream name %q, expected <type>-<dataset>-<namespace>", name)
+	}
+	return dataStreamType, rest[:i], rest[i+1:], nil
+}
+
+// createFieldsValidator creates the validator for the fields of the documents of the scenario.
+func (r *tester) createFieldsValidator(scenario *scenarioTest, config *testConfig) (*fields.Validator, error) {
+	// when reroute processors are used, expectedDatasets should be set depends on the processor config
+	var expectedDatasets []string
+	for _, pipeline := range r.pipelines {
+		var esIngestPipeline map[string]any
+		err := yaml.Unmarshal(pipeline.Content, &esIngestPipeline)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling ingest pipeline content failed: %w", err)
+		}
+		processors, _ := esIngestPipeline["processors"].([]any)
+		for _, p := range processors {
+			processor, ok := p.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("unexpected processor %+v", p)
+			}
+			if reroute, ok := processor["reroute"]; ok {
+				if rerouteP, ok := reroute.(ingest.RerouteProcessor); ok {
+					expectedDatasets = append(expectedDatasets, rerouteP.Dataset...)
+				}
+			}
+		}
+	}
+
+	if expectedDatasets == nil {
+		var expectedDataset string
+		if ds := r.testFolder.DataStream; ds != "" {
+			expectedDataset = getDataStreamDataset(*r.pkgManifest, *r.dataStreamManifest)
+		} else {
+			expectedDataset = inputPackageDataset(r.pkgManifest.Name, scenario.policyTemplateName, *config)
+		}
+		expectedDatasets = []string{expectedDataset}
+	}
+	expectedDatasets = append(expectedDatasets, config.ExpectedDatasets...)
+	if r.pkgManifest.Type == "input" {
+		v, _ := config.Vars.GetValue("data_stream.dataset")
+		if dataset, ok := v.(string); ok && dataset != "" {
+			expectedDatasets = append(expectedDatasets, dataset)
+		}
+	}
+
+	fieldsValidator, err := fields.CreateValidatorForDirectory(r.dataStreamPath,
+		fields.WithSpecVersion(r.pkgManifest.SpecVersion),
+		fields.WithNumericKeywordFields(config.NumericKeywordFields),
+		fields.WithStringNumberFields(config.StringNumberFields),
+		fields.WithExpectedDatasets(expectedDatasets),
+		fields.WithDatasetMismatchAsWarning(r.globalTestConfig.AllowDatasetMismatch),
+		fields.WithStrictExpectedDatasets(r.globalTestConfig.StrictExpectedDatasets),
+		fields.WithEnabledImportAllECSSChema(true),
+		fields.WithDisableNormalization(scenario.syntheticEnabled),
+		fields.WithIndexMode(scenario.indexMode),
+		fields.WithExplanations(r.explain),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating fields validator for data stream failed (path: %s): %w", r.dataStreamPath, err)
+	}
+	return fieldsValidator, nil
+}
+
+// validateScenarioMappings validates the mappings of the data stream of the scenario.
+func (r *tester) validateScenarioMappings(ctx context.Context, scenario *scenarioTest, fieldsValidator *fields.Validator) (multierror.Error, error) {
+	exceptionFields := listExceptionFields(scenario.docs, fieldsValidator)
+
+	mappingsValidator, err := fields.CreateValidatorForMappings(r.esClient,
+		fields.WithMappingValidatorFallbackSchema(fieldsValidator.Schema),
+		fields.WithMappingValidatorIndexTemplate(scenario.indexTemplateName),
+		fields.WithMappingValidatorDataStream(scenario.dataStream),
+		fields.WithMappingValidatorExceptionFields(exceptionFields),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating mappings validator for data stream failed (data stream: %s): %w", scenario.dataStream, err)
+	}
+
+	errs := validateMappings(ctx, mappingsValidator)
+	if r.mappingsReport {
+		err := writeMappingsReport(ctx, mappingsValidator, errs, scenario.dataStream)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+// writeMappingsReport writes the comparison of the expected and actual mappings of the data stream
+// as a JSON file in the test results directory.
+func writeMappingsReport(ctx context.Context, mappingsValidator *fields.MappingValidator, errs multierror.Error, dataStream string) error {
+	report, err := mappingsValidator.MappingsReport(ctx, errs)
+	if err != nil {
+		return fmt.Errorf("failed to build mappings report (data stream: %s): %w", dataStream, err)
+	}
+	d, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mappings report (data stream: %s): %w", dataStream, err)
+	}
+
+	buildDir, err := builder.BuildDirectory()
+	if err != nil {
+		return fmt.Errorf("locating build directory failed: %w", err)
+	}
+	reportsDir := filepath.Join(buildDir, "test-results")
+	err = os.MkdirAll(reportsDir, 0755)
+	if err != nil {
+		return fmt.Errorf("could not create test results directory: %w", err)
+	}
+	reportPath := filepath.Join(reportsDir, fmt.Sprintf("mappings-%s.json", dataStream))
+	err = os.WriteFile(reportPath, d, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write mappings report: %w", err)
+	}
+	logger.Infof("Mappings report written to %s", reportPath)
+	return nil
+}
+
+func (r *tester) runTest(ctx context.Context, config *testConfig, stackConfig stack.Config, svcInfo servicedeployer.ServiceInfo) (results []testrunner.TestResult, err error) {
 	result := r.newResult(config.Name())
 
 	if skip := testrunner.AnySkipConfig(config.Skip, r.globalTestConfig.Skip); skip != nil {
 		logger.Warnf("skipping %s test for %s/%s: %s (details: %s)",
 			TestType, r.testFolder.Package, r.testFolder.DataStream,
 			skip.Reason, skip.Link)
+		if r.withCoverage {
+			// Report the files of skipped tests as not covered, so they are still accounted for in coverage totals.
+			coverage, err := r.generateCoverageReport(result.CoveragePackageName(), false)
+			if err != nil {
+				return result.WithErrorf("coverage report generation failed: %w", err)
+			}
+			result = result.WithCoverage(coverage)
+		}
 		return result.WithSkip(skip)
 	}
 
 	logger.Debugf("running test with configuration '%s'", config.Name())
 
-	scenario, err := r.prepareScenario(ctx, config, stackConfig, svcInfo)
+	// The after test hook is run as soon as the before test hook has been run while preparing
+	// the scenario, also if the preparation fails later.
+	hooks := newTestHooks(r.packageRootPath, r.globalTestConfig)
+	defer func() {
+		results, err = hooks.runAfterTest(ctx, results, err)
+	}()
+
+	scenario, err := r.prepareScenario(ctx, config, stackConfig, svcInfo, hooks)
 	if err != nil {
+		r.collectAgentDiagnostics(ctx)
 		return result.WithError(err)
 	}
 
 	if dump, ok := os.LookupEnv(dumpScenarioDocsEnv); ok && dump != "" {
-		err := dumpScenarioDocs(scenario.docs)
+		err := dumpScenarioDocs(scenario.docs, dumpScenarioDocsLimit(dump))
 		if err != nil {
 			return nil, fmt.Errorf("failed to dump scenario docs: %w", err)
 		}
 	}
 
-	return r.validateTestScenario(ctx, result, scenario, config)
+	if r.exportDocs != "" {
+		err := r.exportScenarioDocs(scenario.docs)
+		if err != nil {
+			return result.WithErrorf("failed to export documents: %w", err)
+		}
+	}
+
+	results, err = r.validateTestScenario(ctx, result, scenario, config)
+	if err != nil || anyTestResultFailed(results) {
+		r.collectAgentDiagnostics(ctx)
+	}
+	return results, err
+}
+
+// collectAgentDiagnostics collects a diagnostics bundle from the deployed agent, if requested and
+// supported by the agent deployer. Errors are logged, so they don't hide the failure of the test.
+func (r *tester) collectAgentDiagnostics(ctx context.Context) {
+	if !r.diagnosticsOnFailure || r.deployedAgent == nil {
+		return
+	}
+	collector, ok := r.deployedAgent.(agentdeployer.DiagnosticsCollector)
+	if !ok {
+		logger.Debug("Agent deployer doesn't support collecting diagnostics")
+		return
+	}
+
+	buildDir, err := builder.BuildDirectory()
+	if err != nil {
+		logger.Errorf("locating build directory failed: %v", err)
+		return
+	}
+
+	bundlePath, err := collector.CollectDiagnostics(ctx, filepath.Join(buildDir, "agent-diagnostics"))
+	if err != nil {
+		logger.Errorf("can't collect agent diagnostics: %v", err)
+		return
+	}
+	logger.Infof("Agent diagnostics written to %s", bundlePath)
+}
+
+func anyTestResultFailed(results []testrunner.TestResult) bool {
+	return slices.ContainsFunc(results, func(result testrunner.TestResult) bool {
+		return result.FailureMsg != "" || result.ErrorMsg != ""
+	})
+}
+
+// exportIndexTemplate writes the resolved index template, including the settings and mappings
+// from its component templates, to a file in the given directory.
+func (r *tester) exportIndexTemplate(ctx context.Context, indexTemplateName, dir string) error {
+	resp, err := r.esAPI.Indices.SimulateTemplate(
+		r.esAPI.Indices.SimulateTemplate.WithContext(ctx),
+		r.esAPI.Indices.SimulateTemplate.WithName(indexTemplateName),
+	)
+	if err != nil {
+		return fmt.Errorf("could not simulate index template %s: %w", indexTemplateName, err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return fmt.Errorf("could not simulate index template %s: %s", indexTemplateName, resp.String())
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response body: %w", err)
+	}
+	var template bytes.Buffer
+	err = json.Indent(&template, body, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not format index template %s: %w", indexTemplateName, err)
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("could not create directory (path: %s): %w", dir, err)
+	}
+	path := filepath.Join(dir, indexTemplateName+".json")
+	logger.Infof("Exporting index template %s to %s", indexTemplateName, path)
+	err = os.WriteFile(path, append(template.Bytes(), '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write index template (path: %s): %w", path, err)
+	}
+	return nil
 }
 
-func dumpScenarioDocs(docs any) error {
+// dumpScenarioDocsLimit returns the maximum number of documents to dump, as configured in the
+// value of the dump environment variable. Non-numeric values dump all the documents.
+func dumpScenarioDocsLimit(value string) int {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// dumpScenarioDocs dumps the documents to a file. If limit is greater than zero, only the first
+// limit documents are dumped.
+func dumpScenarioDocs(docs []common.MapStr, limit int) error {
+	if limit > 0 && len(docs) > limit {
+		logger.Debugf("Dumping %d of %d scenario documents", limit, len(docs))
+		docs = docs[:limit]
+	}
+
 	timestamp := time.Now().Format("20060102150405")
 	path := filepath.Join(os.TempDir(), fmt.Sprintf("elastic-package-test-docs-dump-%s.json", timestamp))
 	f, err := os.Create(path)
@@ -1900,7 +2563,7 @@ func createInputPackageDatastream(
 	streamInput := policyTemplate.Input
 	r.Inputs[0].Type = streamInput
 
-	dataset := fmt.Sprintf("%s.%s", pkg.Name, policyTemplate.Name)
+	dataset := inputPackageDataset(pkg.Name, policyTemplate.Name, config)
 	streams := []kibana.Stream{
 		{
 			ID:      fmt.Sprintf("%s-%s.%s", streamInput, pkg.Name, policyTemplate.Name),
@@ -1934,6 +2597,16 @@ func createInputPackageDatastream(
 	return r
 }
 
+// inputPackageDataset returns the default dataset used for input packages, including
+// the suffix configured in the test, if any.
+func inputPackageDataset(pkgName, policyTemplateName string, config testConfig) string {
+	dataset := fmt.Sprintf("%s.%s", pkgName, policyTemplateName)
+	if config.DatasetSuffix != "" {
+		dataset = dataset + "." + config.DatasetSuffix
+	}
+	return dataset
+}
+
 func setKibanaVariables(definitions []packages.Variable, values common.MapStr) kibana.Vars {
 	vars := kibana.Vars{}
 	for _, definition := range definitions {
@@ -2076,6 +2749,9 @@ func (r *tester) checkTransforms(ctx context.Context, config *testConfig, pkgMan
 		}
 
 		logger.Debugf("checking transform %q", transform.Name)
+		if transform.Definition.Meta.FleetTransformVersion == "" {
+			return fmt.Errorf("transform %q doesn't define _meta.fleet_transform_version, its ID can't be resolved", transform.Name)
+		}
 
 		// IDs format is: "<type>-<package>.<transform>-<namespace>-<version>"
 		// For instance: "logs-ti_anomali.latest_ioc-default-0.1.0"
@@ -2085,7 +2761,11 @@ func (r *tester) checkTransforms(ctx context.Context, config *testConfig, pkgMan
 			transform.Name,
 			transform.Definition.Meta.FleetTransformVersion,
 		)
-		transformId, err := r.getTransformId(ctx, transformPattern)
+		waitForTransformTimeout := waitForTransformDefaultTimeout
+		if config.WaitForTransformTimeout > 0 {
+			waitForTransformTimeout = config.WaitForTransformTimeout
+		}
+		transformId, err := r.waitForTransformId(ctx, transformPattern, waitForTransformTimeout)
 		if err != nil {
 			return fmt.Errorf("failed to determine transform ID: %w", err)
 		}
@@ -2111,17 +2791,81 @@ func (r *tester) checkTransforms(ctx context.Context, config *testConfig, pkgMan
 		if err != nil {
 			return fmt.Errorf("creating fields validator for data stream failed (path: %s): %w", transformRootPath, err)
 		}
-		if errs := validateFields(transformDocs, fieldsValidator); len(errs) > 0 {
+		if errs := validateFields(transformDocs, fieldsValidator, false); len(errs) > 0 {
 			return testrunner.ErrTestCaseFailed{
 				Reason:  fmt.Sprintf("errors found in documents of preview for transform %s for data stream %s", transformId, dataStream),
 				Details: errs.Error(),
 			}
 		}
+
+		if r.fieldValidationMethod == allMethods || r.fieldValidationMethod == mappingsMethod {
+			errs, err := r.validateTransformMappings(ctx, transformId, transformDocs, fieldsValidator)
+			if err != nil {
+				return fmt.Errorf("failed to validate mappings of transform %q: %w", transformId, err)
+			}
+			if len(errs) > 0 {
+				return testrunner.ErrTestCaseFailed{
+					Reason:  fmt.Sprintf("errors found in mappings of destination index of transform %s for data stream %s", transformId, dataStream),
+					Details: errs.Error(),
+				}
+			}
+		}
 	}
 
 	return nil
 }
 
+// validateTransformMappings compares the mappings of the destination index of the transform with
+// the fields defined for the transform, to detect drifts between the definitions and the mappings
+// installed by the index template of the destination.
+func (r *tester) validateTransformMappings(ctx context.Context, transformId string, transformDocs []common.MapStr, fieldsValidator *fields.Validator) (multierror.Error, error) {
+	destIndex, err := r.getTransformDestIndex(ctx, transformId)
+	if err != nil {
+		return nil, err
+	}
+	indexTemplateName, err := r.esClient.MatchingIndexTemplate(ctx, destIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find index template of destination index %q: %w", destIndex, err)
+	}
+
+	logger.Debugf("validating mappings of destination index %q of transform %q (index template: %s)", destIndex, transformId, indexTemplateName)
+	mappingsValidator, err := fields.CreateValidatorForMappings(r.esClient,
+		fields.WithMappingValidatorFallbackSchema(fieldsValidator.Schema),
+		fields.WithMappingValidatorIndexTemplate(indexTemplateName),
+		fields.WithMappingValidatorDataStream(destIndex),
+		fields.WithMappingValidatorExceptionFields(listExceptionFields(transformDocs, fieldsValidator)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating mappings validator for destination index failed (index: %s): %w", destIndex, err)
+	}
+	return validateMappings(ctx, mappingsValidator), nil
+}
+
+// waitForTransformId waits till a transform matching the pattern is found. Fleet installs transforms
+// asynchronously, so they may not be available yet when the package is installed.
+func (r *tester) waitForTransformId(ctx context.Context, transformPattern string, timeout time.Duration) (string, error) {
+	var transformId string
+	found, err := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		transformId, err = r.getTransformId(ctx, transformPattern)
+		if errors.Is(err, errTransformNotFound) {
+			logger.Debugf("transform with pattern %q not found yet", transformPattern)
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}, 1*time.Second, timeout)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("%w with pattern %q after %s", errTransformNotFound, transformPattern, timeout)
+	}
+	return transformId, nil
+}
+
 func (r *tester) getTransformId(ctx context.Context, transformPattern string) (string, error) {
 	resp, err := r.esAPI.TransformGetTransform(
 		r.esAPI.TransformGetTransform.WithContext(ctx),
@@ -2147,7 +2891,7 @@ func (r *tester) getTransformId(ctx context.Context, transformPattern string) (s
 	case err != nil:
 		return "", fmt.Errorf("failed to decode response: %w", err)
 	case len(transforms.Transforms) == 0:
-		return "", fmt.Errorf("no transform found with pattern %q", transformPattern)
+		return "", fmt.Errorf("%w with pattern %q", errTransformNotFound, transformPattern)
 	case len(transforms.Transforms) > 1:
 		return "", fmt.Errorf("multiple transforms (%d) found with pattern %q", len(transforms.Transforms), transformPattern)
 	}
@@ -2158,6 +2902,41 @@ func (r *tester) getTransformId(ctx context.Context, transformPattern string) (s
 	return id, nil
 }
 
+// getTransformDestIndex returns the destination index of an installed transform. It is read from the
+// installed transform because Fleet may modify the destination defined in the package.
+func (r *tester) getTransformDestIndex(ctx context.Context, transformId string) (string, error) {
+	resp, err := r.esAPI.TransformGetTransform(
+		r.esAPI.TransformGetTransform.WithContext(ctx),
+		r.esAPI.TransformGetTransform.WithTransformID(transformId),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to get transform %q: %s", transformId, resp.String())
+	}
+
+	var transforms struct {
+		Transforms []struct {
+			Dest struct {
+				Index string `json:"index"`
+			} `json:"dest"`
+		} `json:"transforms"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&transforms)
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	case len(transforms.Transforms) != 1:
+		return "", fmt.Errorf("exactly 1 transform was expected with ID %q, got %d", transformId, len(transforms.Transforms))
+	case transforms.Transforms[0].Dest.Index == "":
+		return "", fmt.Errorf("empty destination index found in transform %q", transformId)
+	}
+	return transforms.Transforms[0].Dest.Index, nil
+}
+
 func (r *tester) previewTransform(ctx context.Context, transformId string) ([]common.MapStr, error) {
 	resp, err := r.esAPI.TransformPreviewTransform(
 		r.esAPI.TransformPreviewTransform.WithContext(ctx),
@@ -2219,11 +2998,25 @@ func filterIndependentAgents(allAgents []kibana.Agent, agentInfo agentdeployer.A
 			continue
 		}
 
+		if !hasAllTags(agent, agentInfo.Agent.Tags) {
+			continue
+		}
+
 		filtered = append(filtered, agent)
 	}
 	return filtered
 }
 
+// hasAllTags checks if the agent has all the given tags.
+func hasAllTags(agent kibana.Agent, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(agent.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
 func writeSampleEvent(path string, doc common.MapStr, specVersion semver.Version) error {
 	jsonFormatter := formatter.JSONFormatterBuilder(specVersion)
 	body, err := jsonFormatter.Encode(doc)
@@ -2263,19 +3056,43 @@ func validateFailureStore(failureStore []failureStoreDocument) error {
 	return nil
 }
 
-func validateFields(docs []common.MapStr, fieldsValidator *fields.Validator) multierror.Error {
-	var multiErr multierror.Error
-	for _, doc := range docs {
-		if message, err := doc.GetValue("error.message"); err != common.ErrKeyNotFound {
-			multiErr = append(multiErr, fmt.Errorf("found error.message in event: %v", message))
-			continue
+// validateIndexTemplatePriority checks that the index template has a priority higher than the
+// built-in templates of Elasticsearch, so they don't take precedence over it.
+func validateIndexTemplatePriority(indexTemplate *ingest.IndexTemplate) error {
+	if priority := indexTemplate.Priority(); priority < minIndexTemplatePriority {
+		return testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("index template %s has priority %d, expected at least %d", indexTemplate.Name(), priority, minIndexTemplatePriority),
+			Details: "built-in Elasticsearch index templates can take precedence over templates with lower priority, resulting in unexpected mappings",
 		}
+	}
+	return nil
+}
 
-		errs := fieldsValidator.ValidateDocumentMap(doc)
-		if errs != nil {
-			multiErr = append(multiErr, errs...)
-			continue
-		}
+func validateFields(docs []common.MapStr, fieldsValidator *fields.Validator, allowErrorMessage bool) multierror.Error {
+	// The validator is not modified during validation, so documents can be validated in parallel.
+	// Errors are collected per document and merged, Unique sorts them so results are deterministic.
+	docsErrs := make([]multierror.Error, len(docs))
+
+	var wg sync.WaitGroup
+	// Use channel as a semaphore to limit the number of documents validated in parallel.
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i, doc := range docs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() {
+				<-sem
+			}()
+			docsErrs[i] = validateDocumentFields(doc, fieldsValidator, allowErrorMessage)
+		}()
+	}
+	wg.Wait()
+	close(sem)
+
+	var multiErr multierror.Error
+	for _, errs := range docsErrs {
+		multiErr = append(multiErr, errs...)
 	}
 	if len(multiErr) > 0 {
 		return multiErr.Unique()
@@ -2283,6 +3100,13 @@ func validateFields(docs []common.MapStr, fieldsValidator *fields.Validator) mul
 	return nil
 }
 
+func validateDocumentFields(doc common.MapStr, fieldsValidator *fields.Validator, allowErrorMessage bool) multierror.Error {
+	if message, err := doc.GetValue("error.message"); err != common.ErrKeyNotFound && !allowErrorMessage {
+		return multierror.Error{fmt.Errorf("found error.message in event: %v", message)}
+	}
+	return fieldsValidator.ValidateDocumentMap(doc)
+}
+
 func listExceptionFields(docs []common.MapStr, fieldsValidator *fields.Validator) []string {
 	var allFields []string
 	visited := make(map[string]any)
@@ -2300,11 +3124,15 @@ func listExceptionFields(docs []common.MapStr, fieldsValidator *fields.Validator
 	return allFields
 }
 
-func validateIgnoredFields(stackVersion *semver.Version, scenario *scenarioTest, config *testConfig) error {
-	skipIgnoredFields := append([]string(nil), config.SkipIgnoredFields...)
-	if stackVersion.LessThan(semver.MustParse("8.14.0")) {
-		// Pre 8.14 Elasticsearch commonly has event.original not mapped correctly, exclude from check: https://github.com/elastic/elasticsearch/pull/106714
-		skipIgnoredFields = append(skipIgnoredFields, "event.original")
+func validateIgnoredFields(stackVersion *semver.Version, scenario *scenarioTest, config *testConfig, strict bool) error {
+	// In strict mode any ignored field is reported, the skip list and known exceptions are not honored.
+	var skipIgnoredFields []string
+	if !strict {
+		skipIgnoredFields = append(skipIgnoredFields, config.SkipIgnoredFields...)
+		if stackVersion.LessThan(semver.MustParse("8.14.0")) {
+			// Pre 8.14 Elasticsearch commonly has event.original not mapped correctly, exclude from check: https://github.com/elastic/elasticsearch/pull/106714
+			skipIgnoredFields = append(skipIgnoredFields, "event.original")
+		}
 	}
 
 	ignoredFields := make([]string, 0, len(scenario.ignoredFields))
@@ -2352,6 +3180,14 @@ func validateMappings(ctx context.Context, mappingsValidator *fields.MappingVali
 	return nil
 }
 
+// withFailureHint appends the hint provided in the test configuration, if any, to a failure reason.
+func withFailureHint(reason, hint string) string {
+	if hint == "" {
+		return reason
+	}
+	return fmt.Sprintf("%s (hint: %s)", reason, hint)
+}
+
 func assertHitCount(expected int, docs []common.MapStr) (pass bool, message string) {
 	if expected != 0 {
 		observed := len(docs)
@@ -2363,6 +3199,103 @@ func assertHitCount(expected int, docs []common.MapStr) (pass bool, message stri
 	return true, ""
 }
 
+func assertFailedCount(expected int, docs []common.MapStr, failureStore []failureStoreDocument) (pass bool, message string) {
+	if expected != 0 {
+		withErrorMessage := countDocsWithErrorMessage(docs)
+		observed := withErrorMessage + len(failureStore)
+		logger.Debugf("assert failed count expected %d, observed %d (%d with error.message, %d in the failure store)", expected, observed, withErrorMessage, len(failureStore))
+		if observed != expected {
+			return false, fmt.Sprintf("observed failed count %d (%d with error.message, %d in the failure store) did not match expected failed count %d", observed, withErrorMessage, len(failureStore), expected)
+		}
+	}
+	return true, ""
+}
+
+func countDocsWithErrorMessage(docs []common.MapStr) int {
+	count := 0
+	for _, doc := range docs {
+		if _, err := doc.GetValue("error.message"); err != common.ErrKeyNotFound {
+			count++
+		}
+	}
+	return count
+}
+
+// addFailureMessage adds a message to the failure message of the result, keeping previous failures.
+func addFailureMessage(result *testrunner.ResultComposer, message string) {
+	if result.FailureMsg != "" {
+		message = result.FailureMsg + "; " + message
+	}
+	result.FailureMsg = message
+}
+
+func assertAggregations(assertions []aggregationAssertion, docs []common.MapStr) (pass bool, message string) {
+	var failures []string
+	for _, assertion := range assertions {
+		values := numericFieldValues(assertion.Field, docs)
+		if len(values) == 0 {
+			failures = append(failures, fmt.Sprintf("no numeric values found for field %q to compute its %s", assertion.Field, assertion.Stat))
+			continue
+		}
+
+		observed := aggregate(assertion.Stat, values)
+		logger.Debugf("assert %s of field %q, observed %v over %d values", assertion.Stat, assertion.Field, observed, len(values))
+		if (assertion.GTE != nil && observed < *assertion.GTE) || (assertion.LTE != nil && observed > *assertion.LTE) {
+			failures = append(failures, fmt.Sprintf("observed %s of field %q %v is out of the expected bounds %s", assertion.Stat, assertion.Field, observed, assertion.bounds()))
+		}
+	}
+	if len(failures) > 0 {
+		return false, strings.Join(failures, "; ")
+	}
+	return true, ""
+}
+
+// numericFieldValues returns the numeric values of the field found in the documents. Values in arrays
+// are also included.
+func numericFieldValues(field string, docs []common.MapStr) []float64 {
+	var values []float64
+	for _, doc := range docs {
+		value, err := doc.GetValue(field)
+		if err != nil {
+			continue
+		}
+		elements, ok := value.([]any)
+		if !ok {
+			elements = []any{value}
+		}
+		for _, element := range elements {
+			switch element := element.(type) {
+			case float64:
+				values = append(values, element)
+			case int:
+				values = append(values, float64(element))
+			case int64:
+				values = append(values, float64(element))
+			case json.Number:
+				if f, err := element.Float64(); err == nil {
+					values = append(values, f)
+				}
+			}
+		}
+	}
+	return values
+}
+
+func aggregate(stat string, values []float64) float64 {
+	switch stat {
+	case aggregationStatMin:
+		return slices.Min(values)
+	case aggregationStatMax:
+		return slices.Max(values)
+	default:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	}
+}
+
 func (r *tester) generateTestResultFile(docs []common.MapStr, specVersion semver.Version) error {
 	if !r.generateTestResult {
 		return nil
@@ -2402,12 +3335,7 @@ func (r *tester) checkNewAgentLogs(ctx context.Context, agent agentdeployer.Depl
 		if err != nil {
 			return nil, fmt.Errorf("check log messages failed: %s", err)
 		}
-		_, err = f.Write(outputBytes)
-		if err != nil {
-			return nil, fmt.Errorf("write log messages failed: %s", err)
-		}
-
-		err = r.anyErrorMessages(f.Name(), startTesting, patternsContainer.patterns)
+		err = r.scanAgentLogs(f, outputBytes, startTesting, patternsContainer.patterns)
 		if e, ok := err.(testrunner.ErrTestCaseFailed); ok {
 			tr := testrunner.TestResult{
 				TestType:   TestType,
@@ -2432,44 +3360,161 @@ func (r *tester) checkNewAgentLogs(ctx context.Context, agent agentdeployer.Depl
 	return results, nil
 }
 
-func (r *tester) checkAgentLogs(dump []stack.DumpResult, startTesting time.Time, errorPatterns []logsByContainer) (results []testrunner.TestResult, err error) {
-	for _, patternsContainer := range errorPatterns {
-		startTime := time.Now()
+// scanAgentLogs writes the logs to the given file and looks for error messages on them. If the
+// logs are bigger than the maximum log size, they are written and scanned in chunks of complete
+// lines, so the file only grows over this size with lines longer than it.
+func (r *tester) scanAgentLogs(f *os.File, logs []byte, startTesting time.Time, errorPatterns []logsRegexp) error {
+	chunks := [][]byte{logs}
+	if r.maxLogSize > 0 && uint64(len(logs)) > r.maxLogSize {
+		chunks = splitLogs(logs, int(r.maxLogSize))
+		logger.Warnf("Elastic Agent logs (%s) exceed the maximum log size (%s), they are rotated and scanned in %d chunks",
+			humanize.Bytes(uint64(len(logs))), humanize.Bytes(r.maxLogSize), len(chunks))
+	}
 
-		serviceDumpIndex := slices.IndexFunc(dump, func(d stack.DumpResult) bool {
-			return d.ServiceName == patternsContainer.containerName
-		})
-		if serviceDumpIndex < 0 {
-			return nil, fmt.Errorf("could not find logs dump for service %s", patternsContainer.containerName)
+	var failures []testrunner.ErrTestCaseFailed
+	for _, chunk := range chunks {
+		err := f.Truncate(0)
+		if err != nil {
+			return fmt.Errorf("truncate log messages file failed: %w", err)
+		}
+		_, err = f.Seek(0, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("rewind log messages file failed: %w", err)
+		}
+		_, err = f.Write(chunk)
+		if err != nil {
+			return fmt.Errorf("write log messages failed: %w", err)
 		}
-		serviceLogsFile := dump[serviceDumpIndex].LogsFile
 
-		err = r.anyErrorMessages(serviceLogsFile, startTesting, patternsContainer.patterns)
-		if e, ok := err.(testrunner.ErrTestCaseFailed); ok {
-			tr := testrunner.TestResult{
-				TestType:   TestType,
-				Name:       fmt.Sprintf("(%s logs)", patternsContainer.containerName),
-				Package:    r.testFolder.Package,
-				DataStream: r.testFolder.DataStream,
-			}
-			tr.FailureMsg = e.Error()
-			tr.FailureDetails = e.Details
-			tr.TimeElapsed = time.Since(startTime)
-			results = append(results, tr)
+		err = r.anyErrorMessages(f.Name(), startTesting, errorPatterns)
+		var failure testrunner.ErrTestCaseFailed
+		if errors.As(err, &failure) {
+			failures = append(failures, failure)
 			continue
 		}
-
 		if err != nil {
-			return nil, fmt.Errorf("check log messages failed: %s", err)
+			return err
+		}
+	}
+
+	switch len(failures) {
+	case 0:
+		return nil
+	case 1:
+		return failures[0]
+	}
+	details := make([]string, len(failures))
+	for i, failure := range failures {
+		details[i] = failure.Details
+	}
+	return testrunner.ErrTestCaseFailed{
+		Reason:  failures[0].Reason,
+		Details: strings.Join(details, "\n"),
+	}
+}
+
+// splitLogs splits the logs in chunks of at most maxSize bytes. Chunks are split on line
+// boundaries, so lines are never cut, lines longer than maxSize are kept in their own chunk.
+func splitLogs(logs []byte, maxSize int) [][]byte {
+	var chunks [][]byte
+	for len(logs) > maxSize {
+		end := bytes.LastIndexByte(logs[:maxSize], '\n') + 1
+		if end == 0 {
+			end = bytes.IndexByte(logs, '\n') + 1
+			if end == 0 {
+				end = len(logs)
+			}
+		}
+		chunks = append(chunks, logs[:end])
+		logs = logs[end:]
+	}
+	if len(logs) > 0 {
+		chunks = append(chunks, logs)
+	}
+	return chunks
+}
+
+func (r *tester) checkAgentLogs(dump []stack.DumpResult, startTesting time.Time, errorPatterns []logsByContainer) (results []testrunner.TestResult, err error) {
+	// Logs of each container are in independent files, so they can be scanned in parallel.
+	// Containers are sorted by name so results are reported in a deterministic order.
+	patternsContainers := slices.Clone(errorPatterns)
+	slices.SortStableFunc(patternsContainers, func(a, b logsByContainer) int {
+		return strings.Compare(a.containerName, b.containerName)
+	})
+
+	type containerResult struct {
+		result *testrunner.TestResult
+		err    error
+	}
+	containerResults := make([]containerResult, len(patternsContainers))
+
+	var wg sync.WaitGroup
+	// Use channel as a semaphore to limit the number of files scanned in parallel.
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i, patternsContainer := range patternsContainers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() {
+				<-sem
+			}()
+			result, err := r.checkContainerLogs(dump, startTesting, patternsContainer)
+			containerResults[i] = containerResult{result: result, err: err}
+		}()
+	}
+	wg.Wait()
+	close(sem)
+
+	for _, containerResult := range containerResults {
+		if containerResult.err != nil {
+			return nil, containerResult.err
+		}
+		if containerResult.result != nil {
+			results = append(results, *containerResult.result)
 		}
 	}
 	return results, nil
 }
 
+func (r *tester) checkContainerLogs(dump []stack.DumpResult, startTesting time.Time, patternsContainer logsByContainer) (*testrunner.TestResult, error) {
+	startTime := time.Now()
+
+	serviceDumpIndex := slices.IndexFunc(dump, func(d stack.DumpResult) bool {
+		return d.ServiceName == patternsContainer.containerName
+	})
+	if serviceDumpIndex < 0 {
+		return nil, fmt.Errorf("could not find logs dump for service %s", patternsContainer.containerName)
+	}
+	serviceLogsFile := dump[serviceDumpIndex].LogsFile
+
+	err := r.anyErrorMessages(serviceLogsFile, startTesting, patternsContainer.patterns)
+	if e, ok := err.(testrunner.ErrTestCaseFailed); ok {
+		tr := testrunner.TestResult{
+			TestType:   TestType,
+			Name:       fmt.Sprintf("(%s logs)", patternsContainer.containerName),
+			Package:    r.testFolder.Package,
+			DataStream: r.testFolder.DataStream,
+		}
+		tr.FailureMsg = e.Error()
+		tr.FailureDetails = e.Details
+		tr.TimeElapsed = time.Since(startTime)
+		return &tr, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("check log messages failed: %s", err)
+	}
+	return nil, nil
+}
+
 func (r *tester) anyErrorMessages(logsFilePath string, startTime time.Time, errorPatterns []logsRegexp) error {
 	var multiErr multierror.Error
 	processLog := func(log stack.LogLine) error {
 		for _, pattern := range errorPatterns {
+			if pattern.level != "" && !strings.EqualFold(pattern.level, log.LogLevel) {
+				continue
+			}
 			if !pattern.includes.MatchString(log.Message) {
 				continue
 			}
@@ -2484,7 +3529,11 @@ func (r *tester) anyErrorMessages(logsFilePath string, startTime time.Time, erro
 				continue
 			}
 
-			multiErr = append(multiErr, fmt.Errorf("found error %q", log.Message))
+			found := "error"
+			if pattern.level == logLevelWarning {
+				found = "warning"
+			}
+			multiErr = append(multiErr, fmt.Errorf("found %s %q (%s)", found, log.Message, pattern.describeMatch()))
 		}
 		return nil
 	}
@@ -2505,7 +3554,9 @@ func (r *tester) anyErrorMessages(logsFilePath string, startTime time.Time, erro
 	return nil
 }
 
-func (r *tester) generateCoverageReport(pkgName string) (testrunner.CoverageReport, error) {
+// generateCoverageReport generates a coverage report that includes the manifests and field files in the
+// package or data stream, marked as covered or uncovered depending on the given value.
+func (r *tester) generateCoverageReport(pkgName string, covered bool) (testrunner.CoverageReport, error) {
 	dsPattern := "*"
 	if r.dataStreamManifest != nil && r.dataStreamManifest.Name != "" {
 		dsPattern = r.dataStreamManifest.Name
@@ -2519,5 +3570,5 @@ func (r *tester) generateCoverageReport(pkgName string) (testrunner.CoverageRepo
 		filepath.Join(r.packageRootPath, "data_stream", dsPattern, "fields", "*.yml"),
 	}
 
-	return testrunner.GenerateBaseFileCoverageReportGlob(pkgName, patterns, r.coverageType, true)
+	return testrunner.GenerateBaseFileCoverageReportGlob(pkgName, patterns, r.coverageType, covered)
 }
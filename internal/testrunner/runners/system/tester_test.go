@@ -6,19 +6,33 @@ package system
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
+	"github.com/elastic/elastic-package/internal/agentdeployer"
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/elasticsearch/ingest"
 	estest "github.com/elastic/elastic-package/internal/elasticsearch/test"
+	"github.com/elastic/elastic-package/internal/fields"
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+	"github.com/elastic/elastic-package/internal/kibana"
 	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
 	"github.com/elastic/elastic-package/internal/stack"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
@@ -143,8 +157,9 @@ func TestFindPolicyTemplateForInput(t *testing.T) {
 	ds := packages.DataStreamManifest{
 		Name: dataStreamName,
 		Streams: []struct {
-			Input string              `config:"input" json:"input" yaml:"input"`
-			Vars  []packages.Variable `config:"vars" json:"vars" yaml:"vars"`
+			Input        string              `config:"input" json:"input" yaml:"input"`
+			TemplatePath string              `config:"template_path" json:"template_path" yaml:"template_path"`
+			Vars         []packages.Variable `config:"vars" json:"vars" yaml:"vars"`
 		}{
 			{Input: inputName},
 		},
@@ -203,7 +218,7 @@ func TestCheckAgentLogs(t *testing.T) {
 				"test case failed: one or more errors found while examining service.log",
 			},
 			expectedDetails: []string{
-				"[0] found error \"something\"\n[1] found error \"foo\"",
+				"[0] found error \"something\" (matched pattern \".*\", no exclusions defined)\n[1] found error \"foo\" (matched pattern \".*\", no exclusions defined)",
 			},
 		},
 		{
@@ -227,7 +242,7 @@ func TestCheckAgentLogs(t *testing.T) {
 			},
 			expectedErrors:  1,
 			expectedMessage: []string{"test case failed: one or more errors found while examining service.log"},
-			expectedDetails: []string{"[0] found error \"foo\""},
+			expectedDetails: []string{"[0] found error \"foo\" (matched pattern \".*\", no exclusions defined)"},
 		},
 		{
 			testName:     "all logs older",
@@ -272,7 +287,7 @@ func TestCheckAgentLogs(t *testing.T) {
 			},
 			expectedErrors:  1,
 			expectedMessage: []string{"test case failed: one or more errors found while examining service.log"},
-			expectedDetails: []string{"[0] found error \"something\""},
+			expectedDetails: []string{"[0] found error \"something\" (matched pattern \".*thing$\", no exclusions defined)"},
 		},
 		{
 			testName:     "logs found for two services",
@@ -308,13 +323,14 @@ func TestCheckAgentLogs(t *testing.T) {
 				},
 			},
 			expectedErrors: 2,
+			// Results are sorted by container name.
 			expectedMessage: []string{
-				"test case failed: one or more errors found while examining service.log",
 				"test case failed: one or more errors found while examining external.log",
+				"test case failed: one or more errors found while examining service.log",
 			},
 			expectedDetails: []string{
-				"[0] found error \"service: something\"",
-				"[0] found error \"external: foo\"\n[1] found error \"external: any other foo\"",
+				"[0] found error \"external: foo\" (matched pattern \" foo$\", no exclusions defined)\n[1] found error \"external: any other foo\" (matched pattern \" foo$\", no exclusions defined)",
+				"[0] found error \"service: something\" (matched pattern \".*thing$\", no exclusions defined)",
 			},
 		},
 		{
@@ -345,7 +361,32 @@ func TestCheckAgentLogs(t *testing.T) {
 			},
 			expectedErrors:  1,
 			expectedMessage: []string{"test case failed: one or more errors found while examining service.log"},
-			expectedDetails: []string{"[0] found error \"something\"\n[1] found error \"foo bar\""},
+			expectedDetails: []string{"[0] found error \"something\" (matched pattern \"^(something|foo)\", not excluded by any of [\"foo$\", \"42\"])\n[1] found error \"foo bar\" (matched pattern \"^(something|foo)\", not excluded by any of [\"foo$\", \"42\"])"},
+		},
+		{
+			testName:     "filter logs by level",
+			startingTime: "2023-05-15T12:00:00.000Z",
+			errorPatterns: []logsByContainer{
+				logsByContainer{
+					containerName: "service",
+					patterns: []logsRegexp{
+						logsRegexp{
+							includes: regexp.MustCompile(".*"),
+							level:    logLevelWarning,
+						},
+					},
+				},
+			},
+			sampleLogs: map[string][]string{
+				"service": []string{
+					`service_1 | {"@timestamp": "2023-05-15T13:00:00.000Z", "log.level": "info", "message": "something"}`,
+					`service_1 | {"@timestamp": "2023-05-15T13:00:05.000Z", "log.level": "warn", "message": "foo"}`,
+					`service_1 | {"@timestamp": "2023-05-15T13:00:10.000Z", "log.level": "error", "message": "bar"}`,
+				},
+			},
+			expectedErrors:  1,
+			expectedMessage: []string{"test case failed: one or more errors found while examining service.log"},
+			expectedDetails: []string{"[0] found warning \"foo\" (matched pattern \".*\", no exclusions defined)"},
 		},
 	}
 
@@ -398,18 +439,20 @@ func TestCheckAgentLogs(t *testing.T) {
 	}
 }
 
-func TestIsSyntheticSourceModeEnabled(t *testing.T) {
+func TestGetDataStreamModes(t *testing.T) {
 	cases := []struct {
 		title          string
 		record         string
 		dataStreamName string
 		expected       bool
+		indexMode      string
 	}{
 		{
 			title:          "no synthetics",
 			record:         "testdata/elasticsearch-8-mock-synthetic-mode-nginx",
 			dataStreamName: "logs-nginx.access-12345",
 			expected:       false,
+			indexMode:      "standard",
 		},
 		{
 			// This test case is generated with -U stack.logsdb_enabled=true
@@ -417,12 +460,14 @@ func TestIsSyntheticSourceModeEnabled(t *testing.T) {
 			record:         "testdata/elasticsearch-8-mock-synthetic-mode-nginx-logsdb",
 			dataStreamName: "logs-nginx.access-12345",
 			expected:       true,
+			indexMode:      "logs",
 		},
 		{
 			title:          "time_series mode",
 			record:         "testdata/elasticsearch-8-mock-synthetic-mode-couchdb",
 			dataStreamName: "metrics-couchdb.server-12345",
 			expected:       true,
+			indexMode:      "time_series",
 		},
 		{
 			// This test case is generated with the logs_synthetic_mode test package from the Package Spec.
@@ -430,15 +475,575 @@ func TestIsSyntheticSourceModeEnabled(t *testing.T) {
 			record:         "testdata/elasticsearch-8-mock-synthetic-mode-dummy",
 			dataStreamName: "logs-logs_synthetic_mode.synthetic-12345",
 			expected:       true,
+			indexMode:      "standard",
 		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.title, func(t *testing.T) {
 			client := estest.NewClient(t, c.record)
-			enabled, err := isSyntheticSourceModeEnabled(context.Background(), client.API, c.dataStreamName)
+			modes, err := getDataStreamModes(context.Background(), client.API, c.dataStreamName)
 			require.NoError(t, err)
-			assert.Equal(t, c.expected, enabled)
+			assert.Equal(t, c.expected, modes.syntheticSource)
+			assert.Equal(t, c.indexMode, modes.indexMode)
+		})
+	}
+}
+
+// createTestValidator creates a fields validator for the given definitions of fields, without
+// dependency management.
+func createTestValidator(t *testing.T, definitions string) *fields.Validator {
+	t.Helper()
+	fieldsParentDir := t.TempDir()
+	filestest.WriteFile(t, fieldsParentDir, "fields/fields.yml", definitions)
+	validator, err := fields.CreateValidatorForDirectory(fieldsParentDir, fields.WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	return validator
+}
+
+func TestValidateFieldsDeterministicOrder(t *testing.T) {
+	validator := createTestValidator(t, `
+- name: message
+  type: keyword
+- name: count
+  type: long
+`)
+
+	var docs []common.MapStr
+	var expected []string
+	for i := 0; i < 50; i++ {
+		doc := common.MapStr{"message": "test"}
+		switch i % 3 {
+		case 1:
+			field := fmt.Sprintf("undefined_%02d", i)
+			doc[field] = "value"
+			expected = append(expected, fmt.Sprintf(`field %q is undefined`, field))
+		case 2:
+			doc["error"] = common.MapStr{"message": fmt.Sprintf("failure %02d", i)}
+			expected = append(expected, fmt.Sprintf("found error.message in event: failure %02d", i))
+		}
+		docs = append(docs, doc)
+	}
+
+	// Errors are reported sorted, independently of the order in which documents are validated.
+	sort.Strings(expected)
+
+	errs := validateFields(docs, validator, false)
+	require.Len(t, errs, len(expected))
+	for i, err := range errs {
+		assert.Equal(t, expected[i], err.Error())
+	}
+
+	assert.Nil(t, validateFields(docs[:1], validator, false))
+}
+
+func TestValidateIgnoredFields(t *testing.T) {
+	scenario := &scenarioTest{
+		ignoredFields: []string{"event.original", "message"},
+	}
+	config := &testConfig{
+		SkipIgnoredFields: []string{"message"},
+	}
+
+	cases := []struct {
+		title        string
+		stackVersion string
+		strict       bool
+		expectError  bool
+	}{
+		{title: "skipped fields", stackVersion: "8.13.0", expectError: false},
+		{title: "event.original not skipped in recent versions", stackVersion: "8.14.0", expectError: true},
+		{title: "strict mode ignores skip list", stackVersion: "8.13.0", strict: true, expectError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := validateIgnoredFields(semver.MustParse(c.stackVersion), scenario, config, c.strict)
+			if c.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
+
+func TestAnyTestResultFailed(t *testing.T) {
+	assert.False(t, anyTestResultFailed(nil))
+	assert.False(t, anyTestResultFailed([]testrunner.TestResult{{Name: "success"}}))
+	assert.True(t, anyTestResultFailed([]testrunner.TestResult{{Name: "success"}, {Name: "failure", FailureMsg: "failed"}}))
+	assert.True(t, anyTestResultFailed([]testrunner.TestResult{{Name: "error", ErrorMsg: "error"}}))
+}
+
+func TestSplitLogs(t *testing.T) {
+	cases := []struct {
+		title    string
+		logs     string
+		maxSize  int
+		expected []string
+	}{
+		{
+			title:    "empty logs",
+			logs:     "",
+			maxSize:  10,
+			expected: nil,
+		},
+		{
+			title:    "logs smaller than the limit",
+			logs:     "line1\nline2\n",
+			maxSize:  100,
+			expected: []string{"line1\nline2\n"},
+		},
+		{
+			title:    "split on line boundaries",
+			logs:     "line1\nline2\nline3\n",
+			maxSize:  13,
+			expected: []string{"line1\nline2\n", "line3\n"},
+		},
+		{
+			title:    "lines longer than the limit",
+			logs:     "a very long line\nshort\n",
+			maxSize:  8,
+			expected: []string{"a very long line\n", "short\n"},
+		},
+		{
+			title:    "last line longer than the limit",
+			logs:     "short\na very long line",
+			maxSize:  8,
+			expected: []string{"short\n", "a very long line"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			chunks := splitLogs([]byte(c.logs), c.maxSize)
+			var result []string
+			for _, chunk := range chunks {
+				// Only chunks with a single line can be longer than the limit.
+				if len(chunk) > c.maxSize {
+					assert.NotContains(t, strings.TrimSuffix(string(chunk), "\n"), "\n")
+				}
+				result = append(result, string(chunk))
+			}
+			assert.Equal(t, c.expected, result)
+		})
+	}
+}
+
+func TestCreateInputPackageDatastreamWithDatasetSuffix(t *testing.T) {
+	pkg := packages.PackageManifest{Name: "sql_input", Title: "SQL Input", Version: "1.0.0"}
+	policyTemplate := packages.PolicyTemplate{Name: "sql_query", Input: "sql/metrics", Type: "metrics"}
+	policy := kibana.Policy{ID: "policy-id", Namespace: "ep"}
+
+	ds := createInputPackageDatastream(policy, pkg, policyTemplate, testConfig{}, "suffix")
+	assert.Equal(t, "sql_input.sql_query", ds.Inputs[0].Streams[0].DataStream.Dataset)
+
+	ds = createInputPackageDatastream(policy, pkg, policyTemplate, testConfig{DatasetSuffix: "run_42"}, "suffix")
+	stream := ds.Inputs[0].Streams[0]
+	assert.Equal(t, "sql_input.sql_query.run_42", stream.DataStream.Dataset)
+	value, err := stream.Vars["data_stream.dataset"].Value.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `"sql_input.sql_query.run_42"`, string(value))
+}
+
+func TestNewConfigDatasetSuffix(t *testing.T) {
+	cases := []struct {
+		suffix      string
+		expectError bool
+	}{
+		{suffix: "run_42"},
+		{suffix: "{{TEST_RUN_ID}}"},
+		{suffix: "with-dash", expectError: true},
+		{suffix: "Upper", expectError: true},
+		{suffix: strings.Repeat("a", maxDatasetSuffixLength+1), expectError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.suffix, func(t *testing.T) {
+			configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", fmt.Sprintf("dataset_suffix: %q\n", c.suffix))
+
+			var svcInfo servicedeployer.ServiceInfo
+			svcInfo.Test.RunID = "12345"
+			config, err := newConfig(configPath, svcInfo, "", nil)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, config.DatasetSuffix)
+			assert.NotContains(t, config.DatasetSuffix, "{{")
+		})
+	}
+}
+
+func TestNewConfigVariables(t *testing.T) {
+	configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", `
+vars:
+  url: '{{var "base_url"}}/api'
+  run_id: '{{TEST_RUN_ID}}'
+`)
+
+	var svcInfo servicedeployer.ServiceInfo
+	svcInfo.Test.RunID = "12345"
+
+	config, err := newConfig(configPath, svcInfo, "", map[string]string{"base_url": "http://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/api", config.Vars["url"])
+	assert.Equal(t, "12345", config.Vars["run_id"])
+
+	_, err = newConfig(configPath, svcInfo, "", map[string]string{"other": "value"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `undefined variable "base_url"`)
+}
+
+func TestNewConfigExpectedDatasetsFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filestest.WriteFile(t, dir, "test-default-config.yml", "expected_datasets_file: datasets.txt\n")
+	filestest.WriteFile(t, dir, "datasets.txt", `
+# Datasets produced by the reroute processors.
+cisco.asa
+
+  fortinet.firewall
+{{labels.dataset}}
+`)
+
+	config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cisco.asa", "fortinet.firewall", "{{labels.dataset}}"}, config.ExpectedDatasets)
+
+	filestest.WriteFile(t, dir, "datasets.txt", "# No datasets.\n")
+	_, err = newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+	assert.Error(t, err)
+}
+
+func TestNewConfigClusterSettings(t *testing.T) {
+	configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", `
+cluster_settings:
+  cluster.logsdb.enabled: true
+  indices:
+    lifecycle.poll_interval: 1m
+`)
+
+	config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+	require.NoError(t, err)
+
+	expected := common.MapStr{
+		"cluster.logsdb.enabled":          true,
+		"indices.lifecycle.poll_interval": "1m",
+	}
+	assert.Equal(t, expected, config.ClusterSettings.Flatten())
+}
+
+func TestNewConfigAggregations(t *testing.T) {
+	cases := []struct {
+		title       string
+		assert      string
+		expectError bool
+	}{
+		{title: "valid", assert: "{field: system.cpu.total.pct, stat: max, lte: 1}"},
+		{title: "both bounds", assert: "{field: system.cpu.total.pct, stat: avg, gte: 0, lte: 1}"},
+		{title: "missing field", assert: "{stat: max, lte: 1}", expectError: true},
+		{title: "unknown stat", assert: "{field: system.cpu.total.pct, stat: sum, lte: 1}", expectError: true},
+		{title: "missing bounds", assert: "{field: system.cpu.total.pct, stat: min}", expectError: true},
+		{title: "inverted bounds", assert: "{field: system.cpu.total.pct, stat: min, gte: 2, lte: 1}", expectError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", "assert:\n  aggregations:\n    - "+c.assert+"\n")
+
+			config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, config.Assert.Aggregations, 1)
+		})
+	}
+}
+
+func TestAssertAggregations(t *testing.T) {
+	docs := []common.MapStr{
+		{"system": common.MapStr{"cpu": common.MapStr{"total": common.MapStr{"pct": 0.2}}}},
+		{"system": common.MapStr{"cpu": common.MapStr{"total": common.MapStr{"pct": 0.4}}}},
+		{"system": common.MapStr{"cpu": common.MapStr{"total": common.MapStr{"pct": []any{0.6, 0.8}}}}},
+		{"message": "no metrics here"},
+	}
+	bound := func(v float64) *float64 { return &v }
+
+	cases := []struct {
+		title      string
+		assertions []aggregationAssertion
+		expected   string
+	}{
+		{
+			title: "within bounds",
+			assertions: []aggregationAssertion{
+				{Field: "system.cpu.total.pct", Stat: aggregationStatMin, GTE: bound(0.2)},
+				{Field: "system.cpu.total.pct", Stat: aggregationStatMax, LTE: bound(1)},
+				{Field: "system.cpu.total.pct", Stat: aggregationStatAvg, GTE: bound(0.4), LTE: bound(0.6)},
+			},
+		},
+		{
+			title: "out of bounds",
+			assertions: []aggregationAssertion{
+				{Field: "system.cpu.total.pct", Stat: aggregationStatMax, LTE: bound(0.5)},
+				{Field: "system.cpu.total.pct", Stat: aggregationStatAvg, GTE: bound(10), LTE: bound(100)},
+			},
+			expected: `observed max of field "system.cpu.total.pct" 0.8 is out of the expected bounds <= 0.5; ` +
+				`observed avg of field "system.cpu.total.pct" 0.5 is out of the expected bounds [10, 100]`,
+		},
+		{
+			title: "missing values",
+			assertions: []aggregationAssertion{
+				{Field: "system.memory.pct", Stat: aggregationStatMax, LTE: bound(1)},
+			},
+			expected: `no numeric values found for field "system.memory.pct" to compute its max`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			pass, message := assertAggregations(c.assertions, docs)
+			assert.Equal(t, c.expected == "", pass)
+			assert.Equal(t, c.expected, message)
+		})
+	}
+}
+
+func TestRenderPackagePolicy(t *testing.T) {
+	pkg := packages.PackageManifest{
+		Name:    "sql_input",
+		Title:   "SQL Input",
+		Type:    "input",
+		Version: "1.0.0",
+		PolicyTemplates: []packages.PolicyTemplate{{
+			Name:  "sql_query",
+			Input: "sql/metrics",
+			Type:  "metrics",
+			Vars:  []packages.Variable{{Name: "hosts", Type: "text"}},
+		}},
+	}
+	config := testConfig{Vars: common.MapStr{"hosts": []any{"root:test@tcp(mysql:3306)/"}}}
+	policy := kibana.Policy{ID: "policy-id", Namespace: "ep"}
+
+	ds := createPackageDatastream(policy, pkg, pkg.PolicyTemplates[0], packages.DataStreamManifest{}, config, "suffix")
+	rendered, err := renderPackagePolicy(ds)
+	require.NoError(t, err)
+
+	var decoded common.MapStr
+	require.NoError(t, yaml.Unmarshal([]byte(rendered), &decoded))
+	assert.Equal(t, "sql_input-sql_query-suffix", decoded["name"])
+	assert.Equal(t, "policy-id", decoded["policy_id"])
+	value, err := decoded.GetValue("inputs")
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "root:test@tcp(mysql:3306)/")
+	assert.Len(t, value, 1)
+}
+
+func TestValidateFieldsAllowErrorMessage(t *testing.T) {
+	validator := createTestValidator(t, `
+- name: message
+  type: keyword
+- name: error
+  type: group
+  fields:
+    - name: message
+      type: match_only_text
+`)
+
+	docs := []common.MapStr{
+		{"message": "test"},
+		{"message": "test", "error": map[string]any{"message": "failed to parse"}},
+	}
+	assert.Len(t, validateFields(docs, validator, false), 1)
+	assert.Nil(t, validateFields(docs, validator, true))
+}
+
+func TestAssertFailedCount(t *testing.T) {
+	docs := []common.MapStr{
+		{"message": "test"},
+		{"message": "test", "error": common.MapStr{"message": "failed to parse"}},
+	}
+	failureStore := []failureStoreDocument{{}, {}}
+
+	pass, _ := assertFailedCount(0, docs, failureStore)
+	assert.True(t, pass)
+
+	pass, _ = assertFailedCount(3, docs, failureStore)
+	assert.True(t, pass)
+
+	pass, message := assertFailedCount(1, docs, failureStore)
+	assert.False(t, pass)
+	assert.Equal(t, "observed failed count 3 (1 with error.message, 2 in the failure store) did not match expected failed count 1", message)
+}
+
+func TestWithFailureHint(t *testing.T) {
+	assert.Equal(t, "could not find hits in logs-test-default data stream",
+		withFailureHint("could not find hits in logs-test-default data stream", ""))
+	assert.Equal(t, "could not find hits in logs-test-default data stream (hint: did you enable the feature flag?)",
+		withFailureHint("could not find hits in logs-test-default data stream", "did you enable the feature flag?"))
+}
+
+func TestParseDataStreamName(t *testing.T) {
+	cases := []struct {
+		name              string
+		expectedType      string
+		expectedDataset   string
+		expectedNamespace string
+		expectedError     bool
+	}{
+		{name: "logs-nginx.access-ep", expectedType: "logs", expectedDataset: "nginx.access", expectedNamespace: "ep"},
+		{name: "metrics-system.cpu-12345", expectedType: "metrics", expectedDataset: "system.cpu", expectedNamespace: "12345"},
+		{name: "logs-my-dataset-default", expectedType: "logs", expectedDataset: "my-dataset", expectedNamespace: "default"},
+		{name: "logs-nginx.access", expectedError: true},
+		{name: "logs", expectedError: true},
+		{name: "logs-nginx.access-", expectedError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dataStreamType, dataset, namespace, err := parseDataStreamName(c.name)
+			if c.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expectedType, dataStreamType)
+			assert.Equal(t, c.expectedDataset, dataset)
+			assert.Equal(t, c.expectedNamespace, namespace)
+		})
+	}
+}
+
+func TestValidateIndexTemplatePriority(t *testing.T) {
+	var indexTemplate ingest.IndexTemplate
+	indexTemplate.TemplateName = "logs-nginx.access"
+
+	indexTemplate.IndexTemplate.Priority = 200
+	assert.NoError(t, validateIndexTemplatePriority(&indexTemplate))
+
+	indexTemplate.IndexTemplate.Priority = 100
+	err := validateIndexTemplatePriority(&indexTemplate)
+	var tcf testrunner.ErrTestCaseFailed
+	require.ErrorAs(t, err, &tcf)
+	assert.Equal(t, "index template logs-nginx.access has priority 100, expected at least 101", tcf.Reason)
+}
+
+func TestNewConfigBaseImages(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filestest.WriteFile(t, dir, "test-default-config.yml", `
+agent:
+  base_images:
+    - default
+    - complete
+`)
+
+	config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "complete"}, config.Agent.BaseImages)
+
+	config.BaseImageName = "complete"
+	assert.Equal(t, "default (base image: complete)", config.Name())
+
+	filestest.WriteFile(t, dir, "test-default-config.yml", `
+agent:
+  base_image: complete
+  base_images:
+    - default
+`)
+	_, err = newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+	assert.Error(t, err)
+}
+
+func TestDumpScenarioDocsLimit(t *testing.T) {
+	assert.Equal(t, 0, dumpScenarioDocsLimit("true"))
+	assert.Equal(t, 0, dumpScenarioDocsLimit("-5"))
+	assert.Equal(t, 10, dumpScenarioDocsLimit("10"))
+}
+
+func TestNewConfigWaitForDataPollInterval(t *testing.T) {
+	configPath := filestest.WriteFile(t, t.TempDir(), "test-default-config.yml", "wait_for_data_poll_interval: 10s\n")
+
+	config, err := newConfig(configPath, servicedeployer.ServiceInfo{}, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, config.WaitForDataPollInterval)
+}
+
+func TestFilterIndependentAgentsWithTags(t *testing.T) {
+	var agentInfo agentdeployer.AgentInfo
+	agentInfo.Policy.ID = "test-policy"
+	agentInfo.Agent.Tags = []string{"elastic-package", "run-1234"}
+
+	agents := []kibana.Agent{
+		{ID: "untagged", PolicyID: "test-policy", PolicyRevision: 2, Status: "online"},
+		{ID: "other-run", PolicyID: "test-policy", PolicyRevision: 2, Status: "online", Tags: []string{"elastic-package", "run-5678"}},
+		{ID: "selected", PolicyID: "test-policy", PolicyRevision: 2, Status: "online", Tags: []string{"run-1234", "elastic-package", "extra"}},
+		{ID: "other-policy", PolicyID: "other-policy", PolicyRevision: 2, Status: "online", Tags: []string{"elastic-package", "run-1234"}},
+	}
+
+	filtered := filterIndependentAgents(agents, agentInfo)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "selected", filtered[0].ID)
+
+	agentInfo.Agent.Tags = nil
+	assert.Len(t, filterIndependentAgents(agents, agentInfo), 3)
+}
+
+func TestTearDownKeptAgent(t *testing.T) {
+	// Fake Fleet API that rejects deleting policies in use by the agent.
+	agent := kibana.Agent{ID: "agent-id", PolicyID: "test-policy"}
+	policies := map[string]bool{"enroll-policy": true, "test-policy": true}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/api/fleet/agents/agent-id":
+			json.NewEncoder(w).Encode(map[string]any{"item": agent})
+		case req.Method == http.MethodPost && req.URL.Path == "/api/fleet/agents/agent-id/reassign":
+			var body struct {
+				PolicyID string `json:"policy_id"`
+			}
+			json.NewDecoder(req.Body).Decode(&body)
+			agent.PolicyID = body.PolicyID
+		case req.Method == http.MethodPost && req.URL.Path == "/api/fleet/agent_policies/delete":
+			var body struct {
+				AgentPolicyID string `json:"agentPolicyId"`
+			}
+			json.NewDecoder(req.Body).Decode(&body)
+			if body.AgentPolicyID == agent.PolicyID {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			delete(policies, body.AgentPolicyID)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	kibanaClient, err := kibana.NewClient(kibana.Address(server.URL), kibana.KnownVersion("9.0.0"), kibana.RetryMax(0))
+	require.NoError(t, err)
+
+	r := tester{
+		kibanaClient:               kibanaClient,
+		keepAgent:                  true,
+		runIndependentElasticAgent: true,
+	}
+	policyToEnroll := &kibana.Policy{ID: "enroll-policy"}
+	policyToTest := &kibana.Policy{ID: "test-policy"}
+	r.resetAgentPolicyHandler = func(ctx context.Context) error {
+		return r.resetAgentPolicy(ctx, agent, *policyToEnroll, true)
+	}
+	r.deleteTestPolicyHandler = func(ctx context.Context) error {
+		return r.deleteTestPolicies(ctx, policyToTest, policyToEnroll)
+	}
+
+	err = r.tearDownTest(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "enroll-policy", agent.PolicyID)
+	assert.Equal(t, map[string]bool{"enroll-policy": true}, policies)
+}
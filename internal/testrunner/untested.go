@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DataStreamsWithoutTests returns the names of the data streams of the package that don't have
+// system test configurations nor pipeline test cases.
+func DataStreamsWithoutTests(packageRootPath string) ([]string, error) {
+	dataStreamPaths, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("can't look for data streams: %w", err)
+	}
+
+	var untested []string
+	for _, dataStreamPath := range dataStreamPaths {
+		testsPath := filepath.Join(dataStreamPath, "_dev", "test")
+		systemTests, err := filepath.Glob(filepath.Join(testsPath, "system", "test-*-config.yml"))
+		if err != nil {
+			return nil, fmt.Errorf("can't look for system tests: %w", err)
+		}
+		if len(systemTests) > 0 {
+			continue
+		}
+		hasPipelineTests, err := hasPipelineTestCases(filepath.Join(testsPath, "pipeline"))
+		if err != nil {
+			return nil, err
+		}
+		if hasPipelineTests {
+			continue
+		}
+		untested = append(untested, filepath.Base(dataStreamPath))
+	}
+	return untested, nil
+}
+
+// hasPipelineTestCases checks if the directory contains pipeline test cases, ignoring
+// configuration files and expected results.
+func hasPipelineTestCases(path string) (bool, error) {
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("can't read pipeline tests directory (path: %s): %w", path, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, "-expected.json") || strings.HasSuffix(name, "-config.yml") {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
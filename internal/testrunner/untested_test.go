@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	filestest "github.com/elastic/elastic-package/internal/files/test"
+)
+
+func TestDataStreamsWithoutTests(t *testing.T) {
+	packageRoot := t.TempDir()
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "access", "_dev", "test", "system", "test-default-config.yml"), "")
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "error", "_dev", "test", "pipeline", "test-error.log"), "")
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "error", "_dev", "test", "pipeline", "test-error.log-expected.json"), "")
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "status", "_dev", "test", "pipeline", "test-common-config.yml"), "")
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "status", "_dev", "test", "static", "test-default-config.yml"), "")
+	filestest.WriteFile(t, packageRoot, filepath.Join("data_stream", "metrics", "manifest.yml"), "")
+
+	untested, err := DataStreamsWithoutTests(packageRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"metrics", "status"}, untested)
+}
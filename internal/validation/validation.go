@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/elastic/package-spec/v3/code/go/pkg/specerrors"
 	"github.com/elastic/package-spec/v3/code/go/pkg/validator"
@@ -105,3 +107,19 @@ func filterErrors(allErrors error, fsys fs.FS) (specerrors.FilterResult, error)
 	return result, nil
 
 }
+
+// SourceErrors returns the descriptions of the errors found when validating the package in rootPath,
+// one per validation error, with the paths of the files relative to the package root.
+func SourceErrors(rootPath string, err error) []string {
+	var errs specerrors.ValidationErrors
+	if !errors.As(err, &errs) {
+		return []string{err.Error()}
+	}
+
+	prefix := filepath.Clean(rootPath) + string(filepath.Separator)
+	descriptions := make([]string, len(errs))
+	for i, e := range errs {
+		descriptions[i] = strings.ReplaceAll(e.Error(), prefix, "")
+	}
+	return descriptions
+}
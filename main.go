@@ -13,6 +13,7 @@ import (
 
 	"github.com/elastic/elastic-package/cmd"
 	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/stack"
 )
 
 func main() {
@@ -24,6 +25,9 @@ func main() {
 	rootCmd := cmd.RootCmd()
 	rootCmd.SilenceErrors = true // Silence errors so we handle them here.
 	err = rootCmd.Execute()
+	if saveErr := stack.SaveRecordedRequests(); saveErr != nil {
+		err = errors.Join(err, saveErr)
+	}
 	if errIsInterruption(err) {
 		rootCmd.Println("interrupted")
 		os.Exit(130)